@@ -8,11 +8,13 @@ import (
 	"os"
 	"strings"
 
+	"github.com/twitchdev/twitch-cli/internal/admin"
 	"github.com/twitchdev/twitch-cli/internal/api"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/generate"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_server"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // getCmd represents the get command
@@ -21,6 +23,7 @@ var body string
 var prettyPrint bool
 var autoPaginate int = 0
 var port int
+var ssl bool
 var verbose bool
 
 var generateCount int
@@ -104,6 +107,8 @@ func init() {
 	mockCmd.AddCommand(startCmd, generateCmd)
 
 	startCmd.Flags().IntVarP(&port, "port", "p", 8080, "Defines the port that the mock API will run on.")
+	startCmd.Flags().Int("admin-port", admin.DefaultPort, "Port of the mock EventSub server's admin API, which the mock API notifies when its endpoints change state and when tokens are revoked.")
+	startCmd.Flags().BoolVar(&ssl, "ssl", false, "Serves the mock API over HTTPS, with localhost.crt and localhost.key from the application directory. They're generated, along with a local CA, if missing.")
 
 	generateCmd.Flags().IntVarP(&generateCount, "count", "c", 25, "Defines the number of fake users to generate.")
 }
@@ -145,8 +150,15 @@ func getBodyFromFile(filename string) (string, error) {
 }
 
 func mockStartRun(cmd *cobra.Command, args []string) error {
-	log.Printf("Starting mock API server on http://localhost:%v", port)
-	return mock_server.StartServer(port)
+	// Bound here rather than in init, since `twitch event` binds its own --admin-port to the same key
+	viper.BindPFlag("admin_port", cmd.Flags().Lookup("admin-port"))
+
+	scheme := "http"
+	if ssl {
+		scheme = "https"
+	}
+	log.Printf("Starting mock API server on %v://localhost:%v", scheme, port)
+	return mock_server.StartServer(port, ssl)
 }
 
 func generateMockRun(cmd *cobra.Command, args []string) error {
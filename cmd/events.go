@@ -4,7 +4,9 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/twitchdev/twitch-cli/cmd/events"
+	"github.com/twitchdev/twitch-cli/internal/admin"
 )
 
 var noConfig bool
@@ -20,6 +22,11 @@ func init() {
 	eventCmd.AddCommand(
 		events.TriggerCommand(),
 		events.RetriggerCommand(),
+		events.RevokeCommand(),
+		events.HistoryCommand(),
+		events.ExportCommand(),
+		events.ImportCommand(),
+		events.ListenCommand(),
 		events.VerifySubscriptionCommand(),
 		events.WebsocketCommand(),
 		events.StartWebsocketServerCommand(),
@@ -27,4 +34,8 @@ func init() {
 	)
 
 	eventCmd.Flags().BoolVarP(&noConfig, "no-config", "D", false, "Disables the use of the configuration, if it exists.")
+
+	// The admin API port can also be set with TWITCH_ADMIN_PORT or admin_port in the config file
+	eventCmd.PersistentFlags().Int("admin-port", admin.DefaultPort, "Port of the mock EventSub server's admin API, which is used to forward events and commands to the server.")
+	viper.BindPFlag("admin_port", eventCmd.PersistentFlags().Lookup("admin-port"))
 }
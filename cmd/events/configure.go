@@ -15,6 +15,7 @@ func ConfigureCommand() (command *cobra.Command) {
 
 	command.Flags().StringVarP(&forwardAddress, "forward-address", "F", "", "Forward address for mock event (webhook only).")
 	command.Flags().StringVarP(&secret, "secret", "s", "", "Webhook secret. If defined, signs all forwarded events with the SHA256 HMAC and must be 10-100 characters in length.")
+	command.Flags().StringVar(&retention, "retention", "", "How long fired events are kept in the events cache, as a relative time (600, 600s, 10d4h12m55s). Older events are pruned whenever a new event is fired, by the event's timestamp, so events fired with an older --timestamp are pruned by the next trigger.")
 
 	return
 }
@@ -23,5 +24,6 @@ func configureEventRun(cmd *cobra.Command, args []string) error {
 	return configure_event.ConfigureEvents(configure_event.EventConfigurationParams{
 		ForwardAddress: forwardAddress,
 		Secret:         secret,
+		Retention:      retention,
 	})
 }
@@ -0,0 +1,99 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/twitchdev/twitch-cli/internal/events/history"
+)
+
+var (
+	exportOutput    string
+	exportEvent     string
+	exportTransport string
+	exportSince     string
+	exportUntil     string
+	exportLimit     int
+)
+
+func ExportCommand() (command *cobra.Command) {
+	command = &cobra.Command{
+		Use:   "export",
+		Short: "Exports previously fired events from the events cache as NDJSON, oldest first.",
+		Args:  cobra.NoArgs,
+		RunE:  exportCmdRun,
+		Example: `  twitch event export -o events.ndjson
+  twitch event export --event channel.follow --since 2h > follows.ndjson`,
+	}
+
+	command.Flags().StringVarP(&exportOutput, "output", "o", "", "File the events are written to. Defaults to stdout.")
+	command.Flags().StringVarP(&exportEvent, "event", "e", "", "Only exports events of this topic or trigger alias, for example channel.follow or cheer.")
+	command.Flags().StringVarP(&exportTransport, "transport", "T", "", "Only exports events fired with this transport.")
+	command.Flags().StringVar(&exportSince, "since", "", "Only exports events fired at or after this RFC3339 timestamp or relative time (600, 600s, 10d4h12m55s).")
+	command.Flags().StringVar(&exportUntil, "until", "", "Only exports events fired at or before this RFC3339 timestamp or relative time (600, 600s, 10d4h12m55s).")
+	command.Flags().IntVarP(&exportLimit, "limit", "l", 0, "Maximum number of events exported; only the most recent events are exported. Use 0 to export every event.")
+
+	return
+}
+
+func ImportCommand() (command *cobra.Command) {
+	command = &cobra.Command{
+		Use:   "import [file]",
+		Short: "Imports events from an NDJSON file created with \"twitch event export\" into the events cache, so they can be refired with \"twitch event retrigger\". Use - to read from stdin.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  importCmdRun,
+		Example: `  twitch event import events.ndjson
+  cat events.ndjson | twitch event import -`,
+	}
+
+	return
+}
+
+func exportCmdRun(cmd *cobra.Command, args []string) error {
+	var w io.Writer = os.Stdout
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	count, err := history.ExportEvents(w, history.HistoryParameters{
+		Event:     exportEvent,
+		Transport: exportTransport,
+		Since:     exportSince,
+		Until:     exportUntil,
+		Limit:     exportLimit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Keeps stdout clean when piping the export
+	fmt.Fprintf(os.Stderr, "Exported %v events.\n", count)
+	return nil
+}
+
+func importCmdRun(cmd *cobra.Command, args []string) error {
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	result, err := history.ImportEvents(r)
+	if err != nil {
+		return fmt.Errorf("%v\nImported %v events before the error.", err, result.Imported)
+	}
+
+	fmt.Printf("Imported %v events. Skipped %v events already in the events cache.\n", result.Imported, result.Skipped)
+	return nil
+}
@@ -0,0 +1,154 @@
+package events
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	configure_event "github.com/twitchdev/twitch-cli/internal/events/configure"
+	"github.com/twitchdev/twitch-cli/internal/events/history"
+)
+
+var (
+	historyEvent     string
+	historyTransport string
+	historyFromUser  string
+	historyToUser    string
+	historySince     string
+	historyUntil     string
+	historyLimit     int
+	historyJSON      bool
+	historyOlderThan string
+)
+
+func HistoryCommand() (command *cobra.Command) {
+	command = &cobra.Command{
+		Use:   "history",
+		Short: "Browses the cache of previously fired events, which can be refired with \"twitch event retrigger\".",
+		Example: `  twitch event history list --event channel.follow --since 2h
+	  twitch event history show 713f3254-0178-9757-7439-d779400c0999
+	  twitch event history search "testFromUser" --json
+	  twitch event history prune --older-than 30d`,
+	}
+
+	listCommand := &cobra.Command{
+		Use:     "list",
+		Short:   "Lists previously fired events, newest first.",
+		Args:    cobra.NoArgs,
+		RunE:    historyListCmdRun,
+		Example: `twitch event history list --event channel.follow --since 2h`,
+	}
+	addHistoryFilterFlags(listCommand)
+
+	showCommand := &cobra.Command{
+		Use:     "show [id]",
+		Short:   "Shows the full payload of a previously fired event.",
+		Args:    cobra.ExactArgs(1),
+		RunE:    historyShowCmdRun,
+		Example: `twitch event history show 713f3254-0178-9757-7439-d779400c0999`,
+	}
+	showCommand.Flags().BoolVar(&historyJSON, "json", false, "Outputs the event and its metadata as JSON.")
+
+	searchCommand := &cobra.Command{
+		Use:     "search [text]",
+		Short:   "Lists previously fired events whose payload contains the given text.",
+		Args:    cobra.ExactArgs(1),
+		RunE:    historySearchCmdRun,
+		Example: `twitch event history search "testFromUser"`,
+	}
+	addHistoryFilterFlags(searchCommand)
+
+	pruneCommand := &cobra.Command{
+		Use:     "prune",
+		Short:   "Deletes previously fired events from the cache.",
+		Args:    cobra.NoArgs,
+		RunE:    historyPruneCmdRun,
+		Example: `twitch event history prune --older-than 30d`,
+	}
+	pruneCommand.Flags().StringVar(&historyOlderThan, "older-than", "", "Deletes events older than this RFC3339 timestamp or relative time (600, 600s, 10d4h12m55s). Defaults to the retention set with \"twitch event configure --retention\".")
+	pruneCommand.Flags().BoolVarP(&noConfig, "no-config", "D", false, "Disables the use of the configuration, if it exists.")
+
+	command.AddCommand(listCommand, showCommand, searchCommand, pruneCommand)
+
+	return
+}
+
+func addHistoryFilterFlags(command *cobra.Command) {
+	command.Flags().StringVarP(&historyEvent, "event", "e", "", "Only shows events of this topic or trigger alias, for example channel.follow or cheer.")
+	command.Flags().StringVarP(&historyTransport, "transport", "T", "", "Only shows events fired with this transport.")
+	command.Flags().StringVarP(&historyFromUser, "from-user", "f", "", "Only shows events sent by this user ID.")
+	command.Flags().StringVarP(&historyToUser, "to-user", "t", "", "Only shows events received by this user ID.")
+	command.Flags().StringVar(&historySince, "since", "", "Only shows events fired at or after this RFC3339 timestamp or relative time (600, 600s, 10d4h12m55s).")
+	command.Flags().StringVar(&historyUntil, "until", "", "Only shows events fired at or before this RFC3339 timestamp or relative time (600, 600s, 10d4h12m55s).")
+	command.Flags().IntVarP(&historyLimit, "limit", "l", 50, "Maximum number of events shown. Use 0 to show every event.")
+	command.Flags().BoolVar(&historyJSON, "json", false, "Outputs events as JSON instead of a table.")
+}
+
+func historyListCmdRun(cmd *cobra.Command, args []string) error {
+	return printHistory("")
+}
+
+func historySearchCmdRun(cmd *cobra.Command, args []string) error {
+	return printHistory(args[0])
+}
+
+func printHistory(search string) error {
+	entries, err := history.GetEvents(history.HistoryParameters{
+		Event:     historyEvent,
+		Transport: historyTransport,
+		FromUser:  historyFromUser,
+		ToUser:    historyToUser,
+		Since:     historySince,
+		Until:     historyUntil,
+		Search:    search,
+		Limit:     historyLimit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if historyJSON {
+		return history.PrintJSON(os.Stdout, entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No events found.")
+		return nil
+	}
+
+	history.PrintTable(os.Stdout, entries)
+	return nil
+}
+
+func historyShowCmdRun(cmd *cobra.Command, args []string) error {
+	entry, err := history.GetEvent(args[0])
+	if err != nil {
+		return err
+	}
+
+	if historyJSON {
+		return history.PrintJSON(os.Stdout, entry)
+	}
+
+	history.PrintTable(os.Stdout, []history.HistoryEntry{entry})
+	fmt.Println()
+	return history.PrintJSON(os.Stdout, entry.Payload)
+}
+
+func historyPruneCmdRun(cmd *cobra.Command, args []string) error {
+	olderThan := historyOlderThan
+	if olderThan == "" {
+		olderThan = configure_event.GetEventConfiguration(noConfig).Retention
+		if olderThan == "" {
+			return fmt.Errorf("--older-than must be provided if a retention is not set with \"twitch event configure --retention\"")
+		}
+	}
+
+	count, err := history.PruneEvents(olderThan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted %v events from the events cache.\n", count)
+	return nil
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	configure_event "github.com/twitchdev/twitch-cli/internal/events/configure"
+	"github.com/twitchdev/twitch-cli/internal/events/listen"
+)
+
+var (
+	listenPort  int
+	listenStore bool
+	listenRaw   bool
+)
+
+func ListenCommand() (command *cobra.Command) {
+	command = &cobra.Command{
+		Use:   "listen",
+		Short: "Starts a local EventSub webhook receiver, which answers verification challenges, validates signatures, and prints every notification received.",
+		Args:  cobra.NoArgs,
+		RunE:  listenCmdRun,
+		Example: `  twitch event listen -p 8080 -s testsecret
+  twitch event listen --store`,
+	}
+
+	command.Flags().IntVarP(&listenPort, "port", "p", 8080, "Port the receiver listens on.")
+	command.Flags().StringVarP(&secret, "secret", "s", "", "Webhook secret used to validate signatures. Defaults to the secret set with \"twitch event configure\"; Signatures aren't validated if neither is set.")
+	command.Flags().BoolVarP(&noConfig, "no-config", "D", false, "Disables the use of the configuration, if it exists.")
+	command.Flags().BoolVar(&listenStore, "store", false, "Stores received notifications and revocations in the events cache, so they can be refired with \"twitch event retrigger\".")
+	command.Flags().BoolVar(&listenRaw, "raw", false, "Prints each payload as received instead of pretty-printing it.")
+
+	return
+}
+
+func listenCmdRun(cmd *cobra.Command, args []string) error {
+	if secret != "" {
+		if len(secret) < 10 || len(secret) > 100 {
+			return fmt.Errorf("Invalid secret provided. Secrets must be between 10-100 characters")
+		}
+	} else {
+		secret = configure_event.GetEventConfiguration(noConfig).Secret
+	}
+
+	return listen.StartListener(listen.ListenParameters{
+		Port:   listenPort,
+		Secret: secret,
+		Store:  listenStore,
+		Raw:    listenRaw,
+	})
+}
@@ -2,36 +2,63 @@ package events
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/spf13/cobra"
 	configure_event "github.com/twitchdev/twitch-cli/internal/events/configure"
+	"github.com/twitchdev/twitch-cli/internal/events/history"
 	"github.com/twitchdev/twitch-cli/internal/events/trigger"
-	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/internal/models"
+)
+
+var (
+	retriggerTransport  string
+	retriggerEvent      string
+	retriggerSince      string
+	retriggerLimit      int
+	retriggerRegenerate bool
 )
 
 func RetriggerCommand() (command *cobra.Command) {
 	command = &cobra.Command{
-		Use:     "retrigger",
-		Short:   "Refires events based on the event ID. Can be forwarded to the local webserver for event testing.",
-		RunE:    retriggerCmdRun,
-		Example: `twitch event retrigger subscribe`,
+		Use:   "retrigger",
+		Short: "Refires events based on the event ID, or every cached event matching a set of filters. Can be forwarded to the local webserver or the mock EventSub WebSocket server for event testing.",
+		RunE:  retriggerCmdRun,
+		Example: `  twitch event retrigger -i 713f3254-0178-9757-7439-d779400c0999
+  twitch event retrigger --since 1h --event channel.follow -T websocket
+  twitch event retrigger --since 2023-05-01T12:00:00Z --limit 10 --regenerate`,
 	}
 
 	command.Flags().StringVarP(&forwardAddress, "forward-address", "F", "", "Forward address for mock event (webhook only).")
 	command.Flags().StringVarP(&eventMessageID, "id", "i", "", "ID of the event to be refired.")
 	command.Flags().StringVarP(&secret, "secret", "s", "", "Webhook secret. If defined, signs all forwarded events with the SHA256 HMAC and must be 10-100 characters in length.")
 	command.Flags().BoolVarP(&noConfig, "no-config", "D", false, "Disables the use of the configuration, if it exists.")
-	command.MarkFlagRequired("id")
+	command.Flags().StringVarP(&retriggerTransport, "transport", "T", "", "Transport used to refire events. Defaults to the transport each event was originally fired with.\nSupported values: webhook, websocket")
+	command.Flags().StringVar(&websocketClient, "session", "", "Defines a specific websocket client/session to forward events to. Used only with \"websocket\" transport.")
+	command.Flags().StringVarP(&retriggerEvent, "event", "e", "", "Refires every cached event of this topic or trigger alias, for example channel.follow or cheer.")
+	command.Flags().StringVar(&retriggerSince, "since", "", "Refires every cached event fired at or after this RFC3339 timestamp or relative time (600, 600s, 10d4h12m55s).")
+	command.Flags().IntVarP(&retriggerLimit, "limit", "l", 0, "Maximum number of events refired when using --event or --since; only the most recent events are refired. Use 0 to refire every matching event.")
+	command.Flags().BoolVar(&retriggerRegenerate, "regenerate", false, "Generates a new message ID and timestamp for each refired event instead of reusing the cached ones. The event body is kept as-is.")
 
 	return
 }
 
 func retriggerCmdRun(cmd *cobra.Command, args []string) error {
-	if transport == "websub" {
+	if retriggerTransport == "websub" {
 		return fmt.Errorf(websubDeprecationNotice)
 	}
 
+	if retriggerTransport != "" && retriggerTransport != models.TransportWebhook && retriggerTransport != models.TransportWebSocket {
+		return fmt.Errorf("Invalid transport provided. Supported values: webhook, websocket")
+	}
+
+	if eventMessageID == "" && retriggerEvent == "" && retriggerSince == "" {
+		return fmt.Errorf("one of --id, --event, or --since must be provided")
+	}
+
+	if eventMessageID != "" && (retriggerEvent != "" || retriggerSince != "") {
+		return fmt.Errorf("--id cannot be used with --event or --since")
+	}
+
 	defaults := configure_event.GetEventConfiguration(noConfig)
 
 	if secret != "" {
@@ -43,22 +70,36 @@ func retriggerCmdRun(cmd *cobra.Command, args []string) error {
 	}
 
 	if forwardAddress == "" {
-		if defaults.ForwardAddress == "" {
-			return fmt.Errorf("if a default configuration is not set, forward-address must be provided")
-		}
 		forwardAddress = defaults.ForwardAddress
 	}
 
-	//color.New().Add(color.FgGreen).Println(fmt.Sprintf(`Refire %v`, eventMessageID));
-	res, err := trigger.RefireEvent(eventMessageID, trigger.TriggerParameters{
-		ForwardAddress: forwardAddress,
-		Secret:         secret,
-		Timestamp:      util.GetTimestamp().Format(time.RFC3339Nano),
-	})
+	p := trigger.TriggerParameters{
+		ForwardAddress:  forwardAddress,
+		Secret:          secret,
+		Transport:       retriggerTransport,
+		WebSocketClient: websocketClient,
+		Regenerate:      retriggerRegenerate,
+	}
+
+	if eventMessageID != "" {
+		res, err := trigger.RefireEvent(eventMessageID, p)
+		if err != nil {
+			return fmt.Errorf("Error refiring event: %s", err)
+		}
+
+		fmt.Println(res)
+		return nil
+	}
+
+	ids, err := trigger.RefireEvents(history.HistoryParameters{
+		Event: retriggerEvent,
+		Since: retriggerSince,
+		Limit: retriggerLimit,
+	}, p)
 	if err != nil {
-		return fmt.Errorf("Error refiring event: %s", err)
+		return fmt.Errorf("Error refiring events after refiring %v events: %s", len(ids), err)
 	}
 
-	fmt.Println(res)
+	fmt.Printf("Refired %v events.\n", len(ids))
 	return nil
 }
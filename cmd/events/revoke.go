@@ -0,0 +1,94 @@
+package events
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/twitchdev/twitch-cli/internal/events"
+	configure_event "github.com/twitchdev/twitch-cli/internal/events/configure"
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+)
+
+var revokeReason string
+
+func RevokeCommand() (command *cobra.Command) {
+	command = &cobra.Command{
+		Use:   "revoke [event]",
+		Short: "Creates mock revocation messages, which notify that a subscription to the event was revoked, and can be forwarded to a local webserver or the mock EventSub WebSocket server.",
+		Long: fmt.Sprintf(`Creates mock revocation messages, which notify that a subscription to the event was revoked, and can be forwarded to a local webserver or the mock EventSub WebSocket server.
+		Supported reasons:
+		%s`, strings.Join(events.ValidRevocationReasons(), ", ")),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: types.AllWebhookTopics(),
+		RunE:      revokeCmdRun,
+		Example: `  twitch event revoke channel.follow -F https://localhost:8080
+  twitch event revoke cheer --reason user_removed -T websocket`,
+	}
+
+	command.Flags().StringVarP(&revokeReason, "reason", "r", events.SubscriptionStatusAuthorizationRevoked, fmt.Sprintf("Reason the subscription was revoked, sent as the subscription's status.\nSupported values: %s", strings.Join(events.ValidRevocationReasons(), ", ")))
+	command.Flags().StringVarP(&forwardAddress, "forward-address", "F", "", "Forward address for mock event (webhook only).")
+	command.Flags().StringVarP(&transport, "transport", "T", "webhook", fmt.Sprintf("Preferred transport method for event. Defaults to /EventSub.\nSupported values: %s", events.ValidTransports()))
+	command.Flags().StringVarP(&secret, "secret", "s", "", "Webhook secret. If defined, signs all forwarded events with the SHA256 HMAC and must be 10-100 characters in length.")
+	command.Flags().BoolVarP(&noConfig, "no-config", "D", false, "Disables the use of the configuration, if it exists.")
+	command.Flags().StringVarP(&toUser, "to-user", "t", "", "User ID of the broadcaster in the subscription's condition.")
+	command.Flags().StringVarP(&subscriptionID, "subscription-id", "u", "", "Manually set the ID of the revoked subscription.")
+	command.Flags().StringVarP(&eventMessageID, "event-id", "I", "", "Manually set the Twitch-Eventsub-Message-Id header value for the message.")
+	command.Flags().StringVar(&timestamp, "timestamp", "", "Sets the timestamp to be used in payloads and headers. Must be in RFC3339Nano format.")
+	command.Flags().StringVarP(&version, "version", "v", "", "Chooses the EventSub version used for a specific event. Not required for most events.")
+	command.Flags().StringVar(&websocketClient, "session", "", "Defines a specific websocket client/session to forward the message to. Used only with \"websocket\" transport.")
+
+	return
+}
+
+func revokeCmdRun(cmd *cobra.Command, args []string) error {
+	if transport == "websub" {
+		return fmt.Errorf(websubDeprecationNotice)
+	}
+
+	if !events.IsValidRevocationReason(revokeReason) {
+		return fmt.Errorf("Invalid reason provided. Supported values: %v", strings.Join(events.ValidRevocationReasons(), ", "))
+	}
+
+	defaults := configure_event.GetEventConfiguration(noConfig)
+
+	if secret != "" {
+		if len(secret) < 10 || len(secret) > 100 {
+			return fmt.Errorf("Invalid secret provided. Secrets must be between 10-100 characters")
+		}
+	} else {
+		secret = defaults.Secret
+	}
+
+	// Validate that the forward address is actually a URL
+	if len(forwardAddress) > 0 {
+		_, err := url.ParseRequestURI(forwardAddress)
+		if err != nil {
+			return err
+		}
+	} else {
+		forwardAddress = defaults.ForwardAddress
+	}
+
+	res, err := trigger.Fire(trigger.TriggerParameters{
+		Event:              args[0],
+		SubscriptionID:     subscriptionID,
+		EventMessageID:     eventMessageID,
+		Transport:          transport,
+		ForwardAddress:     forwardAddress,
+		ToUser:             toUser,
+		Secret:             secret,
+		SubscriptionStatus: revokeReason,
+		Timestamp:          timestamp,
+		Version:            version,
+		WebSocketClient:    websocketClient,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(res)
+	return nil
+}
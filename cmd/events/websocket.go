@@ -3,8 +3,10 @@ package events
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/twitchdev/twitch-cli/internal/admin"
 	"github.com/twitchdev/twitch-cli/internal/events/websocket"
 	"github.com/twitchdev/twitch-cli/internal/events/websocket/mock_server"
 )
@@ -20,17 +22,44 @@ var (
 	wsServerPort     int
 	wsSSL            bool
 	wsFeatureEnabled bool
+	wsInteractive    bool
+	wsJSON           bool
+	wsChaos          mock_server.ChaosOptions
+	wsRecordDir      string
+	wsPersist        bool
+
+	wsReconnectPercent      float64
+	wsReconnectWaves        int
+	wsReconnectWaveInterval time.Duration
+	wsReconnectGracePeriod  time.Duration
+	wsCloseOldConnections   bool
+
+	wsTopics           []string
+	wsConditions       []string
+	wsClientID         string
+	wsToken            string
+	wsSubscriptionsURL string
 )
 
 func WebsocketCommand() (command *cobra.Command) {
 	command = &cobra.Command{
-		Use:   "websocket [action]",
+		Use:   "websocket [action] [args]",
 		Short: `Executes actions regarding the mock EventSub WebSocket server. See "twitch event websocket --help" for usage info.`,
 		Long:  "Executes actions regarding the mock EventSub WebSocket server.",
-		Args:  cobra.MaximumNArgs(1),
+		Args:  websocketArgs,
 		RunE:  websocketCmdRun,
 		Example: `  twitch event websocket start-server
+	  twitch event websocket start-server --interactive
+	  twitch event websocket start-server --chaos-disconnect=0.05 --chaos-latency=0.2 --chaos-duplicate=0.1
+	  twitch event websocket start-server --record-dir=./transcripts
+	  twitch event websocket start-server --persist-subscriptions
+	  twitch event websocket diff ./transcripts/e411cc1e_a2613d4e.jsonl ./transcripts/7b3a2f0d_c1d2e3f4.jsonl
+	  twitch event websocket connect
+	  twitch event websocket connect ws://127.0.0.1:8080/ws --topic=channel.follow:2 --condition=broadcaster_user_id=1234 --condition=moderator_user_id=1234 --client-id=<client_id> --token=<user_token>
+	  twitch event websocket sessions --json
+	  twitch event websocket subscriptions --session=e411cc1e_a2613d4e
 	  twitch event websocket reconnect
+	  twitch event websocket reconnect --percent=25 --waves=3 --wave-interval=20s --grace-period=10s
 	  twitch event websocket close --session=e411cc1e_a2613d4e --reason=4006
 	  twitch event websocket subscription --status=user_removed --subscription=82a855-fae8-93bff0
 	  twitch event websocket keepalive --session=e411cc1e_a2613d4e --enabled=false`,
@@ -44,20 +73,58 @@ func WebsocketCommand() (command *cobra.Command) {
 	// flags for start-server
 	command.Flags().StringVar(&wsServerIP, "ip", "127.0.0.1", "Defines the ip that the mock EventSub websocket server will bind to.")
 	command.Flags().IntVarP(&wsServerPort, "port", "p", 8080, "Defines the port that the mock EventSub websocket server will run on.")
-	command.Flags().BoolVar(&wsSSL, "ssl", false, "Enables SSL for EventSub websocket server (wss) and EventSub mock subscription server (https).")
-	command.Flags().BoolVar(&wsDebug, "debug", false, "Set on/off for debug messages for the EventSub WebSocket server.")
+	command.Flags().BoolVar(&wsSSL, "ssl", false, "Enables SSL for EventSub websocket server (wss) and EventSub mock subscription server (https). A localhost certificate signed by a local CA is generated if none was added to the application directory.")
+	command.Flags().BoolVar(&wsDebug, "debug", false, "Set on/off for debug messages for the EventSub WebSocket server. Prints keepalive messages with \"websocket connect\".")
 	command.Flags().BoolVarP(&wsStrict, "require-subscription", "S", false, "Requires subscriptions for all events, and activates 10 second subscription requirement.")
+	command.Flags().BoolVarP(&wsInteractive, "interactive", "i", false, "Starts an interactive shell for running server commands, such as triggering events or closing sessions, from the same terminal.")
+	command.Flags().BoolVar(&wsPersist, "persist-subscriptions", false, "Saves subscriptions and conduits to the database, and restores them when the server restarts. Webhook secrets, and the access tokens subscriptions were created with, are saved in plaintext.")
+	command.Flags().StringVar(&wsRecordDir, "record-dir", "", "Records the frames sent and received on each session, with timestamps, to a <session_id>.jsonl file in this directory.")
+	command.Flags().Float64Var(&wsChaos.Latency, "chaos-latency", 0, "Probability (0-1) of delaying each notification by a random duration up to --chaos-max-latency.")
+	command.Flags().DurationVar(&wsChaos.MaxLatency, "chaos-max-latency", 2*time.Second, "Maximum delay added to notifications by --chaos-latency.")
+	command.Flags().Float64Var(&wsChaos.DropKeepalive, "chaos-drop-keepalive", 0, "Probability (0-1) of skipping each session_keepalive message.")
+	command.Flags().Float64Var(&wsChaos.DelayKeepalive, "chaos-delay-keepalive", 0, "Probability (0-1) of delaying each session_keepalive message by up to the session's keepalive timeout.")
+	command.Flags().Float64Var(&wsChaos.Disconnect, "chaos-disconnect", 0, "Probability (0-1) of dropping each session's TCP connection, without a close frame, every keepalive interval.")
+	command.Flags().Float64Var(&wsChaos.Reconnect, "chaos-reconnect", 0, "Probability (0-1) of sending each session a session_reconnect message every keepalive interval.")
+	command.Flags().Float64Var(&wsChaos.Duplicate, "chaos-duplicate", 0, "Probability (0-1) of sending each notification twice, with the same message_id.")
 
 	// flags for everything else
-	command.Flags().StringVarP(&wsClient, "session", "s", "", "WebSocket client/session to target with your server command. Used in multiple commands.")
+	command.Flags().StringVarP(&wsClient, "session", "s", "", "WebSocket client/session to target with your server command. Used in multiple commands. \"websocket reconnect\" accepts a comma-separated list.")
 	command.Flags().StringVar(&wsSubscription, "subscription", "", `Subscription to target with your server command. Used with "websocket subscription".`)
 	command.Flags().StringVar(&wsStatus, "status", "", `Changes the status of an existing subscription. Used with "websocket subscription".`)
 	command.Flags().StringVar(&wsReason, "reason", "", `Sets the close reason when sending a Close message to the client. Used with "websocket close".`)
 	command.Flags().BoolVar(&wsFeatureEnabled, "enabled", false, "Sets on/off for the specified feature.")
+	command.Flags().Float64Var(&wsReconnectPercent, "percent", 0, `Only reconnects this percentage of sessions, chosen at random. Used with "websocket reconnect".`)
+	command.Flags().IntVar(&wsReconnectWaves, "waves", 1, `Number of waves the sessions are reconnected in. Used with "websocket reconnect".`)
+	command.Flags().DurationVar(&wsReconnectWaveInterval, "wave-interval", 10*time.Second, `Time between waves. Used with "websocket reconnect".`)
+	command.Flags().DurationVar(&wsReconnectGracePeriod, "grace-period", mock_server.RECONNECT_GRACE_SECONDS*time.Second, `Time sessions have to reconnect before they're closed with 4004. Used with "websocket reconnect".`)
+	command.Flags().BoolVar(&wsCloseOldConnections, "close-old-connections", false, `Closes a session's old connection with 4004 as soon as it reconnects, as if the client didn't close it. Used with "websocket reconnect".`)
+	command.Flags().BoolVar(&wsJSON, "json", false, `Prints the output as JSON. Used with "websocket sessions", "websocket subscriptions", "websocket diff", and "websocket connect".`)
+
+	// flags for connect
+	command.Flags().StringArrayVar(&wsTopics, "topic", nil, "Available multiple times. Topic to subscribe to once connected, using the format of `type:version`, such as channel.follow:2. The version defaults to 1.")
+	command.Flags().StringArrayVar(&wsConditions, "condition", nil, "Available multiple times. Condition of the subscriptions created with --topic, using the format of `key=value`, such as broadcaster_user_id=1234.")
+	command.Flags().StringVar(&wsClientID, "client-id", "", "Client ID used to create subscriptions with --topic. Defaults to the configured client ID.")
+	command.Flags().StringVar(&wsToken, "token", "", "User access token used to create subscriptions with --topic. Defaults to the configured access token.")
+	command.Flags().StringVar(&wsSubscriptionsURL, "subscriptions-url", "", "URL of the EventSub subscriptions endpoint used with --topic. Defaults to /eventsub/subscriptions on the WebSocket server's host, or the Twitch API for Twitch's server.")
 
 	return
 }
 
+// Only "websocket diff" and "websocket connect" take arguments after the action, which are the two transcripts to
+// compare, and the optional URL to connect to
+func websocketArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && args[0] == "diff" {
+		if len(args) != 3 {
+			return fmt.Errorf("diff requires two transcript files; Example: twitch event websocket diff a.jsonl b.jsonl")
+		}
+		return nil
+	}
+	if len(args) > 0 && args[0] == "connect" {
+		return cobra.MaximumNArgs(2)(cmd, args)
+	}
+	return cobra.MaximumNArgs(1)(cmd, args)
+}
+
 func websocketCmdRun(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
 		cmd.Help()
@@ -65,17 +132,47 @@ func websocketCmdRun(cmd *cobra.Command, args []string) error {
 	}
 
 	if args[0] == "start-server" || args[0] == "start" {
+		if err := wsChaos.Validate(); err != nil {
+			return err
+		}
+
 		log.Printf("Attempting to start WebSocket server on %v:%v", wsServerIP, wsServerPort)
 		log.Printf("`Ctrl + C` to exit mock WebSocket servers.")
-		mock_server.StartWebsocketServer(wsDebug, wsServerIP, wsServerPort, wsSSL, wsStrict)
+		mock_server.StartWebsocketServer(wsDebug, wsServerIP, wsServerPort, admin.Port(), wsSSL, wsStrict, wsInteractive, wsChaos, wsRecordDir, wsPersist)
+	} else if args[0] == "diff" {
+		return websocket.DiffTranscripts(args[1], args[2], wsJSON)
+	} else if args[0] == "connect" {
+		wsURL := ""
+		if len(args) == 2 {
+			wsURL = args[1]
+		}
+		return websocket.Connect(wsURL, websocket.ConnectParameters{
+			Topics:           wsTopics,
+			Condition:        wsConditions,
+			ClientID:         wsClientID,
+			Token:            wsToken,
+			SubscriptionsURL: wsSubscriptionsURL,
+			JSON:             wsJSON,
+			Debug:            wsDebug,
+		})
+	} else if args[0] == "sessions" {
+		return websocket.ListSessions(wsJSON)
+	} else if args[0] == "subscriptions" {
+		return websocket.ListSubscriptions(wsClient, wsJSON)
 	} else {
-		// Forward all other commands via RPC
+		// Forward all other commands via the server's admin API
 		err := websocket.ForwardWebsocketCommand(args[0], websocket.WebsocketCommandParameters{
 			Client:             wsClient,
 			Subscription:       wsSubscription,
 			SubscriptionStatus: wsStatus,
 			CloseReason:        wsReason,
 			FeatureEnabled:     wsFeatureEnabled,
+
+			Percent:             wsReconnectPercent,
+			Waves:               wsReconnectWaves,
+			WaveInterval:        wsReconnectWaveInterval,
+			GracePeriod:         wsReconnectGracePeriod,
+			CloseOldConnections: wsCloseOldConnections,
 		})
 
 		return err
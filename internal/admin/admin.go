@@ -0,0 +1,243 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package admin implements the HTTP/JSON admin API of the mock EventSub server, which is used by other CLI commands,
+// and by test suites, to fire events and control the server.
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultPort is the port the admin API listens on when --admin-port, TWITCH_ADMIN_PORT, and the admin_port config
+// value aren't set.
+const DefaultPort = 44747
+
+// ErrServerNotRunning is returned by the client when nothing is listening on the admin port.
+var ErrServerNotRunning = errors.New("the mock EventSub server's admin API isn't reachable; It may not be running. See `twitch event websocket --help` for help on starting the server")
+
+// EventRequest is the body of POST /admin/events, POST /admin/events/websocket, and POST /admin/events/webhook.
+type EventRequest struct {
+	Event            json.RawMessage `json:"event"`             // EventSub payload, with subscription and event objects
+	Session          string          `json:"session,omitempty"` // WebSocket only; Session to send the event to. All matching sessions and conduits if empty
+	MessageID        string          `json:"message_id,omitempty"`
+	MessageTimestamp string          `json:"message_timestamp,omitempty"`
+}
+
+// CloseRequest is the body of POST /admin/sessions/close.
+type CloseRequest struct {
+	Session string `json:"session"`
+	Code    int    `json:"code"`
+}
+
+// ReconnectRequest is the body of POST /admin/reconnect. Every field is optional; An empty body restarts the server,
+// reconnecting every session at once.
+type ReconnectRequest struct {
+	Sessions            []string `json:"sessions,omitempty"`              // Only reconnects these sessions
+	Percent             float64  `json:"percent,omitempty"`               // Only reconnects this percentage of sessions, chosen at random
+	Waves               *int     `json:"waves,omitempty"`                 // Number of waves the sessions are reconnected in. Defaults to 1
+	WaveIntervalSeconds int      `json:"wave_interval_seconds,omitempty"` // Time between waves
+	GracePeriodSeconds  *int     `json:"grace_period_seconds,omitempty"`  // Time sessions have to reconnect before they're closed with 4004. Defaults to 30
+	CloseOldConnections bool     `json:"close_old_connections,omitempty"` // Closes a session's old connection with 4004 as soon as it reconnects
+}
+
+// KeepaliveRequest is the body of POST /admin/sessions/keepalive.
+type KeepaliveRequest struct {
+	Session string `json:"session"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// SubscriptionStatusRequest is the body of POST /admin/subscriptions/status.
+type SubscriptionStatusRequest struct {
+	SubscriptionID string `json:"subscription_id"`
+	Status         string `json:"status"`
+}
+
+// RevokeRequest is the body of POST /admin/authorizations/revoke, sent by the mock API when authorizations are revoked
+// with its /auth/revoke endpoint.
+type RevokeRequest struct {
+	ClientID string   `json:"client_id"`
+	UserID   string   `json:"user_id,omitempty"` // Set when the user revoked every authorization they gave the client
+	Tokens   []string `json:"tokens"`            // Tokens of the revoked authorizations
+}
+
+// Response is the body of successful admin API responses.
+type Response struct {
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse is the body of failed admin API responses, in the same format as the mock EventSub endpoints.
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// APIError is returned by the client when the admin API responds with an error.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Port returns the admin API port, from --admin-port, TWITCH_ADMIN_PORT, or the admin_port config value.
+func Port() int {
+	port := viper.GetInt("admin_port")
+	if port == 0 {
+		return DefaultPort
+	}
+	return port
+}
+
+// Server serves the admin API on its own port, separate from the endpoints the mock server emulates. It only listens
+// on 127.0.0.1, which is where clients send requests, whatever address the mock server itself binds to.
+type Server struct {
+	Port     int
+	mux      *http.ServeMux
+	routes   []Route
+	listener net.Listener
+}
+
+// Route is an endpoint of the admin API, as listed by GET /admin.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+func NewServer(port int) *Server {
+	s := &Server{
+		Port: port,
+		mux:  http.NewServeMux(),
+	}
+	s.Handle(http.MethodGet, "/admin", func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, "", s.routes)
+	})
+	return s
+}
+
+// Handle registers a handler for the path, which only accepts the given HTTP method.
+func (s *Server) Handle(method string, path string, handler http.HandlerFunc) {
+	s.routes = append(s.routes, Route{Method: method, Path: path})
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			WriteError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%v only supports %v", path, method))
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// Start listens on the admin port and serves requests in the background.
+func (s *Server) Start() error {
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%v", s.Port))
+	if err != nil {
+		return err
+	}
+	s.listener = l
+	go http.Serve(l, s.mux)
+
+	return nil
+}
+
+func (s *Server) Shutdown() {
+	if s.listener != nil {
+		s.listener.Close()
+	}
+}
+
+// WriteJSON writes a successful response with the given message and data.
+func WriteJSON(w http.ResponseWriter, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&Response{Message: message, Data: data})
+}
+
+// WriteError writes a failed response with the given status code.
+func WriteError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&ErrorResponse{
+		Error:   http.StatusText(status),
+		Status:  status,
+		Message: message,
+	})
+}
+
+// DecodeBody reads a JSON request body, writing a 400 response and returning false if it's invalid.
+func DecodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON body: %v", err))
+		return false
+	}
+	return true
+}
+
+// Call sends a request to the admin API on the configured port. The request body and the response's data are
+// encoded as JSON; Either may be nil. Returns the response's message.
+func Call(method string, path string, body interface{}, data interface{}) (string, error) {
+	return CallPort(Port(), method, path, body, data)
+}
+
+// CallPort sends a request to the admin API on the given port. See Call.
+func CallPort(port int, method string, path string, body interface{}, data interface{}) (string, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("http://127.0.0.1:%v%v", port, path), reqBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return "", ErrServerNotRunning
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e ErrorResponse
+		if err := json.Unmarshal(respBody, &e); err != nil || e.Message == "" {
+			return "", &APIError{Status: resp.StatusCode, Message: fmt.Sprintf("Admin API responded %v", resp.Status)}
+		}
+		return "", &APIError{Status: resp.StatusCode, Message: e.Message}
+	}
+
+	r := Response{Data: data}
+	if err := json.Unmarshal(respBody, &r); err != nil {
+		return "", fmt.Errorf("Invalid response from admin API: %v", err)
+	}
+
+	return r.Message, nil
+}
@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+type adminTestStruct struct {
+	Field1 string `json:"field1"`
+	Field2 int    `json:"field2"`
+}
+
+func TestCall(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	s := NewServer(44748)
+	s.Handle(http.MethodPost, "/admin/echo", func(w http.ResponseWriter, r *http.Request) {
+		var body adminTestStruct
+		if !DecodeBody(w, r, &body) {
+			return
+		}
+		WriteJSON(w, "echoed", body)
+	})
+	s.Handle(http.MethodPost, "/admin/fail", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, http.StatusConflict, "already in progress")
+	})
+
+	err := s.Start()
+	a.Nil(err)
+	defer s.Shutdown()
+
+	sent := adminTestStruct{Field1: "abcd", Field2: 1234}
+	var reply adminTestStruct
+	msg, err := CallPort(44748, http.MethodPost, "/admin/echo", sent, &reply)
+	a.Nil(err)
+	a.Equal("echoed", msg)
+	a.Equal(sent, reply)
+
+	// Errors include the status code and message
+	_, err = CallPort(44748, http.MethodPost, "/admin/fail", nil, nil)
+	var apiErr *APIError
+	a.True(errors.As(err, &apiErr))
+	a.Equal(http.StatusConflict, apiErr.Status)
+	a.Equal("already in progress", apiErr.Message)
+
+	_, err = CallPort(44748, http.MethodGet, "/admin/echo", nil, nil)
+	a.True(errors.As(err, &apiErr))
+	a.Equal(http.StatusMethodNotAllowed, apiErr.Status)
+
+	_, err = CallPort(44748, http.MethodPost, "/admin/echo", "not an object", nil)
+	a.True(errors.As(err, &apiErr))
+	a.Equal(http.StatusBadRequest, apiErr.Status)
+
+	// The index lists every route
+	var routes []Route
+	_, err = CallPort(44748, http.MethodGet, "/admin", nil, &routes)
+	a.Nil(err)
+	a.Equal([]Route{
+		{Method: http.MethodGet, Path: "/admin"},
+		{Method: http.MethodPost, Path: "/admin/echo"},
+		{Method: http.MethodPost, Path: "/admin/fail"},
+	}, routes)
+
+	// Nothing listening
+	_, err = CallPort(44749, http.MethodPost, "/admin/echo", sent, nil)
+	a.ErrorIs(err, ErrServerNotRunning)
+}
+
+func TestPort(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	a.Equal(DefaultPort, Port())
+
+	viper.Set("admin_port", 44750)
+	defer viper.Set("admin_port", nil)
+	a.Equal(44750, Port())
+}
@@ -0,0 +1,286 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certs provides the TLS certificate used by the mock servers' --ssl flag. When no certificate was added to the
+// application directory, a local certificate authority and a localhost certificate signed by it are generated, so only
+// the CA has to be trusted by clients.
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+const (
+	CertFile   = "localhost.crt"
+	KeyFile    = "localhost.key"
+	CACertFile = "twitch-cli-ca.crt"
+	CAKeyFile  = "twitch-cli-ca.key"
+)
+
+// Browsers reject leaf certificates valid for longer than 398 days
+const certValidity = 365 * 24 * time.Hour
+const caValidity = 10 * 365 * 24 * time.Hour
+
+// Paths of the certificate and key used with --ssl, and of the local CA if it signed them.
+type Files struct {
+	CertFile  string
+	KeyFile   string
+	CACert    string // Empty if the certificate wasn't generated by the CLI
+	Generated bool   // The certificate was generated by this call
+}
+
+// Localhost returns the certificate and key in the application directory, generating them if either is missing. host is
+// the address the server binds to, which generated certificates are also valid for unless it's empty or unspecified.
+func Localhost(host string) (Files, error) {
+	home, err := util.GetApplicationDir()
+	if err != nil {
+		return Files{}, err
+	}
+	return LocalhostInDir(home, host)
+}
+
+// LocalhostInDir returns the certificate and key in dir, generating them if either is missing, or if the certificate was
+// generated by the CLI and has expired or isn't valid for host. The CA is generated along with the first certificate and
+// reused afterwards, so clients only need to trust it once.
+func LocalhostInDir(dir string, host string) (Files, error) {
+	files := Files{
+		CertFile: filepath.Join(dir, CertFile),
+		KeyFile:  filepath.Join(dir, KeyFile),
+	}
+	caCertFile := filepath.Join(dir, CACertFile)
+	caKeyFile := filepath.Join(dir, CAKeyFile)
+
+	caCert, caKey, err := loadCA(caCertFile, caKeyFile)
+	if err != nil {
+		return Files{}, err
+	}
+
+	_, certErr := os.Stat(files.CertFile)
+	_, keyErr := os.Stat(files.KeyFile)
+	if certErr == nil && keyErr == nil {
+		// Certificates added by the user are always used as is
+		if caCert == nil || !signedBy(files.CertFile, caCert) {
+			return files, nil
+		}
+		files.CACert = caCertFile
+		if !expired(files.CertFile) && validForFile(files.CertFile, host) {
+			return files, nil
+		}
+	} else if (certErr != nil && !errors.Is(certErr, os.ErrNotExist)) || (keyErr != nil && !errors.Is(keyErr, os.ErrNotExist)) {
+		return Files{}, fmt.Errorf("Cannot read the SSL certificate: %v", errors.Join(certErr, keyErr))
+	}
+
+	if caCert == nil {
+		caCert, caKey, err = generateCA(caCertFile, caKeyFile)
+		if err != nil {
+			return Files{}, err
+		}
+	}
+
+	if err := generateLocalhost(files.CertFile, files.KeyFile, caCert, caKey, host); err != nil {
+		return Files{}, err
+	}
+	files.CACert = caCertFile
+	files.Generated = true
+
+	return files, nil
+}
+
+// TrustHint explains how clients can trust the local CA, for the servers to print when they start.
+func TrustHint(caCert string) string {
+	return fmt.Sprintf(`Clients must trust the Twitch CLI's local CA to connect without certificate errors. The CA certificate is at:
+	%v
+Add it to your system's trust store, or pass it to your client, such as with NODE_EXTRA_CA_CERTS, SSL_CERT_FILE, or REQUESTS_CA_BUNDLE.`, caCert)
+}
+
+// RootCAs returns the system's trusted CAs, along with the local CA in the application directory if it was generated, so
+// the CLI's own clients can connect to the mock servers over TLS.
+func RootCAs() (*x509.CertPool, error) {
+	home, err := util.GetApplicationDir()
+	if err != nil {
+		return nil, err
+	}
+	return RootCAsInDir(home)
+}
+
+// RootCAsInDir returns the system's trusted CAs, along with the local CA in dir if it was generated.
+func RootCAsInDir(dir string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	caPEM, err := os.ReadFile(filepath.Join(dir, CACertFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return pool, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("Invalid CA certificate %v", filepath.Join(dir, CACertFile))
+	}
+
+	return pool, nil
+}
+
+// Loads the CA, or returns nils if it hasn't been generated
+func loadCA(certFile string, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid CA certificate %v: %v", certFile, err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, nil, fmt.Errorf("Invalid CA key %v", keyFile)
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid CA key %v: %v", keyFile, err)
+	}
+
+	return cert, key, nil
+}
+
+func generateCA(certFile string, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               pkix.Name{Organization: []string{"Twitch CLI"}, CommonName: "Twitch CLI Local CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := writeFiles(certFile, keyFile, der, key); err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	return cert, key, err
+}
+
+func generateLocalhost(certFile string, keyFile string, caCert *x509.Certificate, caKey *ecdsa.PrivateKey, host string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(),
+		Subject:      pkix.Name{Organization: []string{"Twitch CLI"}, CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+	}
+	if !validFor(template, host) {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+
+	return writeFiles(certFile, keyFile, der, key)
+}
+
+func writeFiles(certFile string, keyFile string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func signedBy(certFile string, ca *x509.Certificate) bool {
+	cert, err := readCertificate(certFile)
+	return err == nil && cert.CheckSignatureFrom(ca) == nil
+}
+
+// Returns whether the certificate is valid for host. Empty and unspecified hosts, such as 0.0.0.0, aren't checked, as
+// clients can't connect to them
+func validFor(cert *x509.Certificate, host string) bool {
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return true
+	}
+	return cert.VerifyHostname(host) == nil
+}
+
+func validForFile(certFile string, host string) bool {
+	cert, err := readCertificate(certFile)
+	return err == nil && validFor(cert, host)
+}
+
+func expired(certFile string) bool {
+	cert, err := readCertificate(certFile)
+	return err != nil || time.Now().After(cert.NotAfter)
+}
+
+func readCertificate(certFile string) (*x509.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertificate(certPEM)
+}
+
+func serialNumber() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestLocalhostInDir(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	dir := t.TempDir()
+	files, err := LocalhostInDir(dir, "")
+	a.Nil(err)
+	a.True(files.Generated)
+	a.Equal(filepath.Join(dir, CACertFile), files.CACert)
+
+	// The certificate is valid for localhost when the CA is trusted
+	_, err = tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+	a.Nil(err)
+	caPEM, err := os.ReadFile(files.CACert)
+	a.Nil(err)
+	roots := x509.NewCertPool()
+	a.True(roots.AppendCertsFromPEM(caPEM))
+	cert, err := readCertificate(files.CertFile)
+	a.Nil(err)
+	for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		a.Nil(err, host)
+	}
+
+	// Existing certificates are reused
+	files, err = LocalhostInDir(dir, "")
+	a.Nil(err)
+	a.False(files.Generated)
+	a.Equal(filepath.Join(dir, CACertFile), files.CACert)
+
+	// A missing certificate is regenerated with the same CA
+	a.Nil(os.Remove(files.KeyFile))
+	files, err = LocalhostInDir(dir, "")
+	a.Nil(err)
+	a.True(files.Generated)
+	a.True(signedBy(files.CertFile, mustLoadCA(t, dir)))
+}
+
+func TestLocalhostInDirHost(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	dir := t.TempDir()
+	files, err := LocalhostInDir(dir, "0.0.0.0")
+	a.Nil(err)
+	a.True(files.Generated)
+
+	roots, err := RootCAsInDir(dir)
+	a.Nil(err)
+	verify := func(host string) error {
+		cert, err := readCertificate(files.CertFile)
+		a.Nil(err)
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		return err
+	}
+
+	// Unspecified and loopback addresses are covered by the localhost certificate
+	for _, host := range []string{"0.0.0.0", "127.0.0.1", "localhost"} {
+		files, err = LocalhostInDir(dir, host)
+		a.Nil(err)
+		a.False(files.Generated, host)
+	}
+
+	// The certificate is regenerated with the same CA for other addresses the server binds to
+	for _, host := range []string{"192.168.1.20", "fd00::20", "mock.test"} {
+		a.NotNil(verify(host), host)
+		files, err = LocalhostInDir(dir, host)
+		a.Nil(err)
+		a.True(files.Generated, host)
+		a.Nil(verify(host), host)
+		a.Nil(verify("localhost"))
+
+		files, err = LocalhostInDir(dir, host)
+		a.Nil(err)
+		a.False(files.Generated, host)
+	}
+}
+
+func TestLocalhostInDirUserCertificate(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	// Certificates not signed by the CLI's CA are used as is
+	dir := t.TempDir()
+	other := t.TempDir()
+	generated, err := LocalhostInDir(other, "")
+	a.Nil(err)
+	for _, f := range []string{CertFile, KeyFile} {
+		b, err := os.ReadFile(filepath.Join(other, f))
+		a.Nil(err)
+		a.Nil(os.WriteFile(filepath.Join(dir, f), b, 0600))
+	}
+
+	files, err := LocalhostInDir(dir, "")
+	a.Nil(err)
+	a.False(files.Generated)
+	a.Empty(files.CACert)
+	a.NotEqual(generated.CertFile, files.CertFile)
+}
+
+func TestRootCAsInDir(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	dir := t.TempDir()
+	_, err := RootCAsInDir(dir)
+	a.Nil(err)
+
+	// Generated certificates are trusted once the CA exists
+	files, err := LocalhostInDir(dir, "")
+	a.Nil(err)
+	roots, err := RootCAsInDir(dir)
+	a.Nil(err)
+	cert, err := readCertificate(files.CertFile)
+	a.Nil(err)
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: roots})
+	a.Nil(err)
+
+	a.Nil(os.WriteFile(filepath.Join(dir, CACertFile), []byte("invalid"), 0600))
+	_, err = RootCAsInDir(dir)
+	a.NotNil(err)
+}
+
+func mustLoadCA(t *testing.T, dir string) *x509.Certificate {
+	cert, _, err := loadCA(filepath.Join(dir, CACertFile), filepath.Join(dir, CAKeyFile))
+	if err != nil || cert == nil {
+		t.Fatalf("loading CA: %v", err)
+	}
+	return cert
+}
@@ -303,4 +303,22 @@ create table stream_schedule(
   category_id text, 
   foreign key (broadcaster_id) references users(id), 
   foreign key (category_id) references categories(id)
+);
+create table eventsub_websocket_subscriptions(
+  session_id text not null,
+  pending_reconnect boolean not null default false,
+  subscription_id text not null,
+  json text not null,
+  primary key (session_id, pending_reconnect, subscription_id)
+);
+
+create table eventsub_subscriptions(
+  subscription_id text not null primary key,
+  transport text not null,
+  json text not null
+);
+
+create table eventsub_conduits(
+  conduit_id text not null primary key,
+  json text not null
 );
\ No newline at end of file
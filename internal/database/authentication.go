@@ -43,6 +43,59 @@ func (q *Query) GetAuthorizationByToken(token string) (Authorization, error) {
 	return r, err
 }
 
+// GetUserAuthorizations returns the user's unexpired authorizations for the client.
+func (q *Query) GetUserAuthorizations(clientID string, userID string) ([]Authorization, error) {
+	var r []Authorization
+	err := q.DB.Select(&r, "select * from authorizations where client_id = $1 and user_id = $2", clientID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := util.GetTimestamp()
+	unexpired := []Authorization{}
+	for _, a := range r {
+		expiresAt, err := time.Parse(time.RFC3339Nano, a.ExpiresAt)
+		if err == nil && expiresAt.After(now) {
+			unexpired = append(unexpired, a)
+		}
+	}
+	return unexpired, nil
+}
+
+// IsUserAuthorized returns whether the user has an unexpired authorization for the client.
+func (q *Query) IsUserAuthorized(clientID string, userID string) (bool, error) {
+	auths, err := q.GetUserAuthorizations(clientID, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(auths) > 0, nil
+}
+
+// RevokeAuthorization deletes the authorization with the token, and returns it. The returned authorization is empty
+// if no authorization has the token.
+func (q *Query) RevokeAuthorization(token string) (Authorization, error) {
+	a, err := q.GetAuthorizationByToken(token)
+	if err != nil || a.Token == "" {
+		return a, err
+	}
+
+	_, err = q.DB.Exec("delete from authorizations where token = $1", token)
+	return a, err
+}
+
+// RevokeUserAuthorizations deletes every authorization the user gave the client, as when the user disconnects the
+// client from their account, and returns them.
+func (q *Query) RevokeUserAuthorizations(clientID string, userID string) ([]Authorization, error) {
+	r := []Authorization{}
+	err := q.DB.Select(&r, "select * from authorizations where client_id = $1 and user_id = $2", clientID, userID)
+	if err != nil || len(r) == 0 {
+		return r, err
+	}
+
+	_, err = q.DB.Exec("delete from authorizations where client_id = $1 and user_id = $2", clientID, userID)
+	return r, err
+}
+
 func (q *Query) InsertOrUpdateAuthenticationClient(client AuthenticationClient, upsert bool) (AuthenticationClient, error) {
 	db := q.DB
 
@@ -131,6 +131,61 @@ func TestRetriveFromDB(t *testing.T) {
 
 	a.NotNil(dbResponse)
 	a.Equal("test", dbResponse.Transport)
+	a.Equal("1234", dbResponse.FromUser)
+}
+
+func TestGetAndDeleteEvents(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	q := Query{DB: db.DB}
+	fromUser := util.RandomGUID()
+
+	oldEvent := EventCacheParameters{
+		ID:        util.RandomGUID(),
+		Event:     "cheer",
+		JSON:      `{"subscription":{"type":"channel.cheer"}}`,
+		FromUser:  fromUser,
+		ToUser:    "history_to",
+		Transport: "webhook",
+		Timestamp: "2001-01-01T00:00:00.5Z",
+	}
+	newEvent := EventCacheParameters{
+		ID:        util.RandomGUID(),
+		Event:     "channel.follow",
+		JSON:      `{"subscription":{"type":"channel.follow"}}`,
+		FromUser:  fromUser,
+		ToUser:    "history_to_2",
+		Transport: "websocket",
+		Timestamp: "2001-01-02T00:00:00Z",
+	}
+	a.Nil(q.InsertIntoDB(oldEvent))
+	a.Nil(q.InsertIntoDB(newEvent))
+
+	events, err := q.GetEvents(EventCacheFilter{FromUser: fromUser})
+	a.Nil(err)
+	a.Len(events, 2)
+	a.Equal(newEvent.ID, events[0].ID)
+
+	events, err = q.GetEvents(EventCacheFilter{Event: "channel.cheer", FromUser: fromUser})
+	a.Nil(err)
+	a.Len(events, 1)
+	a.Equal(oldEvent.ID, events[0].ID)
+
+	events, err = q.GetEvents(EventCacheFilter{FromUser: fromUser, Since: "2001-01-01T12:00:00Z"})
+	a.Nil(err)
+	a.Len(events, 1)
+	a.Equal(newEvent.ID, events[0].ID)
+
+	events, err = q.GetEvents(EventCacheFilter{Search: "channel.follow", Transport: "websocket", FromUser: fromUser, Until: "2001-01-03T00:00:00Z", Limit: 1})
+	a.Nil(err)
+	a.Len(events, 1)
+
+	count, err := q.DeleteEventsBefore("2001-01-01T12:00:00Z")
+	a.Nil(err)
+	a.GreaterOrEqual(count, int64(1))
+
+	_, err = q.GetEventByID(oldEvent.ID)
+	a.NotNil(err)
 }
 
 func TestGenerateString(t *testing.T) {
@@ -177,6 +232,42 @@ func TestAuthentication(t *testing.T) {
 	authorization, err := q.GetAuthorizationByToken(auth.Token)
 	a.Nil(err)
 	a.Equal(client.ID, authorization.ClientID)
+
+	userAuth, err := q.CreateAuthorization(Authorization{ClientID: ac.ID, UserID: "1", Scopes: "bits:read"})
+	a.Nil(err)
+	a.NotEmpty(userAuth.Token)
+
+	userAuths, err := q.GetUserAuthorizations(ac.ID, "1")
+	a.Nil(err)
+	a.Len(userAuths, 1)
+	a.Equal("bits:read", userAuths[0].Scopes)
+
+	authorized, err := q.IsUserAuthorized(ac.ID, "1")
+	a.Nil(err)
+	a.True(authorized)
+
+	authorized, err = q.IsUserAuthorized(ac.ID, "2")
+	a.Nil(err)
+	a.False(authorized)
+
+	revoked, err := q.RevokeAuthorization(auth.Token)
+	a.Nil(err)
+	a.Equal(auth.Token, revoked.Token)
+	authorization, err = q.GetAuthorizationByToken(auth.Token)
+	a.Nil(err)
+	a.Empty(authorization.Token)
+	revoked, err = q.RevokeAuthorization(auth.Token)
+	a.Nil(err)
+	a.Empty(revoked.Token)
+
+	_, err = q.CreateAuthorization(Authorization{ClientID: ac.ID, UserID: "1", Scopes: "bits:read"})
+	a.Nil(err)
+	userRevoked, err := q.RevokeUserAuthorizations(ac.ID, "1")
+	a.Nil(err)
+	a.Len(userRevoked, 2)
+	authorized, err = q.IsUserAuthorized(ac.ID, "1")
+	a.Nil(err)
+	a.False(authorized)
 }
 
 func TestAPI(t *testing.T) {
@@ -666,6 +757,15 @@ func TestStreams(t *testing.T) {
 	a.Nil(err)
 	streamTags := dbr.Data.([]StreamMarkerUser)
 	a.GreaterOrEqual(len(streamTags), 1)
+
+	err = q.DeleteStream(s.UserID)
+	a.Nil(err)
+	dbr, err = q.GetStream(Stream{UserID: s.UserID})
+	a.Nil(err)
+	a.Len(dbr.Data.([]Stream), 0)
+	dbr, err = q.GetVideos(Video{ID: v.ID}, "", "")
+	a.Nil(err)
+	a.Len(dbr.Data.([]Video), 1)
 }
 
 func TestSubscriptions(t *testing.T) {
@@ -690,6 +790,44 @@ func TestSubscriptions(t *testing.T) {
 	a.Equal(subs[0].IsGift, true)
 }
 
+func TestEventSubState(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	err := q.ReplaceEventSubState(EventSubState{
+		WebSocketSubscriptions: []EventSubWebSocketSubscription{
+			{SessionID: "abcd1234_efgh5678", SubscriptionID: "1", JSON: `{"SubscriptionID":"1"}`},
+			{SessionID: "abcd1234_efgh5678", PendingReconnect: true, SubscriptionID: "1", JSON: `{"SubscriptionID":"1"}`},
+		},
+		Subscriptions: []EventSubSubscription{
+			{SubscriptionID: "2", Transport: "webhook", JSON: `{"SubscriptionID":"2"}`},
+			{SubscriptionID: "3", Transport: "conduit", JSON: `{"SubscriptionID":"3"}`},
+		},
+		Conduits: []EventSubConduit{{ConduitID: "4", JSON: `{"ID":"4"}`}},
+	})
+	a.Nil(err)
+
+	state, err := q.GetEventSubState()
+	a.Nil(err)
+	a.Len(state.WebSocketSubscriptions, 2)
+	a.False(state.WebSocketSubscriptions[0].PendingReconnect)
+	a.True(state.WebSocketSubscriptions[1].PendingReconnect)
+	a.Len(state.Subscriptions, 2)
+	a.Equal("webhook", state.Subscriptions[0].Transport)
+	a.Equal("conduit", state.Subscriptions[1].Transport)
+	a.Len(state.Conduits, 1)
+	a.Equal(`{"ID":"4"}`, state.Conduits[0].JSON)
+
+	// Saving replaces the previous state
+	err = q.ReplaceEventSubState(EventSubState{WebSocketSubscriptions: []EventSubWebSocketSubscription{{SessionID: "abcd1234_ijkl9012", SubscriptionID: "5", JSON: "{}"}}})
+	a.Nil(err)
+	state, err = q.GetEventSubState()
+	a.Nil(err)
+	a.Len(state.WebSocketSubscriptions, 1)
+	a.Equal("5", state.WebSocketSubscriptions[0].SubscriptionID)
+	a.Empty(state.Subscriptions)
+	a.Empty(state.Conduits)
+}
+
 func TestTeams(t *testing.T) {
 	a := test_setup.SetupTestEnv(t)
 
@@ -787,3 +925,60 @@ func TestVideos(t *testing.T) {
 	err = q.DeleteVideo(vms.VideoID)
 	a.Nil(err)
 }
+
+func TestTables(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	tables, err := q.GetTables()
+	a.Nil(err)
+	names := []string{}
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	a.Contains(names, "users")
+	a.Contains(names, "events")
+
+	users, err := q.GetTableRows("users", 1, 0)
+	a.Nil(err)
+	a.Contains(users.Columns, "user_login")
+	a.Len(users.Rows, 1)
+	a.GreaterOrEqual(users.Total, 1)
+	for _, v := range users.Rows[0] {
+		_, isBytes := v.([]byte)
+		a.False(isBytes)
+	}
+
+	_, err = q.GetTableRows(`users"; drop table users; --`, 1, 0)
+	a.NotNil(err)
+}
+
+func TestTableRowsRedacted(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	client, err := q.InsertOrUpdateAuthenticationClient(AuthenticationClient{ID: util.RandomClientID(), Name: "redacted", Secret: "clientsecret"}, false)
+	a.Nil(err)
+	auth, err := q.CreateAuthorization(Authorization{ClientID: client.ID, UserID: "1", Scopes: ""})
+	a.Nil(err)
+	err = q.ReplaceEventSubState(EventSubState{Subscriptions: []EventSubSubscription{{SubscriptionID: "1", Transport: "webhook", JSON: `{"Secret":"webhooksecret"}`}}})
+	a.Nil(err)
+
+	for table, column := range map[string]string{"clients": "secret", "authorizations": "token", "eventsub_subscriptions": "json"} {
+		rows, err := q.GetTableRows(table, 1000, 0)
+		a.Nil(err)
+		a.NotEmpty(rows.Rows, table)
+
+		index := -1
+		for i, c := range rows.Columns {
+			if c == column {
+				index = i
+			}
+		}
+		a.NotEqual(-1, index, table)
+		for _, row := range rows.Rows {
+			a.Equal(redactedValue, row[index], table)
+			for _, v := range row {
+				a.NotContains([]interface{}{"clientsecret", auth.Token, `{"Secret":"webhooksecret"}`}, v, table)
+			}
+		}
+	}
+}
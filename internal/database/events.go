@@ -2,6 +2,8 @@
 // SPDX-License-Identifier: Apache-2.0
 package database
 
+import "strings"
+
 // EventCacheParameters is used to define required parameters when writing into the database
 type EventCacheParameters struct {
 	ID        string `db:"id"`
@@ -15,11 +17,25 @@ type EventCacheParameters struct {
 
 // EventCacheResponse is used to define the response coming from a SELECT-based function
 type EventCacheResponse struct {
-	ID        string
-	Event     string
-	JSON      string
-	Transport string
-	Timestamp string
+	ID        string `db:"id" json:"id"`
+	Event     string `db:"event" json:"event"`
+	JSON      string `db:"json" json:"json"`
+	FromUser  string `db:"from_user" json:"from_user"`
+	ToUser    string `db:"to_user" json:"to_user"`
+	Transport string `db:"transport" json:"transport"`
+	Timestamp string `db:"timestamp" json:"timestamp"`
+}
+
+// EventCacheFilter is used to narrow down the events returned by GetEvents. Empty fields are ignored.
+type EventCacheFilter struct {
+	Event     string `db:"event"`     // Matches either the trigger used when firing, or the EventSub topic in the payload
+	Transport string `db:"transport"` // Transport the event was fired with
+	FromUser  string `db:"from_user"` // User ID of the sender of the event
+	ToUser    string `db:"to_user"`   // User ID of the receiver of the event
+	Since     string `db:"since"`     // RFC3339 timestamp; only events fired at or after this time
+	Until     string `db:"until"`     // RFC3339 timestamp; only events fired at or before this time
+	Search    string `db:"search"`    // Free text searched for within the event's JSON
+	Limit     int    `db:"limit"`     // Maximum number of events returned; zero returns everything
 }
 
 // InsertIntoDB inserts an event into the database for replay functions later.
@@ -41,10 +57,76 @@ func (q *Query) GetEventByID(id string) (EventCacheResponse, error) {
 	db := q.DB
 	var r EventCacheResponse
 
-	err := db.Get(&r, "select id, json, transport, event from events where id = $1", id)
+	err := db.Get(&r, "select id, json, transport, event, from_user, to_user, timestamp from events where id = $1", id)
 	if err != nil {
 		return r, err
 	}
 
 	return r, err
 }
+
+// GetEvents returns the cached events matching the filter, newest first.
+func (q *Query) GetEvents(f EventCacheFilter) ([]EventCacheResponse, error) {
+	r := []EventCacheResponse{}
+
+	whereClause := []string{}
+	if f.Event != "" {
+		// json_extract errors on malformed JSON, so it's only used on valid payloads
+		whereClause = append(whereClause, "(event = :event or (case when json_valid(json) then json_extract(json, '$.subscription.type') end) = :event)")
+	}
+	if f.Transport != "" {
+		whereClause = append(whereClause, "transport = :transport")
+	}
+	if f.FromUser != "" {
+		whereClause = append(whereClause, "from_user = :from_user")
+	}
+	if f.ToUser != "" {
+		whereClause = append(whereClause, "to_user = :to_user")
+	}
+	if f.Since != "" {
+		whereClause = append(whereClause, "julianday(timestamp) >= julianday(:since)")
+	}
+	if f.Until != "" {
+		whereClause = append(whereClause, "julianday(timestamp) <= julianday(:until)")
+	}
+	if f.Search != "" {
+		f.Search = "%" + f.Search + "%"
+		whereClause = append(whereClause, "json like :search")
+	}
+
+	sql := "select id, event, json, from_user, to_user, transport, timestamp from events"
+	if len(whereClause) > 0 {
+		sql += " where " + strings.Join(whereClause, " "+SEP_AND+" ")
+	}
+	sql += " order by julianday(timestamp) desc"
+	if f.Limit > 0 {
+		sql += " limit :limit"
+	}
+
+	rows, err := q.DB.NamedQuery(sql, f)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e EventCacheResponse
+		err := rows.StructScan(&e)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, e)
+	}
+
+	return r, rows.Err()
+}
+
+// DeleteEventsBefore removes every cached event fired before the given RFC3339 timestamp, returning the number of events removed.
+func (q *Query) DeleteEventsBefore(timestamp string) (int64, error) {
+	res, err := q.DB.Exec("delete from events where julianday(timestamp) < julianday($1)", timestamp)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package database
+
+// EventSubWebSocketSubscription is a subscription of the mock EventSub WebSocket server, saved with --persist-subscriptions
+// so it survives server restarts.
+type EventSubWebSocketSubscription struct {
+	SessionID        string `db:"session_id"`        // Session the subscription was created on, or that's reconnecting if PendingReconnect
+	PendingReconnect bool   `db:"pending_reconnect"` // The subscription carries over to the session reconnecting from SessionID
+	SubscriptionID   string `db:"subscription_id"`
+	JSON             string `db:"json"` // The server's representation of the subscription
+}
+
+// EventSubSubscription is a webhook or conduit subscription of the mock EventSub server, saved with --persist-subscriptions.
+type EventSubSubscription struct {
+	SubscriptionID string `db:"subscription_id"`
+	Transport      string `db:"transport"` // webhook or conduit
+	JSON           string `db:"json"`      // The server's representation of the subscription
+}
+
+// EventSubConduit is a conduit of the mock EventSub server, saved with --persist-subscriptions.
+type EventSubConduit struct {
+	ConduitID string `db:"conduit_id"`
+	JSON      string `db:"json"` // The server's representation of the conduit and its shards
+}
+
+// EventSubState is everything the mock EventSub server saves with --persist-subscriptions.
+type EventSubState struct {
+	WebSocketSubscriptions []EventSubWebSocketSubscription
+	Subscriptions          []EventSubSubscription
+	Conduits               []EventSubConduit
+}
+
+// ReplaceEventSubState replaces the saved state of the mock EventSub server.
+func (q *Query) ReplaceEventSubState(state EventSubState) error {
+	tx, err := q.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"eventsub_websocket_subscriptions", "eventsub_subscriptions", "eventsub_conduits"} {
+		if _, err := tx.Exec(`delete from ` + table); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, s := range state.WebSocketSubscriptions {
+		_, err := tx.NamedExec(`insert into eventsub_websocket_subscriptions(session_id, pending_reconnect, subscription_id, json) values(:session_id, :pending_reconnect, :subscription_id, :json)`, s)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, s := range state.Subscriptions {
+		_, err := tx.NamedExec(`insert into eventsub_subscriptions(subscription_id, transport, json) values(:subscription_id, :transport, :json)`, s)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, c := range state.Conduits {
+		_, err := tx.NamedExec(`insert into eventsub_conduits(conduit_id, json) values(:conduit_id, :json)`, c)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetEventSubState returns the saved state of the mock EventSub server.
+func (q *Query) GetEventSubState() (EventSubState, error) {
+	state := EventSubState{
+		WebSocketSubscriptions: []EventSubWebSocketSubscription{},
+		Subscriptions:          []EventSubSubscription{},
+		Conduits:               []EventSubConduit{},
+	}
+
+	err := q.DB.Select(&state.WebSocketSubscriptions, `select session_id, pending_reconnect, subscription_id, json from eventsub_websocket_subscriptions order by session_id, subscription_id`)
+	if err != nil {
+		return state, err
+	}
+	err = q.DB.Select(&state.Subscriptions, `select subscription_id, transport, json from eventsub_subscriptions order by subscription_id`)
+	if err != nil {
+		return state, err
+	}
+	err = q.DB.Select(&state.Conduits, `select conduit_id, json from eventsub_conduits order by conduit_id`)
+	return state, err
+}
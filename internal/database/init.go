@@ -10,7 +10,7 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-const currentVersion = 7
+const currentVersion = 8
 
 type migrateMap struct {
 	SQL     string
@@ -57,6 +57,10 @@ ALTER TABLE users ADD COLUMN content_labels text not null default '';`,
 		SQL:     `ALTER TABLE stream_schedule DROP COLUMN timezone;`,
 		Message: `Removing deprecated stream_schedule.timezone from database`,
 	},
+	8: {
+		SQL:     `create table eventsub_websocket_subscriptions( session_id text not null, pending_reconnect boolean not null default false, subscription_id text not null, json text not null, primary key (session_id, pending_reconnect, subscription_id) ); create table eventsub_subscriptions( subscription_id text not null primary key, transport text not null, json text not null ); create table eventsub_conduits( conduit_id text not null primary key, json text not null );`,
+		Message: `Adding tables for persisting mock EventSub subscriptions and conduits.`,
+	},
 }
 
 func checkAndUpdate(db sqlx.DB) error {
@@ -126,7 +130,10 @@ create table prediction_predictions ( prediction_id text not null, user_id text
 create table clips ( id text not null primary key, broadcaster_id text not null, creator_id text not null, video_id text not null, game_id text not null, title text not null, view_count int default 0, created_at text not null, duration real not null, vod_offset int default 0, foreign key (broadcaster_id) references users(id), foreign key (creator_id) references users(id) );
 create table stream_schedule( id text not null primary key, broadcaster_id text not null, starttime text not null, endtime text not null, is_vacation boolean not null default false, is_recurring boolean not null default false, is_canceled boolean not null default false, title text, category_id text, foreign key(broadcaster_id) references users(id), foreign key (category_id) references categories(id));
 create table chat_settings( broadcaster_id text not null primary key, slow_mode boolean not null default 0, slow_mode_wait_time int not null default 10, follower_mode boolean not null default 0, follower_mode_duration int not null default 60, subscriber_mode boolean not null default 0, emote_mode boolean not null default 0, unique_chat_mode boolean not null default 0, non_moderator_chat_delay boolean not null default 0, non_moderator_chat_delay_duration int not null default 10, shieldmode_is_active boolean not null default 0, shieldmode_moderator_id text not null default '', shieldmode_moderator_login text not null default '', shieldmode_moderator_name text not null default '', shieldmode_last_activated text not null default '' );
-create table vips ( broadcaster_id text not null, user_id text not null, created_at text not null default '', primary key (broadcaster_id, user_id), foreign key (broadcaster_id) references users(id), foreign key (user_id) references users(id) );`
+create table vips ( broadcaster_id text not null, user_id text not null, created_at text not null default '', primary key (broadcaster_id, user_id), foreign key (broadcaster_id) references users(id), foreign key (user_id) references users(id) );
+create table eventsub_websocket_subscriptions( session_id text not null, pending_reconnect boolean not null default false, subscription_id text not null, json text not null, primary key (session_id, pending_reconnect, subscription_id) );
+create table eventsub_subscriptions( subscription_id text not null primary key, transport text not null, json text not null );
+create table eventsub_conduits( conduit_id text not null primary key, json text not null );`
 
 	for i := 1; i <= 5; i++ {
 		tx := db.MustBegin()
@@ -111,6 +111,24 @@ func (q *Query) InsertStream(p Stream, upsert bool) error {
 	return err
 }
 
+// DeleteStream ends the broadcaster's live streams. Videos of the streams are kept, without their stream ID.
+func (q *Query) DeleteStream(broadcasterID string) error {
+	tx, err := q.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`update videos set stream_id = null where stream_id in (select id from streams where broadcaster_id = $1)`, broadcasterID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`delete from streams where broadcaster_id = $1`, broadcasterID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 func (q *Query) GetTags(t Tag) (*DBResponse, error) {
 	r := []Tag{}
 	sql := generateSQL("select * from tags", t, SEP_AND)
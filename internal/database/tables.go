@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package database
+
+import "fmt"
+
+// Table is a table of the CLI's database, as listed by the mock servers' dashboard.
+type Table struct {
+	Name string `db:"name" json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// Value shown in place of secrets and tokens
+const redactedValue = "[redacted]"
+
+// Columns holding client secrets and tokens by table. The dashboard serving the tables has no authentication, so their
+// values are never returned. The saved EventSub subscriptions and conduits include tokens and webhook secrets in their
+// json column.
+var redactedColumns = map[string][]string{
+	"clients":                          {"secret"},
+	"authorizations":                   {"token"},
+	"eventsub_websocket_subscriptions": {"json"},
+	"eventsub_subscriptions":           {"json"},
+	"eventsub_conduits":                {"json"},
+}
+
+// TableRows is a page of the rows of a table. Values are strings, numbers, or nil.
+type TableRows struct {
+	Table   string          `json:"table"`
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	Total   int             `json:"total"`
+}
+
+// GetTables returns the tables of the database, sorted by name, with their row counts.
+func (q *Query) GetTables() ([]Table, error) {
+	tables := []Table{}
+	err := q.DB.Select(&tables, `select name from sqlite_master where type = 'table' and name not like 'sqlite_%' order by name`)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tables {
+		// Table names come from sqlite_master, so they're safe to quote into the query
+		if err := q.DB.Get(&tables[i].Rows, fmt.Sprintf(`select count(*) from "%v"`, tables[i].Name)); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+// GetTableRows returns up to limit rows of the table, starting at offset, in the order they were inserted. Secrets and
+// tokens are redacted. Returns an error if the table doesn't exist.
+func (q *Query) GetTableRows(table string, limit int, offset int) (TableRows, error) {
+	tables, err := q.GetTables()
+	if err != nil {
+		return TableRows{}, err
+	}
+	r := TableRows{Table: table, Rows: [][]interface{}{}}
+	found := false
+	for _, t := range tables {
+		if t.Name == table {
+			found = true
+			r.Total = t.Rows
+		}
+	}
+	if !found {
+		return TableRows{}, fmt.Errorf("Table [%v] does not exist", table)
+	}
+
+	rows, err := q.DB.Queryx(fmt.Sprintf(`select * from "%v" order by rowid limit $1 offset $2`, table), limit, offset)
+	if err != nil {
+		return TableRows{}, err
+	}
+	defer rows.Close()
+
+	r.Columns, err = rows.Columns()
+	if err != nil {
+		return TableRows{}, err
+	}
+	redacted := map[int]bool{}
+	for i, column := range r.Columns {
+		for _, c := range redactedColumns[table] {
+			if column == c {
+				redacted[i] = true
+			}
+		}
+	}
+
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return TableRows{}, err
+		}
+		for i, v := range values {
+			// Text columns are scanned as bytes, which would be encoded as base64 in JSON
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+			if redacted[i] && v != nil {
+				values[i] = redactedValue
+			}
+		}
+		r.Rows = append(r.Rows, values)
+	}
+
+	return r, rows.Err()
+}
@@ -18,3 +18,20 @@ func ValidTransports() []string {
 
 	return names
 }
+
+// ValidRevocationReasons returns the subscription statuses that can be sent with revocation messages.
+func ValidRevocationReasons() []string {
+	names := []string{}
+
+	for name := range revocationReasons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// IsValidRevocationReason returns whether status can be sent with a revocation message.
+func IsValidRevocationReason(status string) bool {
+	return revocationReasons[status]
+}
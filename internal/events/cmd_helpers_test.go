@@ -14,3 +14,12 @@ func TestValidTransports(t *testing.T) {
 	t1 := ValidTransports()
 	a.NotEmpty(t1)
 }
+
+func TestValidRevocationReasons(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	a.Contains(ValidRevocationReasons(), SubscriptionStatusAuthorizationRevoked)
+	a.True(IsValidRevocationReason(SubscriptionStatusModeratorRemoved))
+	a.False(IsValidRevocationReason(SubscriptionStatusEnabled))
+	a.False(IsValidRevocationReason("revoked"))
+}
@@ -11,12 +11,13 @@ import (
 type EventConfigurationParams struct {
 	Secret         string
 	ForwardAddress string
+	Retention      string
 }
 
 func ConfigureEvents(p EventConfigurationParams) error {
 	var err error
-	if p.ForwardAddress == "" && p.Secret == "" {
-		return fmt.Errorf("you must provide at least one of --secret or --forward-address")
+	if p.ForwardAddress == "" && p.Secret == "" && p.Retention == "" {
+		return fmt.Errorf("you must provide at least one of --secret, --forward-address, or --retention")
 	}
 
 	// Validate that the forward address is actually a URL
@@ -33,6 +34,12 @@ func ConfigureEvents(p EventConfigurationParams) error {
 		}
 		viper.Set("eventSecret", p.Secret)
 	}
+	if p.Retention != "" {
+		if _, err := util.ParseRelativeDuration(p.Retention); err != nil {
+			return err
+		}
+		viper.Set("eventRetention", p.Retention)
+	}
 
 	configPath, err := util.GetConfigPath()
 	if err != nil {
@@ -54,5 +61,6 @@ func GetEventConfiguration(noConfig bool) EventConfigurationParams {
 	return EventConfigurationParams{
 		ForwardAddress: viper.GetString("forwardAddress"),
 		Secret:         viper.GetString("eventSecret"),
+		Retention:      viper.GetString("eventRetention"),
 	}
 }
@@ -33,4 +33,14 @@ func TestWriteEventConfig(t *testing.T) {
 	test_config.ForwardAddress = "not a url"
 	a.Error(configure_event.ConfigureEvents(test_config))
 	a.NotEqual("not a url", viper.Get("forwardAddress"))
+	test_config.ForwardAddress = defaultForwardAddress
+
+	// test for retention validation
+	test_config.Retention = "30d"
+	a.NoError(configure_event.ConfigureEvents(test_config))
+	a.Equal("30d", viper.Get("eventRetention"))
+
+	test_config.Retention = "a month"
+	a.Error(configure_event.ConfigureEvents(test_config))
+	a.Equal("30d", viper.Get("eventRetention"))
 }
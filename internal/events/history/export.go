@@ -0,0 +1,216 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+// Events can be quite large, so lines are allowed to be well above bufio's default of 64KB
+const maxImportLineSize = 10 * 1024 * 1024
+
+// ExportedEvent is a single line of an NDJSON export of the events cache.
+type ExportedEvent struct {
+	ID        string            `json:"id"`
+	Event     string            `json:"event"`
+	Topic     string            `json:"topic"`
+	Version   string            `json:"version"`
+	Transport string            `json:"transport"`
+	FromUser  string            `json:"from_user"`
+	ToUser    string            `json:"to_user"`
+	Timestamp string            `json:"timestamp"`
+	Headers   map[string]string `json:"headers"`
+	JSON      json.RawMessage   `json:"json,omitempty"`
+	RawJSON   string            `json:"raw_json,omitempty"` // Set instead of JSON when the cached payload isn't valid JSON
+}
+
+// ImportResult summarizes an import into the events cache.
+type ImportResult struct {
+	Imported int
+	Skipped  int // Events whose ID already exists in the events cache
+}
+
+// ExportEvents writes the cached events matching the given filters as NDJSON, oldest first, returning the number of events written.
+func ExportEvents(w io.Writer, p HistoryParameters) (int, error) {
+	entries, err := GetEvents(p)
+	if err != nil {
+		return 0, err
+	}
+
+	// GetEvents returns the newest events first; Exports keep the order events were fired in so they can be replayed as-is
+	encoder := json.NewEncoder(w)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		exported := ExportedEvent{
+			ID:        e.ID,
+			Event:     e.Event,
+			Topic:     e.Topic,
+			Version:   e.Version,
+			Transport: e.Transport,
+			FromUser:  e.FromUser,
+			ToUser:    e.ToUser,
+			Timestamp: e.Timestamp,
+			Headers:   exportHeaders(e),
+			JSON:      e.Payload,
+		}
+		if e.invalidPayload != "" {
+			// Exported as-is, so importing it caches the same payload
+			exported.JSON = nil
+			exported.RawJSON = e.invalidPayload
+		}
+
+		err := encoder.Encode(exported)
+		if err != nil {
+			return len(entries) - 1 - i, err
+		}
+	}
+
+	return len(entries), nil
+}
+
+// ImportEvents reads NDJSON written by ExportEvents into the events cache.
+// Missing IDs and timestamps are taken from the Twitch-Eventsub-Message-Id and Twitch-Eventsub-Message-Timestamp headers when available.
+func ImportEvents(r io.Reader) (ImportResult, error) {
+	result := ImportResult{}
+
+	db, err := database.NewConnection(false)
+	if err != nil {
+		return result, err
+	}
+	q := db.NewQuery(nil, 100)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var e ExportedEvent
+		err := json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			return result, fmt.Errorf("Invalid event on line %v: %v", line, err)
+		}
+
+		p, err := newEventCacheParameters(e)
+		if err != nil {
+			return result, fmt.Errorf("Invalid event on line %v: %v", line, err)
+		}
+
+		if _, err := q.GetEventByID(p.ID); err == nil {
+			result.Skipped++
+			continue
+		}
+
+		err = q.InsertIntoDB(p)
+		if err != nil {
+			return result, fmt.Errorf("Unable to import event on line %v: %v", line, err)
+		}
+		result.Imported++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("Unable to read line %v: %v", line+1, err)
+	}
+
+	return result, nil
+}
+
+func newEventCacheParameters(e ExportedEvent) (database.EventCacheParameters, error) {
+	payload := string(e.JSON)
+	if len(e.JSON) == 0 && e.RawJSON != "" {
+		payload = e.RawJSON
+	} else if len(e.JSON) == 0 || !json.Valid(e.JSON) {
+		return database.EventCacheParameters{}, fmt.Errorf("json must be a valid JSON payload")
+	}
+
+	var body models.EventsubResponse
+	json.Unmarshal(e.JSON, &body)
+
+	p := database.EventCacheParameters{
+		ID:        e.ID,
+		Event:     e.Event,
+		JSON:      payload,
+		FromUser:  e.FromUser,
+		ToUser:    e.ToUser,
+		Transport: e.Transport,
+		Timestamp: e.Timestamp,
+	}
+
+	if p.ID == "" {
+		p.ID = getHeader(e.Headers, "Twitch-Eventsub-Message-Id")
+		if p.ID == "" {
+			p.ID = util.RandomGUID()
+		}
+	}
+
+	if p.Event == "" {
+		p.Event = e.Topic
+		if p.Event == "" {
+			p.Event = body.Subscription.Type
+		}
+		if p.Event == "" {
+			return p, fmt.Errorf("one of event, topic, or json.subscription.type must be set")
+		}
+	}
+
+	if p.Transport == "" {
+		p.Transport = models.TransportWebhook
+	}
+
+	if p.Timestamp == "" {
+		p.Timestamp = getHeader(e.Headers, "Twitch-Eventsub-Message-Timestamp")
+		if p.Timestamp == "" {
+			p.Timestamp = util.GetTimestamp().Format(time.RFC3339Nano)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, p.Timestamp); err != nil {
+		return p, fmt.Errorf("timestamp must be an RFC3339 timestamp")
+	}
+
+	return p, nil
+}
+
+// Builds the headers the event is sent with when forwarded over webhooks.
+func exportHeaders(e HistoryEntry) map[string]string {
+	if e.Transport != models.TransportWebhook {
+		return map[string]string{}
+	}
+
+	messageType := "notification"
+	var body models.EventsubResponse
+	if err := json.Unmarshal(e.Payload, &body); err == nil && body.Subscription.Status != "" && body.Subscription.Status != "enabled" {
+		messageType = "revocation"
+	}
+
+	return map[string]string{
+		"Twitch-Eventsub-Message-Id":           e.ID,
+		"Twitch-Eventsub-Message-Retry":        "0",
+		"Twitch-Eventsub-Message-Type":         messageType,
+		"Twitch-Eventsub-Message-Timestamp":    e.Timestamp,
+		"Twitch-Eventsub-Subscription-Type":    e.Topic,
+		"Twitch-Eventsub-Subscription-Version": e.Version,
+	}
+}
+
+// HTTP headers are case-insensitive, and hand-written fixtures may not use the canonical casing
+func getHeader(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
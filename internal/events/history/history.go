@@ -0,0 +1,201 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+// HistoryParameters defines the filters used when browsing the events cache.
+type HistoryParameters struct {
+	Event     string
+	Transport string
+	FromUser  string
+	ToUser    string
+	Since     string // RFC3339 timestamp or relative time (e.g. 2h30m)
+	Until     string // RFC3339 timestamp or relative time (e.g. 2h30m)
+	Search    string
+	Limit     int
+}
+
+// HistoryEntry is the output format of a cached event.
+type HistoryEntry struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	Topic     string          `json:"topic"`
+	Version   string          `json:"version"`
+	Transport string          `json:"transport"`
+	FromUser  string          `json:"from_user"`
+	ToUser    string          `json:"to_user"`
+	Timestamp string          `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload"`
+
+	invalidPayload string // The cached payload, when it isn't valid JSON and Payload holds it as a JSON string
+}
+
+// GetEvents returns the cached events matching the given filters, newest first.
+func GetEvents(p HistoryParameters) ([]HistoryEntry, error) {
+	filter := database.EventCacheFilter{
+		Event:     resolveTopic(p.Event),
+		Transport: p.Transport,
+		FromUser:  p.FromUser,
+		ToUser:    p.ToUser,
+		Search:    p.Search,
+		Limit:     p.Limit,
+	}
+
+	if p.Since != "" {
+		since, err := util.ParseTimestampOrRelative(p.Since)
+		if err != nil {
+			return nil, err
+		}
+		filter.Since = since.Format(time.RFC3339Nano)
+	}
+
+	if p.Until != "" {
+		until, err := util.ParseTimestampOrRelative(p.Until)
+		if err != nil {
+			return nil, err
+		}
+		filter.Until = until.Format(time.RFC3339Nano)
+	}
+
+	db, err := database.NewConnection(false)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.NewQuery(nil, 100).GetEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []HistoryEntry{}
+	for _, e := range res {
+		entries = append(entries, newHistoryEntry(e))
+	}
+
+	return entries, nil
+}
+
+// GetEvent returns a single cached event by its ID.
+func GetEvent(id string) (HistoryEntry, error) {
+	db, err := database.NewConnection(false)
+	if err != nil {
+		return HistoryEntry{}, err
+	}
+
+	res, err := db.NewQuery(nil, 100).GetEventByID(id)
+	if err != nil {
+		return HistoryEntry{}, fmt.Errorf("Unable to find event [%v] in the events cache: %v", id, err)
+	}
+
+	return newHistoryEntry(res), nil
+}
+
+// PruneEvents deletes every cached event older than the given RFC3339 timestamp or relative time, returning the number of events removed.
+func PruneEvents(olderThan string) (int64, error) {
+	before, err := util.ParseTimestampOrRelative(olderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err := database.NewConnection(false)
+	if err != nil {
+		return 0, err
+	}
+
+	return db.NewQuery(nil, 100).DeleteEventsBefore(before.Format(time.RFC3339Nano))
+}
+
+// ApplyRetention prunes the events cache according to the retention set with `twitch event configure --retention`.
+// Does nothing when no retention is configured.
+func ApplyRetention(q *database.Query) error {
+	retention := viper.GetString("eventRetention")
+	if retention == "" {
+		return nil
+	}
+
+	d, err := util.ParseRelativeDuration(retention)
+	if err != nil {
+		return fmt.Errorf("Invalid event retention in configuration: %v", err)
+	}
+
+	_, err = q.DeleteEventsBefore(util.GetTimestamp().Add(-d).Format(time.RFC3339Nano))
+	return err
+}
+
+// PrintTable writes the given entries as a table.
+func PrintTable(w io.Writer, entries []HistoryEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTIMESTAMP\tTOPIC\tVERSION\tTRANSPORT\tFROM USER\tTO USER")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", e.ID, e.Timestamp, e.Topic, e.Version, e.Transport, e.FromUser, e.ToUser)
+	}
+	tw.Flush()
+}
+
+// PrintJSON writes the given value as indented JSON.
+func PrintJSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func newHistoryEntry(e database.EventCacheResponse) HistoryEntry {
+	entry := HistoryEntry{
+		ID:        e.ID,
+		Event:     e.Event,
+		Topic:     e.Event,
+		Transport: e.Transport,
+		FromUser:  e.FromUser,
+		ToUser:    e.ToUser,
+		Timestamp: e.Timestamp,
+		Payload:   json.RawMessage(e.JSON),
+	}
+
+	var body models.EventsubResponse
+	if err := json.Unmarshal([]byte(e.JSON), &body); err == nil {
+		if body.Subscription.Type != "" {
+			entry.Topic = body.Subscription.Type
+		}
+		entry.Version = body.Subscription.Version
+	} else {
+		// Keeps the output valid JSON even if the cached payload isn't
+		entry.Payload, _ = json.Marshal(e.JSON)
+		entry.invalidPayload = e.JSON
+	}
+
+	return entry
+}
+
+// Resolves aliases (e.g. "cheer") to their EventSub topic (e.g. "channel.cheer") so both can be used as filters.
+func resolveTopic(event string) string {
+	if event == "" {
+		return ""
+	}
+
+	for _, e := range types.AllEvents() {
+		if e.ValidTrigger(event) {
+			if topic := e.GetTopic(models.TransportWebhook, event); topic != "" {
+				return topic
+			}
+		}
+	}
+
+	return event
+}
@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+// Uses its own database, as pruning would otherwise remove events other packages are testing with
+func setupHistoryTestEnv(t *testing.T) *assert.Assertions {
+	a := test_setup.SetupTestEnv(t)
+	viper.Set("DB_FILENAME", "test-history-eventCache.db")
+	return a
+}
+
+func insertTestEvent(t *testing.T, event string, json string, fromUser string, timestamp time.Time) string {
+	a := setupHistoryTestEnv(t)
+
+	db, err := database.NewConnection(true)
+	a.Nil(err)
+
+	id := util.RandomGUID()
+	err = db.NewQuery(nil, 100).InsertIntoDB(database.EventCacheParameters{
+		ID:        id,
+		Event:     event,
+		JSON:      json,
+		FromUser:  fromUser,
+		ToUser:    "history_test_to",
+		Transport: "webhook",
+		Timestamp: timestamp.Format(time.RFC3339Nano),
+	})
+	a.Nil(err)
+
+	return id
+}
+
+func TestGetEvents(t *testing.T) {
+	a := setupHistoryTestEnv(t)
+
+	fromUser := util.RandomGUID()
+	id := insertTestEvent(t, "cheer", `{"subscription":{"type":"channel.cheer","version":"1"},"event":{}}`, fromUser, util.GetTimestamp())
+
+	entries, err := GetEvents(HistoryParameters{Event: "cheer", FromUser: fromUser, Since: "1h"})
+	a.Nil(err)
+	a.Len(entries, 1)
+	a.Equal(id, entries[0].ID)
+	a.Equal("channel.cheer", entries[0].Topic)
+	a.Equal("1", entries[0].Version)
+
+	entries, err = GetEvents(HistoryParameters{Event: "channel.cheer", FromUser: fromUser, Until: "1h"})
+	a.Nil(err)
+	a.Empty(entries)
+
+	_, err = GetEvents(HistoryParameters{Since: "not a time"})
+	a.NotNil(err)
+
+	entry, err := GetEvent(id)
+	a.Nil(err)
+	a.Equal(id, entry.ID)
+
+	var b bytes.Buffer
+	PrintTable(&b, []HistoryEntry{entry})
+	a.Contains(b.String(), id)
+
+	b.Reset()
+	a.Nil(PrintJSON(&b, entry))
+	var decoded HistoryEntry
+	a.Nil(json.Unmarshal(b.Bytes(), &decoded))
+	a.Equal(id, decoded.ID)
+}
+
+func TestPruneEvents(t *testing.T) {
+	a := setupHistoryTestEnv(t)
+
+	oldID := insertTestEvent(t, "channel.follow", `{"subscription":{"type":"channel.follow","version":"2"}}`, util.RandomGUID(), util.GetTimestamp().Add(-48*time.Hour))
+	newID := insertTestEvent(t, "channel.follow", `{"subscription":{"type":"channel.follow","version":"2"}}`, util.RandomGUID(), util.GetTimestamp())
+
+	count, err := PruneEvents("1d")
+	a.Nil(err)
+	a.GreaterOrEqual(count, int64(1))
+
+	_, err = GetEvent(oldID)
+	a.NotNil(err)
+	_, err = GetEvent(newID)
+	a.Nil(err)
+
+	_, err = PruneEvents("forever")
+	a.NotNil(err)
+}
+
+func TestApplyRetention(t *testing.T) {
+	a := setupHistoryTestEnv(t)
+
+	db, err := database.NewConnection(true)
+	a.Nil(err)
+
+	oldID := insertTestEvent(t, "channel.follow", `{"subscription":{"type":"channel.follow","version":"2"}}`, util.RandomGUID(), util.GetTimestamp().Add(-2*time.Hour))
+
+	viper.Set("eventRetention", "")
+	a.Nil(ApplyRetention(db.NewQuery(nil, 100)))
+	_, err = GetEvent(oldID)
+	a.Nil(err)
+
+	viper.Set("eventRetention", "1h")
+	defer viper.Set("eventRetention", "")
+	a.Nil(ApplyRetention(db.NewQuery(nil, 100)))
+	_, err = GetEvent(oldID)
+	a.NotNil(err)
+}
+
+func TestExportImportEvents(t *testing.T) {
+	a := setupHistoryTestEnv(t)
+
+	fromUser := util.RandomGUID()
+	id := insertTestEvent(t, "cheer", `{"subscription":{"type":"channel.cheer","version":"1","status":"enabled"},"event":{}}`, fromUser, util.GetTimestamp())
+
+	var b bytes.Buffer
+	count, err := ExportEvents(&b, HistoryParameters{FromUser: fromUser})
+	a.Nil(err)
+	a.Equal(1, count)
+
+	var exported ExportedEvent
+	a.Nil(json.Unmarshal(b.Bytes(), &exported))
+	a.Equal(id, exported.ID)
+	a.Equal("channel.cheer", exported.Topic)
+	a.Equal("notification", exported.Headers["Twitch-Eventsub-Message-Type"])
+
+	// Already in the cache
+	result, err := ImportEvents(bytes.NewReader(b.Bytes()))
+	a.Nil(err)
+	a.Equal(0, result.Imported)
+	a.Equal(1, result.Skipped)
+
+	newID := util.RandomGUID()
+	fixture := `{"topic":"channel.cheer","headers":{"twitch-eventsub-message-id":"` + newID + `"},"json":{"subscription":{"type":"channel.cheer","version":"1"}}}` + "\n\n"
+	result, err = ImportEvents(bytes.NewBufferString(fixture))
+	a.Nil(err)
+	a.Equal(1, result.Imported)
+
+	entry, err := GetEvent(newID)
+	a.Nil(err)
+	a.Equal("channel.cheer", entry.Event)
+	a.Equal("webhook", entry.Transport)
+
+	_, err = ImportEvents(bytes.NewBufferString(`{"json":{}}`))
+	a.NotNil(err)
+
+	_, err = ImportEvents(bytes.NewBufferString(`{"topic":"channel.cheer","json":"not json`))
+	a.NotNil(err)
+}
+
+func TestExportImportInvalidPayload(t *testing.T) {
+	a := setupHistoryTestEnv(t)
+
+	fromUser := util.RandomGUID()
+	id := insertTestEvent(t, "cheer", `{"not json`, fromUser, util.GetTimestamp())
+
+	var b bytes.Buffer
+	count, err := ExportEvents(&b, HistoryParameters{FromUser: fromUser})
+	a.Nil(err)
+	a.Equal(1, count)
+
+	var exported ExportedEvent
+	a.Nil(json.Unmarshal(b.Bytes(), &exported))
+	a.Empty(exported.JSON)
+	a.Equal(`{"not json`, exported.RawJSON)
+
+	// Imported under a new ID, so the payload is written again rather than skipped
+	newID := util.RandomGUID()
+	exported.ID = newID
+	line, err := json.Marshal(exported)
+	a.Nil(err)
+
+	result, err := ImportEvents(bytes.NewReader(line))
+	a.Nil(err)
+	a.Equal(1, result.Imported)
+
+	db, err := database.NewConnection(true)
+	a.Nil(err)
+	original, err := db.NewQuery(nil, 100).GetEventByID(id)
+	a.Nil(err)
+	imported, err := db.NewQuery(nil, 100).GetEventByID(newID)
+	a.Nil(err)
+	a.Equal(original.JSON, imported.JSON)
+}
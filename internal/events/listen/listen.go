@@ -0,0 +1,276 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package listen
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+const (
+	messageTypeNotification = "notification"
+	messageTypeVerification = "webhook_callback_verification"
+	messageTypeRevocation   = "revocation"
+)
+
+// Twitch recommends rejecting messages older than this to prevent replay attacks. The receiver only warns, since
+// "twitch event retrigger" reuses the original timestamps by default.
+const maxMessageAge = 10 * time.Minute
+
+// ListenParameters defines the parameters used to start the webhook receiver.
+type ListenParameters struct {
+	Port   int
+	Secret string // Validates the signature of each message when set
+	Store  bool   // Stores received notifications and revocations in the events cache
+	Raw    bool   // Prints each payload as received instead of pretty-printing it
+}
+
+// Receiver is an EventSub webhook consumer, answering verification challenges and validating signatures the way
+// an EventSub webhook callback should.
+type Receiver struct {
+	Secret string
+	Store  bool
+	Raw    bool
+	Output io.Writer
+
+	seenMessageIDs map[string]bool // Used to detect duplicate messages
+	muSeen         sync.Mutex      // Mutex for Receiver.seenMessageIDs
+	muOutput       sync.Mutex      // Keeps output of concurrent requests from interleaving
+}
+
+func NewReceiver(p ListenParameters, output io.Writer) *Receiver {
+	return &Receiver{
+		Secret:         p.Secret,
+		Store:          p.Store,
+		Raw:            p.Raw,
+		Output:         output,
+		seenMessageIDs: map[string]bool{},
+	}
+}
+
+// StartListener starts the webhook receiver, blocking until Ctrl+C is pressed.
+func StartListener(p ListenParameters) error {
+	s := http.Server{
+		Addr:    fmt.Sprintf(":%v", p.Port),
+		Handler: NewReceiver(p, os.Stdout),
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+
+	var serverErr error = nil
+
+	go func() {
+		log.Printf("Listening for EventSub webhooks on http://localhost:%v", p.Port)
+		if p.Secret == "" {
+			color.New().Add(color.FgYellow).Println("No secret provided; Signatures will not be validated.")
+		}
+
+		if err := s.ListenAndServe(); err != nil {
+			if err != http.ErrServerClosed {
+				serverErr = err
+				stop <- syscall.SIGINT // Simulate Ctrl+C
+			}
+		}
+	}()
+
+	<-stop
+
+	if serverErr != nil {
+		return serverErr
+	}
+
+	log.Print("shutting down ...\n")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*5))
+	defer cancel()
+
+	return s.Shutdown(ctx)
+}
+
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	messageID := r.Header.Get("Twitch-Eventsub-Message-Id")
+	messageType := r.Header.Get("Twitch-Eventsub-Message-Type")
+	timestamp := r.Header.Get("Twitch-Eventsub-Message-Timestamp")
+
+	if messageID == "" || messageType == "" {
+		rc.printError("Received request missing the Twitch-Eventsub-Message-Id or Twitch-Eventsub-Message-Type header")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if rc.Secret != "" && !ValidSignature(rc.Secret, messageID, timestamp, body, r.Header.Get("Twitch-Eventsub-Message-Signature")) {
+		rc.printError(fmt.Sprintf("Invalid signature for message [%v]", messageID))
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var eventObj models.EventsubResponse
+	err = json.Unmarshal(body, &eventObj)
+	if err != nil {
+		rc.printError(fmt.Sprintf("Message [%v] is not valid JSON: %v", messageID, err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch messageType {
+	case messageTypeVerification:
+		var challenge models.EventsubSubscriptionVerification
+		json.Unmarshal(body, &challenge)
+
+		rc.printMessage(messageType, messageID, timestamp, eventObj, body)
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(challenge.Challenge))
+	case messageTypeNotification, messageTypeRevocation:
+		if rc.isDuplicate(messageID) {
+			// Twitch may resend messages; Receivers should acknowledge and ignore them
+			rc.printWarning(fmt.Sprintf("Ignoring duplicate message [%v]", messageID))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if ts, err := time.Parse(time.RFC3339Nano, timestamp); err == nil && time.Since(ts) > maxMessageAge {
+			rc.printWarning(fmt.Sprintf("Message [%v] is older than %v; Production receivers should reject it", messageID, maxMessageAge))
+		}
+
+		rc.printMessage(messageType, messageID, timestamp, eventObj, body)
+
+		if rc.Store {
+			err := storeEvent(messageID, timestamp, eventObj, body)
+			if err != nil {
+				rc.printError(fmt.Sprintf("Unable to store message [%v] in the events cache: %v", messageID, err))
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		rc.printError(fmt.Sprintf("Unknown message type [%v] for message [%v]", messageType, messageID))
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// ValidSignature checks the Twitch-Eventsub-Message-Signature header of a webhook message.
+func ValidSignature(secret string, messageID string, timestamp string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(messageID + timestamp))
+	mac.Write(body)
+	expected := fmt.Sprintf("sha256=%x", mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (rc *Receiver) isDuplicate(messageID string) bool {
+	rc.muSeen.Lock()
+	defer rc.muSeen.Unlock()
+
+	if rc.seenMessageIDs[messageID] {
+		return true
+	}
+	rc.seenMessageIDs[messageID] = true
+	return false
+}
+
+func (rc *Receiver) printMessage(messageType string, messageID string, timestamp string, eventObj models.EventsubResponse, body []byte) {
+	rc.muOutput.Lock()
+	defer rc.muOutput.Unlock()
+
+	color.New().Add(color.FgGreen).Fprintf(rc.Output, "✔ [%v] %v %v v%v (%v)\n", timestamp, messageType, eventObj.Subscription.Type, eventObj.Subscription.Version, messageID)
+
+	if rc.Raw {
+		fmt.Fprintln(rc.Output, string(body))
+		return
+	}
+
+	var pretty bytes.Buffer
+	json.Indent(&pretty, body, "", "  ")
+	fmt.Fprintln(rc.Output, pretty.String())
+}
+
+func (rc *Receiver) printWarning(msg string) {
+	rc.muOutput.Lock()
+	defer rc.muOutput.Unlock()
+	color.New().Add(color.FgYellow).Fprintf(rc.Output, "! %v\n", msg)
+}
+
+func (rc *Receiver) printError(msg string) {
+	rc.muOutput.Lock()
+	defer rc.muOutput.Unlock()
+	color.New().Add(color.FgRed).Fprintf(rc.Output, "✗ %v\n", msg)
+}
+
+func storeEvent(messageID string, timestamp string, eventObj models.EventsubResponse, body []byte) error {
+	db, err := database.NewConnection(false)
+	if err != nil {
+		return err
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, timestamp); err != nil {
+		timestamp = util.GetTimestamp().Format(time.RFC3339Nano)
+	}
+
+	// Events sent with "twitch event trigger -F" were cached under the same ID when they were fired
+	if _, err := db.NewQuery(nil, 100).GetEventByID(messageID); err == nil {
+		return nil
+	}
+
+	fromUser, toUser := eventUsers(body)
+
+	return db.NewQuery(nil, 100).InsertIntoDB(database.EventCacheParameters{
+		ID:        messageID,
+		Event:     eventObj.Subscription.Type,
+		JSON:      string(body),
+		FromUser:  fromUser,
+		ToUser:    toUser,
+		Transport: models.TransportWebhook,
+		Timestamp: timestamp,
+	})
+}
+
+// Finds the users involved in an event, matching the columns events fired with "twitch event trigger" are stored with.
+func eventUsers(body []byte) (string, string) {
+	var payload struct {
+		Event map[string]interface{} `json:"event"`
+	}
+	json.Unmarshal(body, &payload)
+
+	return firstString(payload.Event, "user_id", "from_broadcaster_user_id"),
+		firstString(payload.Event, "broadcaster_user_id", "to_broadcaster_user_id")
+}
+
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := m[k].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package listen
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+var secret = "potatopotato"
+
+func forward(a string, id string, messageType string, secret string, body string) (*http.Response, error) {
+	return trigger.ForwardEvent(trigger.ForwardParamters{
+		ID:                  id,
+		ForwardAddress:      a,
+		JSON:                []byte(body),
+		Transport:           models.TransportWebhook,
+		Timestamp:           util.GetTimestamp().Format(time.RFC3339Nano),
+		Secret:              secret,
+		Event:               "channel.follow",
+		Type:                messageType,
+		SubscriptionVersion: "2",
+	})
+}
+
+func TestReceiver(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	var output bytes.Buffer
+	ts := httptest.NewServer(NewReceiver(ListenParameters{Secret: secret, Store: true}, &output))
+	defer ts.Close()
+
+	// Verification challenge
+	resp, err := forward(ts.URL, util.RandomGUID(), trigger.EventSubMessageTypeVerification, secret, `{"challenge":"test_challenge","subscription":{"type":"channel.follow","version":"2"}}`)
+	a.Nil(err)
+	challenge, _ := io.ReadAll(resp.Body)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	a.Equal("test_challenge", string(challenge))
+
+	// Notification, which is stored in the events cache
+	id := util.RandomGUID()
+	body := `{"subscription":{"type":"channel.follow","version":"2"},"event":{"user_id":"1234","broadcaster_user_id":"5678"}}`
+	resp, err = forward(ts.URL, id, trigger.EventSubMessageTypeNotification, secret, body)
+	a.Nil(err)
+	a.Equal(http.StatusNoContent, resp.StatusCode)
+	a.Contains(output.String(), id)
+
+	db, err := database.NewConnection(false)
+	a.Nil(err)
+	stored, err := db.NewQuery(nil, 100).GetEventByID(id)
+	a.Nil(err)
+	a.Equal("channel.follow", stored.Event)
+	a.Equal("1234", stored.FromUser)
+	a.Equal("5678", stored.ToUser)
+
+	// Duplicate
+	resp, err = forward(ts.URL, id, trigger.EventSubMessageTypeNotification, secret, body)
+	a.Nil(err)
+	a.Equal(http.StatusNoContent, resp.StatusCode)
+	a.Contains(output.String(), "duplicate")
+
+	// Bad signature
+	resp, err = forward(ts.URL, util.RandomGUID(), trigger.EventSubMessageTypeNotification, "wrongsecret", body)
+	a.Nil(err)
+	a.Equal(http.StatusForbidden, resp.StatusCode)
+
+	// Unsigned
+	resp, err = forward(ts.URL, util.RandomGUID(), trigger.EventSubMessageTypeNotification, "", body)
+	a.Nil(err)
+	a.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func TestReceiverStoresTriggeredEvent(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	var output bytes.Buffer
+	ts := httptest.NewServer(NewReceiver(ListenParameters{Secret: secret, Store: true}, &output))
+	defer ts.Close()
+
+	// Fire caches the event, then forwards it under the same message ID
+	id := util.RandomGUID()
+	_, err := trigger.Fire(trigger.TriggerParameters{
+		EventMessageID: id,
+		Event:          "channel.follow",
+		Transport:      models.TransportWebhook,
+		ForwardAddress: ts.URL,
+		Secret:         secret,
+		FromUser:       "1234",
+		ToUser:         "5678",
+	})
+	a.Nil(err)
+	a.Contains(output.String(), "channel.follow")
+	a.NotContains(output.String(), "Unable to store")
+
+	db, err := database.NewConnection(false)
+	a.Nil(err)
+	stored, err := db.NewQuery(nil, 100).GetEventByID(id)
+	a.Nil(err)
+	a.Equal("1234", stored.FromUser)
+
+	// Storing it again keeps the cached event
+	a.Nil(storeEvent(id, util.GetTimestamp().Format(time.RFC3339Nano), models.EventsubResponse{}, []byte(`{}`)))
+	stored, err = db.NewQuery(nil, 100).GetEventByID(id)
+	a.Nil(err)
+	a.Equal("1234", stored.FromUser)
+}
@@ -7,3 +7,22 @@ var transportSupported = map[string]bool{
 	"webhook":   true,
 	"websocket": true,
 }
+
+// Subscription statuses sent with revocation messages
+// https://dev.twitch.tv/docs/eventsub/handling-webhook-events/#revoking-your-subscription
+const (
+	SubscriptionStatusEnabled                      = "enabled"
+	SubscriptionStatusUserRemoved                  = "user_removed"
+	SubscriptionStatusAuthorizationRevoked         = "authorization_revoked"
+	SubscriptionStatusNotificationFailuresExceeded = "notification_failures_exceeded"
+	SubscriptionStatusVersionRemoved               = "version_removed"
+	SubscriptionStatusModeratorRemoved             = "moderator_removed"
+)
+
+var revocationReasons = map[string]bool{
+	SubscriptionStatusUserRemoved:                  true,
+	SubscriptionStatusAuthorizationRevoked:         true,
+	SubscriptionStatusNotificationFailuresExceeded: true,
+	SubscriptionStatusVersionRemoved:               true,
+	SubscriptionStatusModeratorRemoved:             true,
+}
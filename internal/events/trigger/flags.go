@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package trigger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/twitchdev/twitch-cli/internal/events"
+)
+
+// AddFlags registers the flags of the trigger command on fs, which set the fields of p. They're shared by "twitch event
+// trigger" and the mock WebSocket server's shell and dashboard, which default --transport to transport.
+func AddFlags(fs *pflag.FlagSet, p *TriggerParameters, transport string) {
+	// flags for forwarding functionality/changing payloads
+	fs.StringVarP(&p.ForwardAddress, "forward-address", "F", "", "Forward address for mock event (webhook only).")
+	fs.StringVarP(&p.Transport, "transport", "T", transport, fmt.Sprintf("Preferred transport method for event.\nSupported values: %s", events.ValidTransports()))
+	fs.StringVarP(&p.Secret, "secret", "s", "", "Webhook secret. If defined, signs all forwarded events with the SHA256 HMAC and must be 10-100 characters in length.")
+
+	// per-topic flags
+	fs.StringVarP(&p.ToUser, "to-user", "t", "", "User ID of the receiver of the event. For example, the user that receives a follow. In most contexts, this is the broadcaster.")
+	fs.StringVarP(&p.FromUser, "from-user", "f", "", "User ID of the user sending the event, for example the user following another user.")
+	fs.StringVarP(&p.GiftUser, "gift-user", "g", "", "Used only for \"gift\" events. Denotes the User ID of the gifting user.")
+	fs.BoolVarP(&p.IsAnonymous, "anonymous", "a", false, "Denotes if the event is anonymous. Only applies to Gift and Sub events.")
+	fs.IntVarP(&p.Count, "count", "c", 1, "Number of times to run an event. This can be used to simulate rapid events, such as multiple sub gift, or large number of cheers.")
+	fs.StringVarP(&p.EventStatus, "event-status", "S", "", "Status of the Event object (.event.status in JSON); currently applies to channel points redemptions.")
+	fs.StringVarP(&p.SubscriptionStatus, "subscription-status", "r", "enabled", fmt.Sprintf("Status of the Subscription object (.subscription.status in JSON). Defaults to \"enabled\". Any other status sends a revocation message; see also \"twitch event revoke\".\nSupported values: enabled, %s", strings.Join(events.ValidRevocationReasons(), ", ")))
+	fs.StringVarP(&p.ItemID, "item-id", "i", "", "Manually set the ID of the event payload item (for example the reward ID in redemption events). For stream events, this is the game ID.")
+	fs.StringVarP(&p.ItemName, "item-name", "n", "", "Manually set the name of the event payload item (for example the reward ID in redemption events). For stream events, this is the game title.")
+	fs.Int64VarP(&p.Cost, "cost", "C", 0, "Amount of drops, subscriptions, bits, or channel points redeemed/used in the event.")
+	fs.StringVarP(&p.Description, "description", "d", "", "Title the stream should be updated with.")
+	fs.StringVarP(&p.GameID, "game-id", "G", "", "Sets the game/category ID for applicable events.")
+	fs.StringVarP(&p.Tier, "tier", "", "", "Sets the subscription tier. Valid values are 1000, 2000, and 3000.")
+	fs.StringVarP(&p.SubscriptionID, "subscription-id", "u", "", "Manually set the subscription/event ID of the event itself.")
+	fs.StringVarP(&p.EventMessageID, "event-id", "I", "", "Manually set the Twitch-Eventsub-Message-Id header value for the event.")
+	fs.StringVar(&p.Timestamp, "timestamp", "", "Sets the timestamp to be used in payloads and headers. Must be in RFC3339Nano format.")
+	fs.IntVar(&p.CharityCurrentValue, "charity-current-value", 0, "Only used for \"charity-*\" events. Manually set the current dollar value for charity events.")
+	fs.IntVar(&p.CharityTargetValue, "charity-target-value", 1500000, "Only used for \"charity-*\" events. Manually set the target dollar value for charity events.")
+	fs.StringVar(&p.ClientID, "client-id", "", "Manually set the Client ID used in revoke, grant, and bits transaction events.")
+	fs.StringVarP(&p.Version, "version", "v", "", "Chooses the EventSub version used for a specific event. Not required for most events.")
+	fs.StringVar(&p.WebSocketClient, "session", "", "Defines a specific websocket client/session to forward an event to. Used only with \"websocket\" transport.")
+	fs.StringVar(&p.BanStartTimestamp, "ban-start", "", "Sets the timestamp a ban started at.")
+	fs.StringVar(&p.BanEndTimestamp, "ban-end", "", "Sets the timestamp a ban is intended to end at. If not set, the ban event will appear as permanent. This flag can take a timestamp or relative time (600, 600s, 10d4h12m55s)")
+}
@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package trigger
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestAddFlags(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	p := TriggerParameters{}
+	fs := pflag.NewFlagSet("trigger", pflag.ContinueOnError)
+	AddFlags(fs, &p, models.TransportWebSocket)
+
+	a.Equal(models.TransportWebSocket, p.Transport)
+	a.Equal(1, p.Count)
+	a.Equal("enabled", p.SubscriptionStatus)
+	a.Equal(1500000, p.CharityTargetValue)
+
+	a.Nil(fs.Parse([]string{"-t", "1234", "--from-user", "5678", "-c", "3", "--ban-end", "10m", "--session", "abc", "-T", "webhook"}))
+	a.Equal("1234", p.ToUser)
+	a.Equal("5678", p.FromUser)
+	a.Equal(3, p.Count)
+	a.Equal("10m", p.BanEndTimestamp)
+	a.Equal("abc", p.WebSocketClient)
+	a.Equal("webhook", p.Transport)
+}
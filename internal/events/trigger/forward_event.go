@@ -7,11 +7,17 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/fatih/color"
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/events"
 	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/internal/request"
 )
@@ -30,6 +36,22 @@ type ForwardParamters struct {
 	SubscriptionVersion string
 }
 
+// WebSocketForwardParameters defines the parameters used to forward an event to the mock EventSub WebSocket server.
+type WebSocketForwardParameters struct {
+	JSON             []byte
+	WebSocketClient  string // Only sends to this client/session when set
+	MessageID        string // Sets metadata.message_id of the notification; Randomly generated by the server when empty
+	MessageTimestamp string // Sets metadata.message_timestamp of the notification; Set to the current time by the server when empty
+}
+
+// Returns "revocation" if the subscription status is not "enabled", and "notification" otherwise
+func messageTypeForStatus(status string) string {
+	if status != "" && !strings.EqualFold(status, events.SubscriptionStatusEnabled) {
+		return EventSubMessageTypeRevocation
+	}
+	return EventSubMessageTypeNotification
+}
+
 type header struct {
 	HeaderName  string
 	HeaderValue string
@@ -120,3 +142,60 @@ func getSignatureHeader(req *http.Request, id string, secret string, transport s
 		req.Header.Set("Twitch-Eventsub-Message-Signature", fmt.Sprintf("sha256=%x", mac.Sum(nil)))
 	}
 }
+
+// ForwardWebSocketEvent sends an event to the mock EventSub WebSocket server via its admin API, returning the JSON with its transport changed to websocket.
+func ForwardWebSocketEvent(p WebSocketForwardParameters) ([]byte, error) {
+	// Modify transport
+	modifiedTransportJSON := models.EventsubResponse{}
+	err := json.Unmarshal(p.JSON, &modifiedTransportJSON)
+	if err != nil {
+		return nil, errors.New("Unexpected error unmarshling JSON before forwarding to WebSocket server: " + err.Error())
+	}
+	modifiedTransportJSON.Subscription.Transport.Method = "websocket"
+	modifiedTransportJSON.Subscription.Transport.Callback = ""
+	modifiedTransportJSON.Subscription.Transport.SessionID = "WebSocket-Server-Will-Set"
+	rawModifiedTransportJSON, _ := json.Marshal(modifiedTransportJSON)
+
+	// Trigger any EventSub subscription that's available over 1st party WebSocket connections
+	_, err = admin.Call(http.MethodPost, "/admin/events/websocket", admin.EventRequest{
+		Event:            rawModifiedTransportJSON,
+		Session:          p.WebSocketClient,
+		MessageID:        p.MessageID,
+		MessageTimestamp: p.MessageTimestamp,
+	}, nil)
+
+	var apiErr *admin.APIError
+	if errors.As(err, &apiErr) {
+		color.New().Add(color.FgRed).Println(fmt.Sprintf(`✗ EventSub WebSocket server failed to process event: %v`, apiErr.Message))
+	} else if err != nil {
+		return nil, err
+	} else {
+		color.New().Add(color.FgGreen).Println(`✔ Forwarded for use in mock EventSub WebSocket server`)
+	}
+
+	return rawModifiedTransportJSON, nil
+}
+
+// ForwardWebhookSubscriptionEvent sends an event via the admin API of the mock EventSub WebSocket server, which sends it to the callback
+// of every matching webhook subscription created with its mock EventSub REST endpoint.
+// Does nothing if the server isn't running.
+func ForwardWebhookSubscriptionEvent(json []byte, messageID string, messageTimestamp string) error {
+	msg, err := admin.Call(http.MethodPost, "/admin/events/webhook", admin.EventRequest{
+		Event:            json,
+		MessageID:        messageID,
+		MessageTimestamp: messageTimestamp,
+	}, nil)
+
+	var apiErr *admin.APIError
+	if errors.Is(err, admin.ErrServerNotRunning) {
+		return nil
+	} else if errors.As(err, &apiErr) {
+		color.New().Add(color.FgYellow).Println(fmt.Sprintf(`Not forwarded to mock EventSub webhook subscriptions: %v`, apiErr.Message))
+		return nil
+	} else if err != nil {
+		return errors.New("Failed to send to WebSocket server: " + err.Error())
+	}
+
+	color.New().Add(color.FgGreen).Println(fmt.Sprintf(`✔ Forwarded to mock EventSub webhook subscriptions: %v`, msg))
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package trigger
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+// Fields of the events applied to the mock API's database
+type mockAPIStateEvent struct {
+	ID                string `json:"id"`
+	UserID            string `json:"user_id"`
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+	Tier              string `json:"tier"`
+	IsGift            bool   `json:"is_gift"`
+	Type              string `json:"type"`
+	StartedAt         string `json:"started_at"`
+	Title             string `json:"title"`
+	Language          string `json:"language"`
+	CategoryID        string `json:"category_id"`
+	CategoryName      string `json:"category_name"`
+}
+
+// Applies a triggered event to the mock API's database, so the mock API's endpoints agree with the events. For
+// example, a triggered channel.follow adds the follow returned by GET /channels/followers. Events are only applied when
+// every user in them exists in the database, so triggers with random user IDs don't fill it with users. Events of other
+// topics are ignored.
+func applyToMockAPI(q *database.Query, eventJSON []byte) error {
+	var body struct {
+		Subscription models.EventsubSubscription `json:"subscription"`
+		Event        mockAPIStateEvent           `json:"event"`
+	}
+	if err := json.Unmarshal(eventJSON, &body); err != nil {
+		return err
+	}
+	e := body.Event
+
+	var userIDs []string
+	switch body.Subscription.Type {
+	case "channel.follow", "channel.subscribe", "channel.ban", "channel.unban", "channel.moderator.add", "channel.moderator.remove":
+		userIDs = []string{e.BroadcasterUserID, e.UserID}
+	case "stream.online", "stream.offline", "channel.update":
+		userIDs = []string{e.BroadcasterUserID}
+	default:
+		return nil
+	}
+	for _, id := range userIDs {
+		if exists, err := mockAPIUserExists(q, id); err != nil || !exists {
+			return err
+		}
+	}
+
+	params := database.UserRequestParams{BroadcasterID: e.BroadcasterUserID, UserID: e.UserID}
+
+	switch body.Subscription.Type {
+	case "channel.follow":
+		// Following again updates when the user followed
+		if err := q.DeleteFollow(e.UserID, e.BroadcasterUserID); err != nil {
+			return err
+		}
+		return q.AddFollow(params)
+
+	case "channel.subscribe":
+		dbr, err := q.GetSubscriptions(database.Subscription{BroadcasterID: e.BroadcasterUserID, UserID: e.UserID})
+		if err != nil || len(dbr.Data.([]database.Subscription)) > 0 {
+			return err
+		}
+		return q.InsertSubscription(database.SubscriptionInsert{
+			BroadcasterID: e.BroadcasterUserID,
+			UserID:        e.UserID,
+			IsGift:        e.IsGift,
+			Tier:          e.Tier,
+			CreatedAt:     util.GetTimestamp().Format(time.RFC3339),
+		})
+
+	case "channel.ban":
+		dbr, err := q.GetBans(params)
+		if err != nil || len(dbr.Data.([]database.Ban)) > 0 {
+			return err
+		}
+		return q.InsertBan(params)
+
+	case "channel.unban":
+		return q.DeleteBan(params)
+
+	case "channel.moderator.add":
+		dbr, err := q.GetModeratorsForBroadcaster(e.BroadcasterUserID)
+		if err != nil {
+			return err
+		}
+		for _, m := range dbr.Data.([]database.Moderator) {
+			if m.UserID == e.UserID {
+				return nil
+			}
+		}
+		return q.AddModerator(params)
+
+	case "channel.moderator.remove":
+		return q.RemoveModerator(e.BroadcasterUserID, e.UserID)
+
+	case "stream.online":
+		// Broadcasters only have one live stream
+		if err := q.DeleteStream(e.BroadcasterUserID); err != nil {
+			return err
+		}
+		return q.InsertStream(database.Stream{
+			ID:          e.ID,
+			UserID:      e.BroadcasterUserID,
+			StreamType:  e.Type,
+			ViewerCount: 0,
+			StartedAt:   e.StartedAt,
+		}, false)
+
+	case "stream.offline":
+		return q.DeleteStream(e.BroadcasterUserID)
+
+	case "channel.update":
+		u, err := q.GetUser(database.User{ID: e.BroadcasterUserID})
+		if err != nil {
+			return err
+		}
+
+		categoryID := u.CategoryID
+		if e.CategoryID != "" {
+			if err := ensureMockAPICategory(q, e.CategoryID, e.CategoryName); err != nil {
+				return err
+			}
+			categoryID = sql.NullString{String: e.CategoryID, Valid: true}
+		}
+
+		return q.UpdateChannel(e.BroadcasterUserID, database.User{
+			ID:         e.BroadcasterUserID,
+			Title:      e.Title,
+			Language:   e.Language,
+			CategoryID: categoryID,
+			Delay:      u.Delay,
+		})
+	}
+
+	return nil
+}
+
+// Returns whether the user exists in the mock API's database
+func mockAPIUserExists(q *database.Query, id string) (bool, error) {
+	if id == "" {
+		return false, nil
+	}
+
+	u, err := q.GetUser(database.User{ID: id})
+	if err != nil {
+		return false, err
+	}
+	return u.ID != "", nil
+}
+
+// Creates the category if it doesn't exist in the mock API's database
+func ensureMockAPICategory(q *database.Query, id string, name string) error {
+	dbr, err := q.GetCategories(database.Category{ID: id})
+	if err != nil || len(dbr.Data.([]database.Category)) > 0 {
+		return err
+	}
+
+	if name == "" {
+		name = "Category " + id
+	}
+	return q.InsertCategory(database.Category{ID: id, Name: name}, false)
+}
@@ -0,0 +1,81 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestApplyToMockAPI(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	db, err := database.NewConnection(false)
+	a.Nil(err)
+	q := db.NewQuery(nil, 100)
+
+	fromUser, toUser := util.RandomUserID(), util.RandomUserID()
+	follow := func() {
+		_, err := Fire(TriggerParameters{
+			Event:     "follow",
+			Transport: models.TransportWebhook,
+			FromUser:  fromUser,
+			ToUser:    toUser,
+		})
+		a.Nil(err)
+	}
+	follows := func() []database.Follow {
+		dbr, err := q.GetFollows(database.UserRequestParams{BroadcasterID: toUser, UserID: fromUser}, false)
+		a.Nil(err)
+		return dbr.Data.([]database.Follow)
+	}
+
+	// Events of users that aren't in the database aren't applied, and don't create them
+	follow()
+	a.Len(follows(), 0)
+	u, err := q.GetUser(database.User{ID: fromUser})
+	a.Nil(err)
+	a.Empty(u.ID)
+
+	for _, id := range []string{fromUser, toUser} {
+		a.Nil(q.InsertUser(database.User{
+			ID:          id,
+			UserLogin:   "user" + id,
+			DisplayName: "user" + id,
+			CreatedAt:   util.GetTimestamp().Format(time.RFC3339),
+			ModifiedAt:  util.GetTimestamp().Format(time.RFC3339),
+			Language:    "en",
+		}, false))
+	}
+
+	follow()
+	a.Len(follows(), 1)
+	a.Equal("user"+fromUser, follows()[0].ViewerLogin)
+
+	_, err = Fire(TriggerParameters{
+		Event:     "streamup",
+		Transport: models.TransportWebhook,
+		ToUser:    toUser,
+	})
+	a.Nil(err)
+
+	dbr, err := q.GetStream(database.Stream{UserID: toUser})
+	a.Nil(err)
+	a.Len(dbr.Data.([]database.Stream), 1)
+
+	_, err = Fire(TriggerParameters{
+		Event:     "streamdown",
+		Transport: models.TransportWebhook,
+		ToUser:    toUser,
+	})
+	a.Nil(err)
+
+	dbr, err = q.GetStream(database.Stream{UserID: toUser})
+	a.Nil(err)
+	a.Len(dbr.Data.([]database.Stream), 0)
+}
@@ -5,12 +5,19 @@ package trigger
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/events/history"
 	"github.com/twitchdev/twitch-cli/internal/events/types"
 	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
 )
 
+// RefireEvent resends a cached event with its original body.
+// The event is sent over the transport it was originally fired with unless p.Transport is set. The cached message ID and
+// timestamp are reused unless p.Regenerate is set.
 func RefireEvent(id string, p TriggerParameters) (string, error) {
 	db, err := database.NewConnection(false)
 	if err != nil {
@@ -21,7 +28,9 @@ func RefireEvent(id string, p TriggerParameters) (string, error) {
 		return "", err
 	}
 
-	p.Transport = res.Transport
+	if p.Transport == "" {
+		p.Transport = res.Transport
+	}
 
 	var previousEventObj models.EventsubResponse
 	err = json.Unmarshal([]byte(res.JSON), &previousEventObj)
@@ -39,28 +48,73 @@ func RefireEvent(id string, p TriggerParameters) (string, error) {
 		topic = res.Event
 	}
 
-	if p.ForwardAddress != "" {
-		resp, err := ForwardEvent(ForwardParamters{
-			ID:                  id,
-			Transport:           res.Transport,
-			Timestamp:           p.Timestamp,
-			ForwardAddress:      p.ForwardAddress,
-			Secret:              p.Secret,
-			JSON:                []byte(res.JSON),
-			Event:               topic,
-			EventMessageID:      "",
-			Type:                EventSubMessageTypeNotification,
-			SubscriptionVersion: e.SubscriptionVersion(),
-		})
+	messageID := id
+	timestamp := res.Timestamp
+	if p.Regenerate || timestamp == "" {
+		timestamp = util.GetTimestamp().Format(time.RFC3339Nano)
+	}
+	if p.Regenerate {
+		messageID = util.RandomGUID()
+	}
 
+	if strings.EqualFold(p.Transport, models.TransportWebSocket) {
+		body, err := ForwardWebSocketEvent(WebSocketForwardParameters{
+			JSON:             []byte(res.JSON),
+			WebSocketClient:  p.WebSocketClient,
+			MessageID:        messageID,
+			MessageTimestamp: timestamp,
+		})
 		if err != nil {
 			return "", err
 		}
 
-		defer resp.Body.Close()
+		return string(body), nil
+	}
 
-		fmt.Printf("[%v] Endpoint received refired event.", resp.StatusCode)
+	if p.ForwardAddress == "" {
+		return "", fmt.Errorf("if a default configuration is not set, forward-address must be provided to refire webhook events")
 	}
 
+	resp, err := ForwardEvent(ForwardParamters{
+		ID:                  messageID,
+		Transport:           p.Transport,
+		Timestamp:           timestamp,
+		ForwardAddress:      p.ForwardAddress,
+		Secret:              p.Secret,
+		JSON:                []byte(res.JSON),
+		Event:               topic,
+		EventMessageID:      "",
+		Type:                messageTypeForStatus(previousEventObj.Subscription.Status),
+		SubscriptionVersion: e.SubscriptionVersion(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	fmt.Printf("[%v] Endpoint received refired event.\n", resp.StatusCode)
+
 	return res.JSON, nil
 }
+
+// RefireEvents refires every cached event matching the given filters, in the order they were originally fired.
+// Returns the IDs of the refired events.
+func RefireEvents(filter history.HistoryParameters, p TriggerParameters) ([]string, error) {
+	entries, err := history.GetEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// GetEvents returns the newest events first, so the limit applies to the most recent ones
+	ids := []string{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		_, err := RefireEvent(entries[i].ID, p)
+		if err != nil {
+			return ids, fmt.Errorf("Unable to refire event [%v]: %v", entries[i].ID, err)
+		}
+		ids = append(ids, entries[i].ID)
+	}
+
+	return ids, nil
+}
@@ -10,21 +10,28 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/twitchdev/twitch-cli/internal/events/history"
 	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
 	"github.com/twitchdev/twitch-cli/test_setup"
 )
 
 func TestRefireEvent(t *testing.T) {
 	a := test_setup.SetupTestEnv(t)
+	messageIDs := []string{}
+	timestamps := []string{}
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusAccepted)
 
+		messageIDs = append(messageIDs, r.Header.Get("Twitch-Eventsub-Message-Id"))
+		timestamps = append(timestamps, r.Header.Get("Twitch-Eventsub-Message-Timestamp"))
+
 		_, err := io.ReadAll(r.Body)
 		a.Nil(err)
 	}))
 	defer ts.Close()
 
-	var eventMessageID = "testtriggereventid";
+	var eventMessageID = util.RandomGUID()
 
 	params := TriggerParameters{
 		Event:          "gift",
@@ -53,4 +60,53 @@ func TestRefireEvent(t *testing.T) {
 	json, err := RefireEvent(eventMessageID, params)
 	a.Nil(err)
 	a.Equal(response, json)
+	a.Equal(eventMessageID, messageIDs[len(messageIDs)-1])
+	a.Equal(timestamps[0], timestamps[len(timestamps)-1])
+
+	params.Regenerate = true
+	json, err = RefireEvent(eventMessageID, params)
+	a.Nil(err)
+	a.Equal(response, json)
+	a.NotEqual(eventMessageID, messageIDs[len(messageIDs)-1])
+
+	params.Regenerate = false
+	params.ForwardAddress = ""
+	_, err = RefireEvent(eventMessageID, params)
+	a.NotNil(err)
+}
+
+func TestRefireEvents(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+	messageIDs := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		messageIDs = append(messageIDs, r.Header.Get("Twitch-Eventsub-Message-Id"))
+	}))
+	defer ts.Close()
+
+	fromUser := util.RandomUserID()
+	params := TriggerParameters{
+		Event:     "cheer",
+		Transport: models.TransportWebhook,
+		FromUser:  fromUser,
+	}
+
+	firstID := util.RandomGUID()
+	params.EventMessageID = firstID
+	_, err := Fire(params)
+	a.Nil(err)
+
+	secondID := util.RandomGUID()
+	params.EventMessageID = secondID
+	_, err = Fire(params)
+	a.Nil(err)
+
+	ids, err := RefireEvents(history.HistoryParameters{Event: "cheer", FromUser: fromUser, Since: "1h"}, TriggerParameters{ForwardAddress: ts.URL})
+	a.Nil(err)
+	a.Equal([]string{firstID, secondID}, ids)
+	a.Equal([]string{firstID, secondID}, messageIDs)
+
+	ids, err = RefireEvents(history.HistoryParameters{Event: "cheer", FromUser: fromUser, Since: "1h", Limit: 1}, TriggerParameters{ForwardAddress: ts.URL})
+	a.Nil(err)
+	a.Equal([]string{secondID}, ids)
 }
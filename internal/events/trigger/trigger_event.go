@@ -3,11 +3,8 @@
 package trigger
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
-	"net/rpc"
 	"strings"
 	"time"
 
@@ -15,9 +12,8 @@ import (
 	"github.com/spf13/viper"
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/events"
+	"github.com/twitchdev/twitch-cli/internal/events/history"
 	"github.com/twitchdev/twitch-cli/internal/events/types"
-	"github.com/twitchdev/twitch-cli/internal/models"
-	rpc_handler "github.com/twitchdev/twitch-cli/internal/rpc"
 	"github.com/twitchdev/twitch-cli/internal/util"
 )
 
@@ -51,8 +47,12 @@ type TriggerParameters struct {
 	WebSocketClient     string
 	BanStartTimestamp   string
 	BanEndTimestamp     string
+	Regenerate          bool // Used when refiring; generates a new message ID and timestamp instead of reusing the cached ones
 }
 
+// MaxCount is the most times servers trigger an event for a single request, such as the dashboard's.
+const MaxCount = 100
+
 type TriggerResponse struct {
 	ID        string
 	JSON      []byte
@@ -99,6 +99,15 @@ func Fire(p TriggerParameters) (string, error) {
 				"Valid values are 1000, 2000 or 3000")
 	}
 
+	// Any status other than "enabled" sends a revocation message, which only supports the statuses Twitch revokes subscriptions with
+	if p.SubscriptionStatus == "" {
+		p.SubscriptionStatus = events.SubscriptionStatusEnabled
+	} else if !strings.EqualFold(p.SubscriptionStatus, events.SubscriptionStatusEnabled) && !events.IsValidRevocationReason(p.SubscriptionStatus) {
+		return "", fmt.Errorf(
+			"Discarding event: Invalid subscription status provided.\n"+
+				"Valid values are %v, or one of the revocation reasons: %v", events.SubscriptionStatusEnabled, strings.Join(events.ValidRevocationReasons(), ", "))
+	}
+
 	// the header twitch-eventsub-message-id
 	if p.EventMessageID == "" {
 		p.EventMessageID = util.RandomGUID()
@@ -169,6 +178,13 @@ https://dev.twitch.tv/docs/eventsub/handling-webhook-events#processing-an-event`
 		return "", err
 	}
 
+	// Retention prunes by the events' timestamps, so an event fired with a --timestamp older than the retention is kept
+	// until the next event is fired, and then pruned
+	err = history.ApplyRetention(db.NewQuery(nil, 100))
+	if err != nil {
+		return "", err
+	}
+
 	//color.New().Add(color.FgGreen).Println(fmt.Sprintf(`Insert into DB with %v`, resp.ID));
 	err = db.NewQuery(nil, 100).InsertIntoDB(database.EventCacheParameters{
 		ID:        resp.ID,
@@ -187,11 +203,14 @@ https://dev.twitch.tv/docs/eventsub/handling-webhook-events#processing-an-event`
 		topic = p.Event
 	}
 
-	messageType := EventSubMessageTypeNotification
-	// Set to "revocation" if SubscriptionStatus is not set to "enabled"
 	// We don't have to worry about "webhook_callback_verification" in this bit of code, since it's an entirely different command. All this code is from "event trigger".
-	if !strings.EqualFold(p.SubscriptionStatus, "enabled") {
-		messageType = EventSubMessageTypeRevocation
+	messageType := messageTypeForStatus(p.SubscriptionStatus)
+
+	// Keep the mock API consistent with the event, e.g. so a triggered follow is listed by GET /channels/followers
+	if messageType == EventSubMessageTypeNotification {
+		if err := applyToMockAPI(db.NewQuery(nil, 100), resp.JSON); err != nil {
+			color.New().Add(color.FgYellow).Println(fmt.Sprintf(`Could not apply the event to the mock API: %v`, err))
+		}
 	}
 
 	if p.ForwardAddress != "" && strings.EqualFold(p.Transport, "webhook") { // Forwarding to an address requires Webhook, as its done via HTTP
@@ -227,52 +246,24 @@ https://dev.twitch.tv/docs/eventsub/handling-webhook-events#processing-an-event`
 		}
 	}
 
-	// Forward to WebSocket server via RPC
-	if strings.EqualFold(p.Transport, "websocket") {
-		client, err := rpc.DialHTTP("tcp", ":44747")
+	// Without a forward address, webhook events are sent to the subscriptions created on the mock EventSub WebSocket server, if it's running
+	if p.ForwardAddress == "" && strings.EqualFold(p.Transport, "webhook") {
+		err = ForwardWebhookSubscriptionEvent(resp.JSON, p.EventMessageID, p.Timestamp)
 		if err != nil {
-			return "", errors.New(
-				"Failed to dial RPC handler for WebSocket server; It may not be running. See `twitch event websocket --help` for help on starting the WebSocket server.\n" +
-					"Error: " + err.Error(),
-			)
-		}
-
-		var reply rpc_handler.RPCResponse
-
-		// Modify transport
-		modifiedTransportJSON := models.EventsubResponse{}
-		err = json.Unmarshal([]byte(resp.JSON), &modifiedTransportJSON)
-		if err != nil {
-			return "", errors.New("Unexpected error unmarshling JSON before forwarding to WebSocket server: " + err.Error())
-		}
-		modifiedTransportJSON.Subscription.Transport.Method = "websocket"
-		modifiedTransportJSON.Subscription.Transport.Callback = ""
-		modifiedTransportJSON.Subscription.Transport.SessionID = "WebSocket-Server-Will-Set"
-		rawModifiedTransportJSON, _ := json.Marshal(modifiedTransportJSON)
-		resp.JSON = rawModifiedTransportJSON
-
-		// Trigger any EventSub subscription that's available over 1st party WebSocket connections
-		variables := make(map[string]string)
-		variables["ClientName"] = p.WebSocketClient
-
-		args := &rpc_handler.RPCArgs{
-			RPCName:   "EventSubWebSocketForwardEvent",
-			Body:      string(resp.JSON),
-			Variables: variables,
+			return "", err
 		}
+	}
 
-		err = client.Call("RPCHandler.ExecuteGenericRPC", args, &reply)
-
-		// Error checking for RPC internals
+	// Forward to WebSocket server via its admin API
+	if strings.EqualFold(p.Transport, "websocket") {
+		resp.JSON, err = ForwardWebSocketEvent(WebSocketForwardParameters{
+			JSON:             resp.JSON,
+			WebSocketClient:  p.WebSocketClient,
+			MessageID:        p.EventMessageID,
+			MessageTimestamp: p.Timestamp,
+		})
 		if err != nil {
-			return "", errors.New("Failed to send via RPC to WebSocket server: " + err.Error())
-		}
-
-		// Error checking for everything else
-		if reply.ResponseCode == 0 { // Zero will always be success
-			color.New().Add(color.FgGreen).Println(`✔ Forwarded for use in mock EventSub WebSocket server`)
-		} else {
-			color.New().Add(color.FgRed).Println(fmt.Sprintf(`✗ EventSub WebSocket server failed to process event: [%v] %v`, reply.DetailedInfo, reply.DetailedInfo))
+			return "", err
 		}
 	}
 
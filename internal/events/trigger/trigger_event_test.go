@@ -3,12 +3,18 @@
 package trigger
 
 import (
+	"database/sql"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
+	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
 	"github.com/twitchdev/twitch-cli/test_setup"
 )
 
@@ -175,3 +181,72 @@ func TestFire(t *testing.T) {
 	_, err = Fire(params)
 	a.NotNil(err)
 }
+
+func TestFireRevocation(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+	messageType := ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		messageType = r.Header.Get("Twitch-Eventsub-Message-Type")
+	}))
+	defer ts.Close()
+
+	params := TriggerParameters{
+		Event:              "cheer",
+		Transport:          models.TransportWebhook,
+		ForwardAddress:     ts.URL,
+		SubscriptionStatus: "user_removed",
+	}
+
+	res, err := Fire(params)
+	a.Nil(err)
+	a.Equal(EventSubMessageTypeRevocation, messageType)
+
+	var body map[string]interface{}
+	a.Nil(json.Unmarshal([]byte(res), &body))
+	a.NotContains(body, "event")
+	a.Equal("user_removed", body["subscription"].(map[string]interface{})["status"])
+
+	params.SubscriptionStatus = ""
+	_, err = Fire(params)
+	a.Nil(err)
+	a.Equal(EventSubMessageTypeNotification, messageType)
+
+	params.SubscriptionStatus = "revoked"
+	_, err = Fire(params)
+	a.NotNil(err)
+}
+
+func TestFireRetention(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	viper.Set("eventRetention", "1h")
+	defer viper.Set("eventRetention", "")
+
+	db, err := database.NewConnection(false)
+	a.Nil(err)
+	q := db.NewQuery(nil, 100)
+
+	fire := func(timestamp time.Time) string {
+		p := TriggerParameters{
+			Event:          "channel.ban",
+			Transport:      models.TransportWebhook,
+			EventMessageID: util.RandomGUID(),
+			Timestamp:      timestamp.Format(time.RFC3339Nano),
+		}
+		_, err := Fire(p)
+		a.Nil(err)
+		return p.EventMessageID
+	}
+
+	// Events are pruned by their timestamp, so an event fired with an older --timestamp is pruned by the next trigger
+	old := fire(util.GetTimestamp().Add(-2 * time.Hour))
+	_, err = q.GetEventByID(old)
+	a.Nil(err)
+
+	recent := fire(util.GetTimestamp())
+	_, err = q.GetEventByID(old)
+	a.ErrorIs(err, sql.ErrNoRows)
+	_, err = q.GetEventByID(recent)
+	a.Nil(err)
+}
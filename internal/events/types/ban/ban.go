@@ -4,8 +4,6 @@ package ban
 
 import (
 	"encoding/json"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -64,43 +62,16 @@ func (e Event) GenerateEvent(params events.MockEventParameters) (events.MockEven
 		if params.BanEndTimestamp == "" {
 			// Default to perma ban
 			isPermanent = true
+		} else if d, err := util.ParseRelativeDuration(params.BanEndTimestamp); err == nil {
+			// Relative time, in seconds similar to /timeout <user> <seconds> (--ban-end=600), or by shorthands (90d10h30m45s)
+			tNow, _ := time.Parse(time.RFC3339Nano, params.Timestamp)
+			tLater := tNow.Add(d).Format(time.RFC3339Nano)
+			endsAt = &tLater
+			isPermanent = false
 		} else {
-			r1 := regexp.MustCompile("^[0-9]+$")
-			r2 := regexp.MustCompile("^(?:(?P<Days>[0-9]+)[dD])?(?:(?P<Hours>[0-9]+)[hH])?(?:(?P<Minutes>[0-9]+)[mM])?(?:(?P<Seconds>[0-9]+)[sS])?$")
-
-			if r1.MatchString(params.BanEndTimestamp) {
-				// Similar format to /timeout <user> <seconds>
-				// twitch event trigger channel.ban --ban-end=600
-				seconds, _ := strconv.Atoi(r1.FindAllString(params.BanEndTimestamp, -1)[0])
-				tNow, _ := time.Parse(time.RFC3339Nano, params.Timestamp)
-				tLater := tNow.Add(time.Duration(seconds) * time.Second).Format(time.RFC3339Nano)
-				endsAt = &tLater
-				isPermanent = false
-
-			} else if r2.MatchString(params.BanEndTimestamp) {
-				// Relative time specified by shorthands. e.g. 90d10h30m45s
-				// Can include or exclude any of those, but they have to be in the same order as above
-				values := r2.FindStringSubmatch(params.BanEndTimestamp)
-				days, _ := strconv.Atoi(values[r2.SubexpIndex("Days")])
-				hours, _ := strconv.Atoi(values[r2.SubexpIndex("Hours")])
-				minutes, _ := strconv.Atoi(values[r2.SubexpIndex("Minutes")])
-				seconds, _ := strconv.Atoi(values[r2.SubexpIndex("Seconds")])
-
-				tNow, _ := time.Parse(time.RFC3339Nano, params.Timestamp)
-				tLater := tNow.Add(time.Duration(days*24) * time.Hour).
-					Add(time.Duration(hours) * time.Hour).
-					Add(time.Duration(minutes) * time.Minute).
-					Add(time.Duration(seconds) * time.Second).
-					Format(time.RFC3339Nano)
-				endsAt = &tLater
-				isPermanent = false
-
-			} else {
-				// Timeout with user provided timestamp
-				endsAt = &params.BanEndTimestamp
-				isPermanent = false
-
-			}
+			// Timeout with user provided timestamp
+			endsAt = &params.BanEndTimestamp
+			isPermanent = false
 		}
 
 		ban.Reason = reason
@@ -36,6 +36,33 @@ func TestEventSubBan(t *testing.T) {
 	a.Equal(fromUser, body.Event.UserID, "Expected from user %v, got %v", r.ToUser, body.Event.UserID)
 }
 
+func TestEventSubBanEnd(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	for banEnd, endsAt := range map[string]string{
+		"600":                  "2023-01-01T00:10:00Z",
+		"1d2h3m4s":             "2023-01-02T02:03:04Z",
+		"2023-02-01T00:00:00Z": "2023-02-01T00:00:00Z",
+	} {
+		r, err := Event{}.GenerateEvent(events.MockEventParameters{
+			FromUserID:         fromUser,
+			ToUserID:           toUser,
+			Transport:          models.TransportWebhook,
+			Trigger:            "ban",
+			SubscriptionStatus: "enabled",
+			Timestamp:          "2023-01-01T00:00:00Z",
+			BanEndTimestamp:    banEnd,
+		})
+		a.Nil(err)
+
+		var body models.BanEventSubResponse
+		a.Nil(json.Unmarshal(r.JSON, &body))
+		a.False(body.Event.IsPermanent, banEnd)
+		a.NotNil(body.Event.EndsAt, banEnd)
+		a.Equal(endsAt, *body.Event.EndsAt, banEnd)
+	}
+}
+
 func TestFakeTransport(t *testing.T) {
 	a := test_setup.SetupTestEnv(t)
 
@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/twitchdev/twitch-cli/internal/models"
+)
+
+// Condition fields required when subscribing to a topic, keyed by "type/version".
+// Each entry lists alternatives, of which at least one must be set. Topics not listed only require broadcaster_user_id.
+// https://dev.twitch.tv/docs/eventsub/eventsub-reference/#conditions
+var requiredConditions = map[string][][]string{
+	"channel.follow/2":                    {{"broadcaster_user_id"}, {"moderator_user_id"}},
+	"channel.raid/1":                      {{"from_broadcaster_user_id", "to_broadcaster_user_id"}},
+	"channel.shield_mode.begin/1":         {{"broadcaster_user_id"}, {"moderator_user_id"}},
+	"channel.shield_mode.end/1":           {{"broadcaster_user_id"}, {"moderator_user_id"}},
+	"channel.shoutout.create/1":           {{"broadcaster_user_id"}, {"moderator_user_id"}},
+	"channel.shoutout.receive/1":          {{"broadcaster_user_id"}, {"moderator_user_id"}},
+	"channel.unban_request.create/1":      {{"broadcaster_user_id"}, {"moderator_user_id"}},
+	"channel.unban_request.resolve/1":     {{"broadcaster_user_id"}, {"moderator_user_id"}},
+	"conduit.shard.disabled/1":            {{"client_id"}},
+	"drop.entitlement.grant/1":            {{"organization_id"}},
+	"extension.bits_transaction.create/1": {{"extension_client_id"}},
+	"user.authorization.grant/1":          {{"client_id"}},
+	"user.authorization.revoke/1":         {{"client_id"}},
+	"user.update/1":                       {{"user_id"}},
+}
+
+// ValidateCondition checks that a subscription's condition includes every field required by its topic and version.
+func ValidateCondition(topic string, version string, condition models.EventsubCondition) error {
+	required, ok := requiredConditions[topic+"/"+version]
+	if !ok {
+		required = [][]string{{"broadcaster_user_id"}}
+	}
+
+	values := ConditionFields(condition)
+	for _, alternatives := range required {
+		found := false
+		for _, field := range alternatives {
+			if values[field] != "" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("missing or invalid condition; %v requires %v", topic, strings.Join(alternatives, " or "))
+		}
+	}
+
+	return nil
+}
+
+// ConditionMatches returns whether an event with the given condition should be delivered to a subscription with the
+// given condition; Every field set on the subscription must have the same value in the event.
+// moderator_user_id is ignored, as it only identifies the user who authorized the subscription.
+func ConditionMatches(subscription models.EventsubCondition, event models.EventsubCondition) bool {
+	eventValues := ConditionFields(event)
+	for field, value := range ConditionFields(subscription) {
+		if field == "moderator_user_id" {
+			continue
+		}
+		if value != "" && eventValues[field] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ConditionFields returns the condition's values, keyed by their JSON field names.
+func ConditionFields(c models.EventsubCondition) map[string]string {
+	return map[string]string{
+		"broadcaster_user_id":      c.BroadcasterUserID,
+		"to_broadcaster_user_id":   c.ToBroadcasterUserID,
+		"user_id":                  c.UserID,
+		"from_broadcaster_user_id": c.FromBroadcasterUserID,
+		"moderator_user_id":        c.ModeratorUserID,
+		"client_id":                c.ClientID,
+		"extension_client_id":      c.ExtensionClientID,
+		"organization_id":          c.OrganizationID,
+		"category_id":              c.CategoryID,
+		"campaign_id":              c.CampaignID,
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package types
+
+import (
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestValidateCondition(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	a.Nil(ValidateCondition("channel.cheer", "1", models.EventsubCondition{BroadcasterUserID: "1234"}))
+	a.NotNil(ValidateCondition("channel.cheer", "1", models.EventsubCondition{}))
+
+	a.NotNil(ValidateCondition("channel.follow", "2", models.EventsubCondition{BroadcasterUserID: "1234"}))
+	a.Nil(ValidateCondition("channel.follow", "2", models.EventsubCondition{BroadcasterUserID: "1234", ModeratorUserID: "5678"}))
+
+	a.Nil(ValidateCondition("channel.raid", "1", models.EventsubCondition{FromBroadcasterUserID: "1234"}))
+	a.Nil(ValidateCondition("channel.raid", "1", models.EventsubCondition{ToBroadcasterUserID: "1234"}))
+	a.NotNil(ValidateCondition("channel.raid", "1", models.EventsubCondition{BroadcasterUserID: "1234"}))
+
+	a.Nil(ValidateCondition("user.update", "1", models.EventsubCondition{UserID: "1234"}))
+	a.NotNil(ValidateCondition("conduit.shard.disabled", "1", models.EventsubCondition{BroadcasterUserID: "1234"}))
+}
+
+func TestConditionMatches(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	event := models.EventsubCondition{BroadcasterUserID: "1234", ModeratorUserID: "5678"}
+
+	a.True(ConditionMatches(models.EventsubCondition{BroadcasterUserID: "1234"}, event))
+	a.True(ConditionMatches(models.EventsubCondition{BroadcasterUserID: "1234", ModeratorUserID: "5678"}, event))
+	a.False(ConditionMatches(models.EventsubCondition{BroadcasterUserID: "4321"}, event))
+	a.True(ConditionMatches(models.EventsubCondition{BroadcasterUserID: "1234", ModeratorUserID: "1111"}, event))
+
+	// Raids are sent to subscriptions for either side of the raid
+	raid := models.EventsubCondition{ToBroadcasterUserID: "1234"}
+	a.True(ConditionMatches(models.EventsubCondition{ToBroadcasterUserID: "1234"}, raid))
+	a.False(ConditionMatches(models.EventsubCondition{FromBroadcasterUserID: "1234"}, raid))
+}
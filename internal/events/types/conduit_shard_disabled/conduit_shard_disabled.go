@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package conduit_shard_disabled
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/events"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+var transportsSupported = map[string]bool{
+	models.TransportWebhook:   true,
+	models.TransportWebSocket: true,
+}
+
+var triggerSupported = []string{"shard-disabled"}
+
+var triggerMapping = map[string]map[string]string{
+	models.TransportWebhook: {
+		"shard-disabled": "conduit.shard.disabled",
+	},
+	models.TransportWebSocket: {
+		"shard-disabled": "conduit.shard.disabled",
+	},
+}
+
+type Event struct{}
+
+func (e Event) GenerateEvent(params events.MockEventParameters) (events.MockEventResponse, error) {
+	var event []byte
+	var err error
+
+	conduitID := params.ItemID
+	if conduitID == "" {
+		conduitID = util.RandomGUID()
+	}
+
+	status := params.EventStatus
+	if status == "" {
+		status = "websocket_disconnected"
+	}
+
+	switch params.Transport {
+	case models.TransportWebhook, models.TransportWebSocket:
+		body := &models.ConduitShardDisabledEventSubResponse{
+			Subscription: models.EventsubSubscription{
+				ID:      params.SubscriptionID,
+				Status:  params.SubscriptionStatus,
+				Type:    triggerMapping[params.Transport][params.Trigger],
+				Version: e.SubscriptionVersion(),
+				Condition: models.EventsubCondition{
+					ClientID: params.ClientID,
+				},
+				Transport: models.EventsubTransport{
+					Method:   "webhook",
+					Callback: "null",
+				},
+				Cost:      0,
+				CreatedAt: params.Timestamp,
+			},
+			Event: &models.ConduitShardDisabledEvent{
+				ConduitID: conduitID,
+				ShardID:   "0",
+				Status:    status,
+				Transport: models.ConduitShardDisabledEventTransport{
+					Method:         models.TransportWebSocket,
+					SessionID:      util.RandomGUID()[:8] + "_" + util.RandomGUID()[:8],
+					ConnectedAt:    util.GetTimestamp().Add(-1 * time.Hour).Format(time.RFC3339Nano),
+					DisconnectedAt: params.Timestamp,
+				},
+			},
+		}
+
+		event, err = json.Marshal(body)
+		if err != nil {
+			return events.MockEventResponse{}, err
+		}
+
+		// Delete event info if Subscription.Status is not set to "enabled"
+		if !strings.EqualFold(params.SubscriptionStatus, "enabled") {
+			var i interface{}
+			if err := json.Unmarshal([]byte(event), &i); err != nil {
+				return events.MockEventResponse{}, err
+			}
+			if m, ok := i.(map[string]interface{}); ok {
+				delete(m, "event") // Matches JSON key defined in body variable above
+			}
+
+			event, err = json.Marshal(i)
+			if err != nil {
+				return events.MockEventResponse{}, err
+			}
+		}
+	default:
+		return events.MockEventResponse{}, nil
+	}
+
+	return events.MockEventResponse{
+		ID:       params.EventMessageID,
+		JSON:     event,
+		FromUser: params.FromUserID,
+		ToUser:   params.ToUserID,
+	}, nil
+}
+
+func (e Event) ValidTransport(t string) bool {
+	return transportsSupported[t]
+}
+
+func (e Event) ValidTrigger(t string) bool {
+	for _, ts := range triggerSupported {
+		if ts == t {
+			return true
+		}
+	}
+	return false
+}
+func (e Event) GetTopic(transport string, trigger string) string {
+	return triggerMapping[transport][trigger]
+}
+func (e Event) GetAllTopicsByTransport(transport string) []string {
+	allTopics := []string{}
+	for _, topic := range triggerMapping[transport] {
+		allTopics = append(allTopics, topic)
+	}
+	return allTopics
+}
+func (e Event) GetEventSubAlias(t string) string {
+	// check for aliases
+	for trigger, topic := range triggerMapping[models.TransportWebhook] {
+		if topic == t {
+			return trigger
+		}
+	}
+	return ""
+}
+
+func (e Event) SubscriptionVersion() string {
+	return "1"
+}
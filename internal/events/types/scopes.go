@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package types
+
+import "strings"
+
+// Authorization scopes required when subscribing to a topic, keyed by "type/version".
+// Each entry lists alternatives, of which the authorization must include at least one. Topics not listed don't require
+// a scope.
+// https://dev.twitch.tv/docs/eventsub/eventsub-subscription-types/
+var requiredScopes = map[string][]string{
+	"channel.ad_break.begin/1":                                 {"channel:read:ads"},
+	"channel.ban/1":                                            {"channel:moderate"},
+	"channel.channel_points_custom_reward.add/1":               {"channel:read:redemptions", "channel:manage:redemptions"},
+	"channel.channel_points_custom_reward.remove/1":            {"channel:read:redemptions", "channel:manage:redemptions"},
+	"channel.channel_points_custom_reward.update/1":            {"channel:read:redemptions", "channel:manage:redemptions"},
+	"channel.channel_points_custom_reward_redemption.add/1":    {"channel:read:redemptions", "channel:manage:redemptions"},
+	"channel.channel_points_custom_reward_redemption.update/1": {"channel:read:redemptions", "channel:manage:redemptions"},
+	"channel.charity_campaign.donate/1":                        {"channel:read:charity"},
+	"channel.charity_campaign.progress/1":                      {"channel:read:charity"},
+	"channel.charity_campaign.start/1":                         {"channel:read:charity"},
+	"channel.charity_campaign.stop/1":                          {"channel:read:charity"},
+	"channel.cheer/1":                                          {"bits:read"},
+	"channel.follow/2":                                         {"moderator:read:followers"},
+	"channel.goal.begin/1":                                     {"channel:read:goals"},
+	"channel.goal.end/1":                                       {"channel:read:goals"},
+	"channel.goal.progress/1":                                  {"channel:read:goals"},
+	"channel.hype_train.begin/1":                               {"channel:read:hype_train"},
+	"channel.hype_train.end/1":                                 {"channel:read:hype_train"},
+	"channel.hype_train.progress/1":                            {"channel:read:hype_train"},
+	"channel.moderator.add/1":                                  {"moderation:read"},
+	"channel.moderator.remove/1":                               {"moderation:read"},
+	"channel.poll.begin/1":                                     {"channel:read:polls", "channel:manage:polls"},
+	"channel.poll.end/1":                                       {"channel:read:polls", "channel:manage:polls"},
+	"channel.poll.progress/1":                                  {"channel:read:polls", "channel:manage:polls"},
+	"channel.prediction.begin/1":                               {"channel:read:predictions", "channel:manage:predictions"},
+	"channel.prediction.end/1":                                 {"channel:read:predictions", "channel:manage:predictions"},
+	"channel.prediction.lock/1":                                {"channel:read:predictions", "channel:manage:predictions"},
+	"channel.prediction.progress/1":                            {"channel:read:predictions", "channel:manage:predictions"},
+	"channel.shield_mode.begin/1":                              {"moderator:read:shield_mode", "moderator:manage:shield_mode"},
+	"channel.shield_mode.end/1":                                {"moderator:read:shield_mode", "moderator:manage:shield_mode"},
+	"channel.shoutout.create/1":                                {"moderator:read:shoutouts", "moderator:manage:shoutouts"},
+	"channel.shoutout.receive/1":                               {"moderator:read:shoutouts", "moderator:manage:shoutouts"},
+	"channel.subscribe/1":                                      {"channel:read:subscriptions"},
+	"channel.subscription.end/1":                               {"channel:read:subscriptions"},
+	"channel.subscription.gift/1":                              {"channel:read:subscriptions"},
+	"channel.subscription.message/1":                           {"channel:read:subscriptions"},
+	"channel.unban/1":                                          {"channel:moderate"},
+	"channel.unban_request.create/1":                           {"moderator:read:unban_requests", "moderator:manage:unban_requests"},
+	"channel.unban_request.resolve/1":                          {"moderator:read:unban_requests", "moderator:manage:unban_requests"},
+}
+
+// RequiredScopes returns the scopes an authorization must include one of to subscribe to a topic and version, or nil
+// if the topic doesn't require a scope.
+func RequiredScopes(topic string, version string) []string {
+	return requiredScopes[topic+"/"+version]
+}
+
+// HasRequiredScope returns whether the space-separated scopes of an authorization allow subscribing to a topic and version.
+func HasRequiredScope(topic string, version string, scopes string) bool {
+	required := RequiredScopes(topic, version)
+	if len(required) == 0 {
+		return true
+	}
+
+	for _, s := range strings.Fields(scopes) {
+		for _, r := range required {
+			if s == r {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package types
+
+import (
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestHasRequiredScope(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	a.True(HasRequiredScope("channel.follow", "2", "moderator:read:followers"))
+	a.True(HasRequiredScope("channel.follow", "2", "user:read:email moderator:read:followers"))
+	a.False(HasRequiredScope("channel.follow", "2", ""))
+	a.False(HasRequiredScope("channel.follow", "2", "bits:read"))
+
+	// Any of the alternatives is enough
+	a.True(HasRequiredScope("channel.poll.begin", "1", "channel:manage:polls"))
+	a.True(HasRequiredScope("channel.poll.begin", "1", "channel:read:polls"))
+
+	// Topics without required scopes
+	a.True(HasRequiredScope("channel.update", "2", ""))
+	a.True(HasRequiredScope("stream.online", "1", ""))
+	a.Nil(RequiredScopes("channel.raid", "1"))
+}
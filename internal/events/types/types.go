@@ -19,6 +19,7 @@ import (
 	"github.com/twitchdev/twitch-cli/internal/events/types/channel_update_v2"
 	"github.com/twitchdev/twitch-cli/internal/events/types/charity"
 	"github.com/twitchdev/twitch-cli/internal/events/types/cheer"
+	"github.com/twitchdev/twitch-cli/internal/events/types/conduit_shard_disabled"
 	"github.com/twitchdev/twitch-cli/internal/events/types/drop"
 	"github.com/twitchdev/twitch-cli/internal/events/types/extension_transaction"
 	"github.com/twitchdev/twitch-cli/internal/events/types/follow"
@@ -51,6 +52,7 @@ func AllEvents() []events.MockEvent {
 		channel_points_reward.Event{},
 		charity.Event{},
 		cheer.Event{},
+		conduit_shard_disabled.Event{},
 		drop.Event{},
 		extension_transaction.Event{},
 		follow.Event{},
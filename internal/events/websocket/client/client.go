@@ -0,0 +1,497 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client is a reference EventSub WebSocket client, used by "twitch event websocket connect". It subscribes to
+// topics on the session it's welcomed to, follows session_reconnect messages to their reconnect URL without missing
+// events, and reconnects when no message arrives within the session's keepalive timeout.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+)
+
+// URL of the mock EventSub WebSocket server started with its default flags
+const DefaultURL = "ws://127.0.0.1:8080/ws"
+
+const productionSubscriptionsURL = "https://api.twitch.tv/helix/eventsub/subscriptions"
+
+// Time allowed past the keepalive timeout before the connection is considered lost, for network latency
+const keepaliveGrace = time.Second
+
+// Keepalive timeout used until the session's welcome message says otherwise
+const defaultKeepaliveTimeout = 10 * time.Second
+
+// Number of recent message IDs remembered to detect duplicate messages
+const seenMessageIDs = 1000
+
+// Options of a client connection
+type Options struct {
+	URL              string // WebSocket URL to connect to
+	SubscriptionsURL string // URL of the /eventsub/subscriptions endpoint; Derived from URL when empty
+
+	ClientID string // Sent in the Client-Id header when subscribing
+	Token    string // User access token sent in the Authorization header when subscribing
+
+	Topics    []Topic                  // Topics subscribed to when a session is welcomed
+	Condition models.EventsubCondition // Condition of every subscription
+
+	JSON  bool // Print every message as received, one per line, instead of a summary
+	Debug bool // Print keepalive messages
+
+	TLSConfig *tls.Config // TLS configuration for wss and https URLs; nil uses the system's CAs
+
+	Out io.Writer // Where messages are printed
+	Log io.Writer // Where the connection's state changes are printed
+}
+
+// Topic is an EventSub subscription type and version.
+type Topic struct {
+	Type    string
+	Version string
+}
+
+// ParseTopic parses a topic given as type:version, such as channel.follow:2. The version defaults to 1.
+func ParseTopic(s string) (Topic, error) {
+	topicType, version, found := strings.Cut(s, ":")
+	if topicType == "" || (found && version == "") {
+		return Topic{}, fmt.Errorf("Invalid topic %q; Topics are given as type:version, such as channel.follow:2", s)
+	}
+	if !found {
+		version = "1"
+	}
+	return Topic{Type: topicType, Version: version}, nil
+}
+
+// ParseCondition parses condition fields given as key=value, such as broadcaster_user_id=1234.
+func ParseCondition(pairs []string) (models.EventsubCondition, error) {
+	fields := map[string]string{}
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			return models.EventsubCondition{}, fmt.Errorf("Invalid condition %q; Conditions are given as key=value, such as broadcaster_user_id=1234", pair)
+		}
+		if _, ok := types.ConditionFields(models.EventsubCondition{})[key]; !ok {
+			return models.EventsubCondition{}, fmt.Errorf("Unknown condition field %q", key)
+		}
+		fields[key] = value
+	}
+
+	condition := models.EventsubCondition{}
+	b, _ := json.Marshal(fields)
+	json.Unmarshal(b, &condition)
+	return condition, nil
+}
+
+// SubscriptionsURL returns the /eventsub/subscriptions endpoint used with a WebSocket URL: the Twitch API for Twitch's
+// server, and the same host for any other server, as the mock server serves it on its own port.
+func SubscriptionsURL(wsURL string) (string, error) {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("Invalid WebSocket URL %q; Expected a ws:// or wss:// URL", wsURL)
+	}
+
+	if u.Host == "eventsub.wss.twitch.tv" {
+		return productionSubscriptionsURL, nil
+	}
+	u.Path = "/eventsub/subscriptions"
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
+type message struct {
+	Metadata struct {
+		MessageID           string `json:"message_id"`
+		MessageType         string `json:"message_type"`
+		MessageTimestamp    string `json:"message_timestamp"`
+		SubscriptionType    string `json:"subscription_type"`
+		SubscriptionVersion string `json:"subscription_version"`
+	} `json:"metadata"`
+	Payload struct {
+		Session      *session                     `json:"session"`
+		Subscription *models.EventsubSubscription `json:"subscription"`
+		Event        json.RawMessage              `json:"event"`
+	} `json:"payload"`
+}
+
+type session struct {
+	ID                      string `json:"id"`
+	Status                  string `json:"status"`
+	KeepaliveTimeoutSeconds *int   `json:"keepalive_timeout_seconds"`
+	ReconnectURL            string `json:"reconnect_url"`
+}
+
+// A connection to the server, with the frames read from it
+type connection struct {
+	url         string
+	conn        *websocket.Conn
+	frames      chan frame
+	closed      chan struct{} // Closed once the connection is, to stop reading frames
+	closeOnce   sync.Once
+	sessionID   string // Set when the connection is welcomed
+	fromSession string // Session the connection reconnects, if it was opened for a reconnect URL
+}
+
+type frame struct {
+	data []byte
+	err  error
+}
+
+type client struct {
+	o      Options
+	log    *log.Logger
+	dialer *websocket.Dialer
+	http   *http.Client
+
+	seen    map[string]bool
+	seenIDs []string // Message IDs in seen, oldest first
+}
+
+// Run connects to the server and prints the messages it sends until ctx is done, or the server closes the connection.
+func Run(ctx context.Context, o Options) error {
+	if o.URL == "" {
+		o.URL = DefaultURL
+	}
+	if o.SubscriptionsURL == "" && len(o.Topics) > 0 {
+		subscriptionsURL, err := SubscriptionsURL(o.URL)
+		if err != nil {
+			return err
+		}
+		o.SubscriptionsURL = subscriptionsURL
+	}
+	if len(o.Topics) > 0 && (o.ClientID == "" || o.Token == "") {
+		return errors.New("A client ID and a user access token are required to subscribe to topics")
+	}
+
+	c := &client{
+		o:      o,
+		log:    log.New(o.Log, "", log.LstdFlags),
+		dialer: &websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: 10 * time.Second, TLSClientConfig: o.TLSConfig},
+		http:   &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: o.TLSConfig}},
+		seen:   make(map[string]bool),
+	}
+	return c.run(ctx)
+}
+
+func (c *client) run(ctx context.Context) error {
+	current, err := c.dial(c.o.URL, "")
+	if err != nil {
+		return err
+	}
+	var pending *connection // Connection to a reconnect URL, until it's welcomed
+	defer func() {
+		current.close(websocket.CloseNormalClosure)
+		pending.close(websocket.CloseNormalClosure)
+	}()
+
+	keepaliveTimeout := defaultKeepaliveTimeout
+	keepalive := time.NewTimer(keepaliveTimeout + keepaliveGrace)
+	defer keepalive.Stop()
+
+	for {
+		var pendingFrames chan frame
+		if pending != nil {
+			pendingFrames = pending.frames
+		}
+
+		select {
+		case <-ctx.Done():
+			c.log.Printf("Disconnecting from session [%v]", current.sessionID)
+			return nil
+
+		case <-keepalive.C:
+			// Without a message within the keepalive timeout, the connection is assumed to be lost. The new session has
+			// none of the old one's subscriptions, so they're created again.
+			c.log.Printf("No message received from session [%v] within its keepalive timeout of %v; Reconnecting to %v", current.sessionID, keepaliveTimeout, c.o.URL)
+			current.close(websocket.CloseNormalClosure)
+			pending.close(websocket.CloseNormalClosure)
+			pending = nil
+
+			current, err = c.dial(c.o.URL, "")
+			if err != nil {
+				return err
+			}
+			keepaliveTimeout = defaultKeepaliveTimeout
+			keepalive.Reset(keepaliveTimeout + keepaliveGrace)
+
+		case f := <-current.frames:
+			if f.err != nil {
+				if pending != nil {
+					// The server closed the old connection once the new one was established; Keep waiting for its welcome
+					c.log.Printf("Connection to session [%v] closed while reconnecting: %v", current.sessionID, describeError(f.err))
+					current = pending
+					pending = nil
+					continue
+				}
+				return fmt.Errorf("Connection to session [%v] closed: %v", current.sessionID, describeError(f.err))
+			}
+
+			msg, err := c.handle(current, f.data)
+			if err != nil {
+				return err
+			}
+			resetTimer(keepalive, keepaliveTimeout+keepaliveGrace)
+
+			switch msg.Metadata.MessageType {
+			case "session_welcome":
+				keepaliveTimeout = sessionKeepaliveTimeout(msg.Payload.Session)
+				resetTimer(keepalive, keepaliveTimeout+keepaliveGrace)
+				if current.fromSession != "" {
+					// The old connection was closed before the new one was welcomed
+					c.log.Printf("Reconnected session [%v] as session [%v]", current.fromSession, current.sessionID)
+				} else if err := c.subscribe(current.sessionID); err != nil {
+					return err
+				}
+
+			case "session_reconnect":
+				if msg.Payload.Session == nil || msg.Payload.Session.ReconnectURL == "" {
+					return fmt.Errorf("session_reconnect message from session [%v] has no reconnect_url", current.sessionID)
+				}
+				// Events keep arriving on the old connection until the new one is welcomed
+				c.log.Printf("Reconnecting session [%v] to %v", current.sessionID, msg.Payload.Session.ReconnectURL)
+				pending.close(websocket.CloseNormalClosure)
+				pending, err = c.dial(msg.Payload.Session.ReconnectURL, current.sessionID)
+				if err != nil {
+					return err
+				}
+			}
+
+		case f := <-pendingFrames:
+			if f.err != nil {
+				return fmt.Errorf("Connection to reconnect URL %v closed: %v", pending.url, describeError(f.err))
+			}
+
+			msg, err := c.handle(pending, f.data)
+			if err != nil {
+				return err
+			}
+
+			if msg.Metadata.MessageType == "session_welcome" {
+				// Subscriptions carry over to the new connection, so the old one can be closed
+				c.log.Printf("Reconnected session [%v] as session [%v]", current.sessionID, pending.sessionID)
+				current.close(websocket.CloseNormalClosure)
+				current = pending
+				pending = nil
+				keepaliveTimeout = sessionKeepaliveTimeout(msg.Payload.Session)
+				resetTimer(keepalive, keepaliveTimeout+keepaliveGrace)
+			}
+		}
+	}
+}
+
+// Connects to the server and starts reading frames from the connection. fromSession is the session reconnecting to a
+// reconnect URL, or empty for new sessions.
+func (c *client) dial(u string, fromSession string) (*connection, error) {
+	conn, _, err := c.dialer.Dial(u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Could not connect to %v: %v", u, err)
+	}
+	c.log.Printf("Connected to %v", u)
+
+	cn := &connection{url: u, conn: conn, frames: make(chan frame), closed: make(chan struct{}), fromSession: fromSession}
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			select {
+			case cn.frames <- frame{data: data, err: err}:
+			case <-cn.closed:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return cn, nil
+}
+
+// Closes the connection with a close frame, ignoring connections already closed
+func (cn *connection) close(code int) {
+	if cn == nil {
+		return
+	}
+	cn.closeOnce.Do(func() {
+		close(cn.closed)
+		cn.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, ""), time.Now().Add(time.Second))
+		cn.conn.Close()
+	})
+}
+
+// Prints a message received on a connection, and returns it parsed
+func (c *client) handle(cn *connection, data []byte) (message, error) {
+	msg := message{}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, fmt.Errorf("Invalid message from session [%v]: %v\n%s", cn.sessionID, err, data)
+	}
+
+	duplicate := c.isDuplicate(msg.Metadata.MessageID)
+	if c.o.JSON && (msg.Metadata.MessageType != "session_keepalive" || c.o.Debug) {
+		fmt.Fprintf(c.o.Out, "%s\n", bytes.TrimSpace(data))
+	}
+
+	switch msg.Metadata.MessageType {
+	case "session_welcome":
+		if msg.Payload.Session == nil || msg.Payload.Session.ID == "" {
+			return msg, fmt.Errorf("session_welcome message has no session ID\n%s", data)
+		}
+		if cn.sessionID != "" {
+			return msg, fmt.Errorf("Session [%v] was welcomed twice", cn.sessionID)
+		}
+		cn.sessionID = msg.Payload.Session.ID
+		c.log.Printf("Welcomed to session [%v] with a keepalive timeout of %v", cn.sessionID, sessionKeepaliveTimeout(msg.Payload.Session))
+
+	case "session_keepalive":
+		if c.o.Debug {
+			c.log.Printf("Keepalive from session [%v]", cn.sessionID)
+		}
+
+	case "notification", "revocation":
+		if duplicate {
+			// Twitch may send a message more than once; Clients should only process it once
+			c.log.Printf("Ignored duplicate %v [%v / %v] with message ID [%v]", msg.Metadata.MessageType, msg.Metadata.SubscriptionType, msg.Metadata.SubscriptionVersion, msg.Metadata.MessageID)
+			return msg, nil
+		}
+		if !c.o.JSON {
+			c.printNotification(cn, msg)
+		}
+
+	case "session_reconnect":
+		// Handled by the caller
+
+	default:
+		c.log.Printf("Unknown message type [%v] from session [%v]", msg.Metadata.MessageType, cn.sessionID)
+	}
+
+	return msg, nil
+}
+
+func (c *client) printNotification(cn *connection, msg message) {
+	subscriptionID := ""
+	status := ""
+	if msg.Payload.Subscription != nil {
+		subscriptionID = msg.Payload.Subscription.ID
+		status = msg.Payload.Subscription.Status
+	}
+
+	if msg.Metadata.MessageType == "revocation" {
+		c.log.Printf("Subscription [%v] to [%v / %v] was revoked with status [%v]", subscriptionID, msg.Metadata.SubscriptionType, msg.Metadata.SubscriptionVersion, status)
+		return
+	}
+
+	c.log.Printf("Notification [%v / %v] for subscription [%v] on session [%v]", msg.Metadata.SubscriptionType, msg.Metadata.SubscriptionVersion, subscriptionID, cn.sessionID)
+	var event bytes.Buffer
+	if err := json.Indent(&event, msg.Payload.Event, "", "  "); err == nil {
+		fmt.Fprintln(c.o.Out, event.String())
+	}
+}
+
+// Returns whether a message with the ID was already received, and remembers the ID
+func (c *client) isDuplicate(messageID string) bool {
+	if messageID == "" {
+		return false
+	}
+	if c.seen[messageID] {
+		return true
+	}
+
+	c.seen[messageID] = true
+	c.seenIDs = append(c.seenIDs, messageID)
+	if len(c.seenIDs) > seenMessageIDs {
+		delete(c.seen, c.seenIDs[0])
+		c.seenIDs = c.seenIDs[1:]
+	}
+	return false
+}
+
+// Creates a subscription to each topic on the session
+func (c *client) subscribe(sessionID string) error {
+	for _, topic := range c.o.Topics {
+		body, _ := json.Marshal(models.EventsubSubscription{
+			Type:      topic.Type,
+			Version:   topic.Version,
+			Condition: c.o.Condition,
+			Transport: models.EventsubTransport{Method: models.TransportWebSocket, SessionID: sessionID},
+		})
+
+		req, err := http.NewRequest(http.MethodPost, c.o.SubscriptionsURL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Client-Id", c.o.ClientID)
+		req.Header.Set("Authorization", "Bearer "+c.o.Token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("Could not subscribe to [%v / %v]: %v", topic.Type, topic.Version, err)
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusAccepted {
+			return fmt.Errorf("Could not subscribe to [%v / %v]: %v %s", topic.Type, topic.Version, resp.StatusCode, bytes.TrimSpace(respBody))
+		}
+
+		created := struct {
+			Data []models.EventsubSubscription `json:"data"`
+		}{}
+		json.Unmarshal(respBody, &created)
+		subscriptionID := ""
+		if len(created.Data) > 0 {
+			subscriptionID = created.Data[0].ID
+		}
+		c.log.Printf("Subscribed to [%v / %v] with subscription [%v]", topic.Type, topic.Version, subscriptionID)
+	}
+
+	return nil
+}
+
+func sessionKeepaliveTimeout(s *session) time.Duration {
+	if s == nil || s.KeepaliveTimeoutSeconds == nil {
+		return defaultKeepaliveTimeout
+	}
+	return time.Duration(*s.KeepaliveTimeoutSeconds) * time.Second
+}
+
+// Describes why a connection was closed, with the close code and reason if the server sent a close frame
+func describeError(err error) string {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		return fmt.Sprintf("close code [%v] with reason [%v]", closeErr.Code, closeErr.Text)
+	}
+	return err.Error()
+}
+
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
@@ -0,0 +1,190 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestParse(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	topic, err := ParseTopic("channel.follow:2")
+	a.Nil(err)
+	a.Equal(Topic{Type: "channel.follow", Version: "2"}, topic)
+	topic, err = ParseTopic("channel.update")
+	a.Nil(err)
+	a.Equal("1", topic.Version)
+	_, err = ParseTopic("channel.follow:")
+	a.NotNil(err)
+
+	condition, err := ParseCondition([]string{"broadcaster_user_id=1234", "moderator_user_id=5678"})
+	a.Nil(err)
+	a.Equal(models.EventsubCondition{BroadcasterUserID: "1234", ModeratorUserID: "5678"}, condition)
+	_, err = ParseCondition([]string{"broadcaster=1234"})
+	a.NotNil(err)
+	_, err = ParseCondition([]string{"broadcaster_user_id"})
+	a.NotNil(err)
+
+	u, err := SubscriptionsURL("ws://127.0.0.1:8080/ws?keepalive_timeout_seconds=30")
+	a.Nil(err)
+	a.Equal("http://127.0.0.1:8080/eventsub/subscriptions", u)
+	u, err = SubscriptionsURL("wss://eventsub.wss.twitch.tv/ws")
+	a.Nil(err)
+	a.Equal(productionSubscriptionsURL, u)
+	_, err = SubscriptionsURL("http://127.0.0.1:8080/ws")
+	a.NotNil(err)
+}
+
+func TestRun(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	var mu sync.Mutex
+	subscribed := []string{} // Sessions subscribed to, in order
+	sessions := 0
+	done := make(chan struct{})
+
+	upgrader := websocket.Upgrader{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eventsub/subscriptions" {
+			body := models.EventsubSubscription{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.Header.Get("Authorization") != "Bearer token" || body.Condition.BroadcasterUserID != "1234" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			mu.Lock()
+			subscribed = append(subscribed, body.Transport.SessionID)
+			if len(subscribed) == 2 {
+				close(done)
+			}
+			mu.Unlock()
+
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintf(w, `{"data":[{"id":"sub_%v","type":"%v","version":"%v"}]}`, body.Transport.SessionID, body.Type, body.Version)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mu.Lock()
+		sessions++
+		sessionID := fmt.Sprintf("s%v", sessions)
+		mu.Unlock()
+
+		send := func(messageType string, messageID string, payload string) {
+			conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+				`{"metadata":{"message_id":"%v","message_type":"%v","message_timestamp":"2023-01-01T00:00:00Z","subscription_type":"channel.follow","subscription_version":"2"},"payload":%v}`,
+				messageID, messageType, payload,
+			)))
+		}
+		welcome := func(keepaliveSeconds int) {
+			send("session_welcome", sessionID+"_welcome", fmt.Sprintf(`{"session":{"id":"%v","status":"connected","keepalive_timeout_seconds":%v,"reconnect_url":null}}`, sessionID, keepaliveSeconds))
+		}
+		notification := func(messageID string) {
+			send("notification", messageID, fmt.Sprintf(`{"subscription":{"id":"sub_%v","status":"enabled"},"event":{"message":"%v"}}`, sessionID, messageID))
+		}
+
+		switch sessionID {
+		case "s1":
+			// Sends a duplicate notification, then moves the session to s2
+			welcome(10)
+			waitForSubscriptions(&mu, &subscribed, 1)
+			notification("n1")
+			notification("n1")
+			send("session_reconnect", sessionID+"_reconnect", fmt.Sprintf(`{"session":{"id":"s1","status":"reconnecting","keepalive_timeout_seconds":null,"reconnect_url":"%v?reconnect_id=s1"}}`, strings.Replace(server.URL, "http", "ws", 1)))
+
+			// The client closes the connection once it's welcomed to s2
+			_, _, err := conn.ReadMessage()
+			if closeErr, ok := err.(*websocket.CloseError); !ok || closeErr.Code != websocket.CloseNormalClosure {
+				t.Errorf("Expected the client to close s1 after reconnecting, got %v", err)
+			}
+
+		case "s2":
+			// Sends a notification, then stops sending keepalives
+			if r.URL.Query().Get("reconnect_id") != "s1" {
+				t.Errorf("Expected s2 to be connected to the reconnect URL")
+			}
+			welcome(1)
+			notification("n2")
+			conn.ReadMessage()
+
+		case "s3":
+			// The client reconnected to the original URL after the keepalive timeout
+			welcome(10)
+			<-done
+			conn.ReadMessage()
+		}
+	}))
+	defer server.Close()
+
+	var out, logs bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() {
+		result <- Run(ctx, Options{
+			URL:       strings.Replace(server.URL, "http", "ws", 1) + "/ws",
+			ClientID:  "client",
+			Token:     "token",
+			Topics:    []Topic{{Type: "channel.follow", Version: "2"}},
+			Condition: models.EventsubCondition{BroadcasterUserID: "1234", ModeratorUserID: "1234"},
+			Out:       &out,
+			Log:       &logs,
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Timed out; Log:\n%v", logs.String())
+	}
+	cancel()
+	a.Nil(<-result)
+
+	// Subscriptions carry over reconnects, but are created again on new sessions
+	a.Equal([]string{"s1", "s3"}, subscribed)
+	a.Equal(1, strings.Count(out.String(), `"message": "n1"`))
+	a.Equal(1, strings.Count(out.String(), `"message": "n2"`))
+	a.Contains(logs.String(), "Ignored duplicate notification [channel.follow / 2] with message ID [n1]")
+	a.Contains(logs.String(), "Reconnected session [s1] as session [s2]")
+	a.Contains(logs.String(), "No message received from session [s2] within its keepalive timeout of 1s")
+}
+
+func TestRunRequiresCredentials(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	err := Run(context.Background(), Options{Topics: []Topic{{Type: "channel.follow", Version: "2"}}})
+	a.NotNil(err)
+}
+
+// Waits until the number of subscriptions created reaches count
+func waitForSubscriptions(mu *sync.Mutex, subscribed *[]string, count int) {
+	for {
+		mu.Lock()
+		n := len(*subscribed)
+		mu.Unlock()
+		if n >= count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
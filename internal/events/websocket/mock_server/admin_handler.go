@@ -0,0 +1,750 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+var sessionRegex = regexp.MustCompile(`(?P<server_name>.+)_(?P<client_name>.+)`)
+
+// Response - GET /admin/sessions
+type AdminSession struct {
+	ID                      string         `json:"id"`
+	ConnectedAt             string         `json:"connected_at"`
+	KeepaliveEnabled        bool           `json:"keepalive_enabled"`
+	KeepaliveTimeoutSeconds int            `json:"keepalive_timeout_seconds"`
+	SubscriptionCount       int            `json:"subscription_count"`
+	MessagesSent            map[string]int `json:"messages_sent"` // Messages sent to the session, by message type
+}
+
+// Response - GET /admin/conduits
+type AdminConduit struct {
+	ID       string              `json:"id"`
+	ClientID string              `json:"client_id"`
+	Shards   []ShardResponseBody `json:"shards"`
+}
+
+// Response - GET /admin/health
+type AdminHealth struct {
+	ServerID         string `json:"server_id"`
+	ReconnectTesting bool   `json:"reconnect_testing"`
+	WebSocketURL     string `json:"websocket_url"`
+	SubscriptionsURL string `json:"subscriptions_url"`
+}
+
+// Errors returned by admin commands carry the status code the admin API responds with
+type adminError struct {
+	status  int
+	message string
+}
+
+func (e *adminError) Error() string {
+	return e.message
+}
+
+func newAdminError(status int, format string, a ...interface{}) error {
+	return &adminError{status: status, message: fmt.Sprintf(format, a...)}
+}
+
+func registerAdminHandlers(s *admin.Server) {
+	s.Handle(http.MethodGet, "/admin/health", adminHealthHandler)
+	s.Handle(http.MethodPost, "/admin/events", adminEmitEventHandler)
+	s.Handle(http.MethodPost, "/admin/events/websocket", adminFireWebSocketEventHandler)
+	s.Handle(http.MethodPost, "/admin/events/webhook", adminFireWebhookEventHandler)
+	s.Handle(http.MethodPost, "/admin/reconnect", adminReconnectHandler)
+	s.Handle(http.MethodGet, "/admin/sessions", adminSessionsHandler)
+	s.Handle(http.MethodPost, "/admin/sessions/close", adminCloseHandler)
+	s.Handle(http.MethodPost, "/admin/sessions/keepalive", adminKeepaliveHandler)
+	s.Handle(http.MethodGet, "/admin/subscriptions", adminSubscriptionsHandler)
+	s.Handle(http.MethodPost, "/admin/subscriptions/status", adminSubscriptionStatusHandler)
+	s.Handle(http.MethodGet, "/admin/conduits", adminConduitsHandler)
+	s.Handle(http.MethodPost, "/admin/authorizations/revoke", adminRevokeAuthorizationsHandler)
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	if e, ok := err.(*adminError); ok {
+		admin.WriteError(w, e.status, e.message)
+		return
+	}
+	admin.WriteError(w, http.StatusInternalServerError, err.Error())
+}
+
+// GET /admin/health
+func adminHealthHandler(w http.ResponseWriter, r *http.Request) {
+	admin.WriteJSON(w, "", AdminHealth{
+		ServerID:         serverManager.primaryServer,
+		ReconnectTesting: serverManager.reconnectTesting,
+		WebSocketURL:     fmt.Sprintf("%v://%v:%v/ws", serverManager.protocolWs, serverManager.ip, serverManager.port),
+		SubscriptionsURL: fmt.Sprintf("%v://%v:%v/eventsub/subscriptions", serverManager.protocolHttp, serverManager.ip, serverManager.port),
+	})
+}
+
+// POST /admin/events/websocket
+// $ twitch event trigger <event> --transport=websocket
+func adminFireWebSocketEventHandler(w http.ResponseWriter, r *http.Request) {
+	var body admin.EventRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+	if len(body.Event) == 0 {
+		admin.WriteError(w, http.StatusBadRequest, "The event field is required")
+		return
+	}
+
+	msg, err := fireWebSocketEvent(string(body.Event), body.Session, body.MessageID, body.MessageTimestamp)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, msg, nil)
+}
+
+// POST /admin/events/webhook
+// $ twitch event trigger <event> --transport=webhook
+// Only used when no forward address is given
+func adminFireWebhookEventHandler(w http.ResponseWriter, r *http.Request) {
+	var body admin.EventRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+	if len(body.Event) == 0 {
+		admin.WriteError(w, http.StatusBadRequest, "The event field is required")
+		return
+	}
+
+	msg, err := fireWebhookEvent(string(body.Event), body.MessageID, body.MessageTimestamp)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, msg, nil)
+}
+
+// POST /admin/events
+// Sent by the mock API when its endpoints change state
+func adminEmitEventHandler(w http.ResponseWriter, r *http.Request) {
+	var body admin.EventRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+	if len(body.Event) == 0 {
+		admin.WriteError(w, http.StatusBadRequest, "The event field is required")
+		return
+	}
+
+	msg, err := emitEvent(string(body.Event))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, msg, nil)
+}
+
+// POST /admin/reconnect
+// $ twitch event websocket reconnect
+func adminReconnectHandler(w http.ResponseWriter, r *http.Request) {
+	// The body is optional, to restart the server with the defaults
+	var body admin.ReconnectRequest
+	if r.ContentLength != 0 && !admin.DecodeBody(w, r, &body) {
+		return
+	}
+
+	o := ReconnectOptions{
+		Sessions:            body.Sessions,
+		Percent:             body.Percent,
+		Waves:               1,
+		WaveInterval:        time.Duration(body.WaveIntervalSeconds) * time.Second,
+		CloseOldConnections: body.CloseOldConnections,
+	}
+	if body.Waves != nil {
+		o.Waves = *body.Waves
+	}
+	if body.GracePeriodSeconds != nil {
+		gracePeriod := time.Duration(*body.GracePeriodSeconds) * time.Second
+		o.GracePeriod = &gracePeriod
+	}
+
+	err := startReconnect(o)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, "Reconnect testing started", nil)
+}
+
+// GET /admin/sessions
+func adminSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	sessions, err := listSessions()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, "", sessions)
+}
+
+// POST /admin/sessions/close
+// $ twitch event websocket close
+func adminCloseHandler(w http.ResponseWriter, r *http.Request) {
+	var body admin.CloseRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+
+	if err := closeSession(body.Session, body.Code); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, fmt.Sprintf("Closed session [%v] with code [%v]", body.Session, body.Code), nil)
+}
+
+// POST /admin/sessions/keepalive
+// $ twitch event websocket keepalive
+func adminKeepaliveHandler(w http.ResponseWriter, r *http.Request) {
+	var body admin.KeepaliveRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+	if body.Enabled == nil {
+		admin.WriteError(w, http.StatusBadRequest, "The enabled field is required")
+		return
+	}
+
+	if err := setKeepalive(body.Session, *body.Enabled); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, fmt.Sprintf("Set keepalive messages for session [%v]: %v", body.Session, *body.Enabled), nil)
+}
+
+// GET /admin/subscriptions?session=<session_id>
+func adminSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := listSubscriptions(r.URL.Query().Get("session"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, "", subscriptions)
+}
+
+// POST /admin/subscriptions/status
+// $ twitch event websocket subscription
+func adminSubscriptionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	var body admin.SubscriptionStatusRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+
+	if err := setSubscriptionStatus(body.SubscriptionID, body.Status); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	admin.WriteJSON(w, fmt.Sprintf("Set status of subscription [%v] to [%v]", body.SubscriptionID, body.Status), nil)
+}
+
+// GET /admin/conduits
+func adminConduitsHandler(w http.ResponseWriter, r *http.Request) {
+	admin.WriteJSON(w, "", listConduits())
+}
+
+// Returns the primary server, which is where all admin commands are run
+func primaryServer() (*WebSocketServer, error) {
+	server, ok := serverManager.serverList.Get(serverManager.primaryServer)
+	if !ok {
+		log.Printf("Error on admin command: Primary server not in server list.")
+		return nil, newAdminError(http.StatusInternalServerError, "Primary server not in server list.")
+	}
+	return server, nil
+}
+
+// Users can include the full session_id given in the welcome message. If they do, subtract it to just the client name
+func clientNameFromSession(session string) string {
+	if sessionRegex.MatchString(session) {
+		return sessionRegex.FindAllStringSubmatch(session, -1)[0][2]
+	}
+	return session
+}
+
+func startReconnect(o ReconnectOptions) error {
+	// Initiate reconnect testing
+	log.Printf("Initiating reconnect testing...")
+
+	if serverManager.reconnectTesting {
+		log.Printf("Error on admin command (reconnect): Cannot execute reconnect testing while its already in progress. Discarding duplicate reconnect command.")
+		return newAdminError(http.StatusConflict, "Cannot execute reconnect testing while its already in progress. Discarding duplicate reconnect command.")
+	}
+
+	if err := o.Validate(); err != nil {
+		return newAdminError(http.StatusBadRequest, err.Error())
+	}
+
+	// Find current primary server
+	originalPrimaryServer, err := primaryServer()
+	if err != nil {
+		return err
+	}
+
+	if o.Rolling() {
+		return startRollingReconnect(originalPrimaryServer, o)
+	}
+
+	serverManager.reconnectTesting = true
+
+	// Get the list of reconnect clients ready
+	reconnectClients := originalPrimaryServer.GetCurrentSubscriptionsForReconnect()
+
+	// Spin up new server
+	newServer := &WebSocketServer{
+		ServerId: util.RandomGUID()[:8],
+		Status:   2,
+		Clients: &util.List[Client]{
+			Elements: make(map[string]*Client),
+		},
+		Upgrader:         websocket.Upgrader{},
+		DebugEnabled:     serverManager.debugEnabled,
+		Subscriptions:    make(map[string][]Subscription),
+		StrictMode:       serverManager.strictMode,
+		ReconnectClients: reconnectClients,
+	}
+	serverManager.serverList.Put(newServer.ServerId, newServer)
+
+	// Switch manager's primary server to new one
+	// Doing this before sending the reconnect messages emulates the Twitch's production load balancer, which will never send to servers shutting down.
+	serverManager.primaryServer = newServer.ServerId
+
+	// Notify primary server to restart (includes not accepting new clients)
+	// This is in a goroutine so it doesn't hang the reconnect command
+	go func() {
+		originalPrimaryServer.InitiateRestart(o)
+
+		// Remove server from server list
+		serverManager.serverList.Delete(originalPrimaryServer.ServerId)
+
+		if serverManager.debugEnabled {
+			log.Printf(
+				"Removed server [%v] from server list. New server list count: %v",
+				originalPrimaryServer.ServerId,
+				serverManager.serverList.Length(),
+			)
+		}
+
+		serverManager.reconnectTesting = false
+
+		log.Printf("Reconnect testing successful. Primary server is now [%v]\nYou may now execute reconnect testing again.", serverManager.primaryServer)
+	}()
+
+	return nil
+}
+
+// Reconnects some of the primary server's sessions, or all of them in waves, without restarting the server
+func startRollingReconnect(server *WebSocketServer, o ReconnectOptions) error {
+	clients, err := server.selectReconnectClients(o)
+	if err != nil {
+		return err
+	}
+
+	serverManager.reconnectTesting = true
+
+	go func() {
+		server.RollingRestart(clients, o)
+
+		serverManager.reconnectTesting = false
+
+		log.Printf("Reconnect testing successful. Primary server is still [%v]\nYou may now execute reconnect testing again.", server.ServerId)
+	}()
+
+	return nil
+}
+
+func fireWebSocketEvent(eventsubBody string, session string, messageID string, messageTimestamp string) (string, error) {
+	server, err := primaryServer()
+	if err != nil {
+		return "", err
+	}
+
+	clientName := clientNameFromSession(session)
+
+	// Conduits are skipped when --session targets a specific client
+	conduitCount := 0
+	if clientName == "" {
+		count, err := serverManager.HandleConduitForwarding(eventsubBody, messageID, messageTimestamp)
+		if err != nil {
+			log.Printf("Error on admin command (events/websocket): %v", err)
+		}
+		conduitCount = count
+	}
+
+	success, failMsg := server.HandleEventSubForwarding(eventsubBody, clientName, messageID, messageTimestamp)
+	if !success && conduitCount == 0 {
+		return "", newAdminError(http.StatusNotFound, failMsg)
+	}
+
+	return "Forwarded for use in mock EventSub WebSocket server", nil
+}
+
+func fireWebhookEvent(eventsubBody string, messageID string, messageTimestamp string) (string, error) {
+	count, err := serverManager.HandleWebhookForwarding(eventsubBody, messageID, messageTimestamp)
+	if err != nil {
+		log.Printf("Error on admin command (events/webhook): %v", err)
+		return "", newAdminError(http.StatusBadRequest, err.Error())
+	}
+
+	conduitCount, err := serverManager.HandleConduitForwarding(eventsubBody, messageID, messageTimestamp)
+	if err != nil {
+		log.Printf("Error on admin command (events/webhook): %v", err)
+	}
+
+	if count == 0 && conduitCount == 0 {
+		return "", newAdminError(http.StatusNotFound, "No webhook or conduit subscriptions match the event")
+	}
+
+	return fmt.Sprintf("Sent to %v webhook subscriptions and %v conduit subscriptions", count, conduitCount), nil
+}
+
+// Sends an event to the subscriptions of every transport matching it. Unlike triggered events, the event isn't sent to
+// sessions without a matching subscription.
+func emitEvent(eventsubBody string) (string, error) {
+	eventObj := models.EventsubResponse{}
+	if err := json.Unmarshal([]byte(eventsubBody), &eventObj); err != nil {
+		return "", newAdminError(http.StatusBadRequest, "Error reading event JSON: %v", err)
+	}
+
+	serverManager.emitServerEvent(json.RawMessage(eventsubBody))
+
+	return fmt.Sprintf("Sent [%v / %v] to matching subscriptions", eventObj.Subscription.Type, eventObj.Subscription.Version), nil
+}
+
+func closeSession(session string, closeCode int) error {
+	if session == "" || closeCode == 0 {
+		return newAdminError(http.StatusBadRequest, "Closing a session requires a session and a close code"+
+			"\nThe close code must be one of the number codes defined here:"+
+			"\nhttps://dev.twitch.tv/docs/eventsub/websocket-reference/#close-message"+
+			"\n\nExample: twitch event websocket close --session=e411cc1e_a2613d4e --reason=4006")
+	}
+
+	if serverManager.reconnectTesting {
+		log.Printf("Error on admin command (sessions/close): Could not activate while reconnect testing is active.")
+		return newAdminError(http.StatusConflict, "Cannot activate this command while reconnect testing is active.")
+	}
+
+	server, err := primaryServer()
+	if err != nil {
+		return err
+	}
+
+	clientName := clientNameFromSession(session)
+
+	server.muClients.Lock()
+
+	client, ok := server.Clients.Get(clientName)
+	if !ok {
+		server.muClients.Unlock()
+		return newAdminError(http.StatusNotFound, "Client [%v] does not exist on WebSocket server.", clientName)
+	}
+
+	closeMessage := GetCloseMessageFromCode(closeCode)
+	if closeMessage == nil {
+		server.muClients.Unlock()
+		return newAdminError(http.StatusBadRequest, "Close code [%v] not supported.", closeCode)
+	}
+
+	server.muClients.Unlock()
+
+	client.CloseWithReason(closeMessage)
+	server.handleClientConnectionClose(client, closeMessage)
+
+	log.Printf("Admin API instructed to close client [%v] with code [%v]", clientName, closeCode)
+
+	return nil
+}
+
+func setKeepalive(session string, enabled bool) error {
+	if session == "" {
+		return newAdminError(http.StatusBadRequest, "Setting keepalive messages requires a session"+
+			"\n\nExample: twitch event websocket keepalive --session=e411cc1e_a2613d4e --enabled=false")
+	}
+
+	if serverManager.reconnectTesting {
+		log.Printf("Error on admin command (sessions/keepalive): Could not activate while reconnect testing is active.")
+		return newAdminError(http.StatusConflict, "Cannot activate this command while reconnect testing is active.")
+	}
+
+	server, err := primaryServer()
+	if err != nil {
+		return err
+	}
+
+	clientName := clientNameFromSession(session)
+
+	server.muClients.Lock()
+
+	client, ok := server.Clients.Get(clientName)
+	if !ok {
+		server.muClients.Unlock()
+		return newAdminError(http.StatusNotFound, "Client [%v] does not exist on WebSocket server.", clientName)
+	}
+
+	client.KeepAliveEnabled = enabled
+
+	server.muClients.Unlock()
+
+	log.Printf("Admin API set status on client feature [KeepAliveEnabled] for client [%v]: %v", clientName, enabled)
+
+	return nil
+}
+
+func setSubscriptionStatus(subscriptionID string, status string) error {
+	if subscriptionID == "" || !IsValidSubscriptionStatus(status) {
+		return newAdminError(http.StatusBadRequest, "Changing a subscription's status requires a subscription ID and a status"+
+			fmt.Sprintf("\nThe subscription ID must be the ID of the subscription made at %v://%v:%v/eventsub/subscriptions", serverManager.protocolHttp, serverManager.ip, serverManager.port)+
+			"\nThe status must be one of the non-webhook status options defined here:"+
+			"\nhttps://dev.twitch.tv/docs/api/reference/#get-eventsub-subscriptions"+
+			"\n\nExample: twitch event websocket subscription --status=user_removed --subscription=82a855-fae8-93bff0")
+	}
+
+	if serverManager.reconnectTesting {
+		return newAdminError(http.StatusConflict, "Cannot activate this command while reconnect testing is active.")
+	}
+
+	server, err := primaryServer()
+	if err != nil {
+		return err
+	}
+
+	server.muSubscriptions.Lock()
+	found := false
+	for client, clientSubscriptions := range server.Subscriptions {
+		if found {
+			break
+		}
+
+		for i, sub := range clientSubscriptions {
+			if sub.SubscriptionID == subscriptionID {
+				found = true
+
+				server.Subscriptions[client][i].Status = status
+				if status == STATUS_ENABLED {
+					server.Subscriptions[client][i].DisabledAt = nil
+				} else {
+					tNow := util.GetTimestamp()
+					server.Subscriptions[client][i].DisabledAt = &tNow
+				}
+				server.subscriptionsChanged()
+				break
+			}
+		}
+	}
+	server.muSubscriptions.Unlock()
+
+	if !found {
+		return newAdminError(http.StatusNotFound, "Subscription ID [%v] does not exist", subscriptionID)
+	}
+
+	return nil
+}
+
+// Returns the sessions connected to the primary server, sorted by the time they connected
+func listSessions() ([]AdminSession, error) {
+	server, err := primaryServer()
+	if err != nil {
+		return nil, err
+	}
+
+	server.muClients.Lock()
+	clients := server.Clients.All()
+	server.muClients.Unlock()
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ConnectedAtTimestamp < clients[j].ConnectedAtTimestamp })
+
+	server.muSubscriptions.Lock()
+	defer server.muSubscriptions.Unlock()
+
+	sessions := []AdminSession{}
+	for _, c := range clients {
+		sessions = append(sessions, AdminSession{
+			ID:                      fmt.Sprintf("%v_%v", server.ServerId, c.clientName),
+			ConnectedAt:             c.ConnectedAtTimestamp,
+			KeepaliveEnabled:        c.KeepAliveEnabled,
+			KeepaliveTimeoutSeconds: c.keepAliveSeconds,
+			SubscriptionCount:       len(server.Subscriptions[c.clientName]),
+			MessagesSent:            c.MessageCounts(),
+		})
+	}
+	return sessions, nil
+}
+
+// Returns the subscriptions of every transport, or only the WebSocket subscriptions of a session when one is given
+func listSubscriptions(session string) ([]SubscriptionPostSuccessResponseBody, error) {
+	server, err := primaryServer()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := []SubscriptionPostSuccessResponseBody{}
+	clientName := clientNameFromSession(session)
+
+	server.muClients.Lock()
+	_, connected := server.Clients.Get(clientName)
+	server.muClients.Unlock()
+
+	server.muSubscriptions.Lock()
+	if session != "" {
+		if _, ok := server.Subscriptions[clientName]; !ok && !connected {
+			server.muSubscriptions.Unlock()
+			return nil, newAdminError(http.StatusNotFound, "Client [%v] does not exist on WebSocket server.", clientName)
+		}
+	}
+	for name, clientSubscriptions := range server.Subscriptions {
+		if session != "" && name != clientName {
+			continue
+		}
+		for _, s := range clientSubscriptions {
+			subscriptions = append(subscriptions, SubscriptionPostSuccessResponseBody{
+				ID:        s.SubscriptionID,
+				Status:    s.Status,
+				Type:      s.Type,
+				Version:   s.Version,
+				Condition: s.Conditions,
+				CreatedAt: s.CreatedAt,
+				Transport: SubscriptionTransport{
+					Method:         models.TransportWebSocket,
+					SessionID:      fmt.Sprintf("%v_%v", server.ServerId, name),
+					ConnectedAt:    s.ClientConnectedAt,
+					DisconnectedAt: s.ClientDisconnectedAt,
+				},
+				Cost: s.Cost,
+			})
+		}
+	}
+	server.muSubscriptions.Unlock()
+
+	if session == "" {
+		serverManager.muWebhookSubscriptions.Lock()
+		for _, s := range serverManager.webhookSubscriptions {
+			subscriptions = append(subscriptions, SubscriptionPostSuccessResponseBody{
+				ID:        s.SubscriptionID,
+				Status:    s.Status,
+				Type:      s.Type,
+				Version:   s.Version,
+				Condition: s.Conditions,
+				CreatedAt: s.CreatedAt,
+				Transport: SubscriptionTransport{
+					Method:   models.TransportWebhook,
+					Callback: s.Callback,
+				},
+				Cost: s.Cost,
+			})
+		}
+		serverManager.muWebhookSubscriptions.Unlock()
+
+		serverManager.muConduits.Lock()
+		for _, s := range serverManager.conduitSubscriptions {
+			subscriptions = append(subscriptions, SubscriptionPostSuccessResponseBody{
+				ID:        s.SubscriptionID,
+				Status:    s.Status,
+				Type:      s.Type,
+				Version:   s.Version,
+				Condition: s.Conditions,
+				CreatedAt: s.CreatedAt,
+				Transport: SubscriptionTransport{
+					Method:    TRANSPORT_CONDUIT,
+					ConduitID: s.ConduitID,
+				},
+				Cost: s.Cost,
+			})
+		}
+		serverManager.muConduits.Unlock()
+	}
+
+	sort.SliceStable(subscriptions, func(i, j int) bool { return subscriptions[i].CreatedAt < subscriptions[j].CreatedAt })
+	return subscriptions, nil
+}
+
+func listConduits() []AdminConduit {
+	serverManager.muConduits.Lock()
+	defer serverManager.muConduits.Unlock()
+
+	conduits := []AdminConduit{}
+	for _, c := range serverManager.conduits {
+		shards := []ShardResponseBody{}
+		for _, s := range c.Shards {
+			shards = append(shards, s.toResponseBody())
+		}
+		conduits = append(conduits, AdminConduit{
+			ID:       c.ID,
+			ClientID: c.ClientID,
+			Shards:   shards,
+		})
+	}
+	return conduits
+}
+
+// Prints sessions as a table; Used by the interactive shell and "twitch event websocket sessions"
+func PrintSessions(w io.Writer, sessions []AdminSession) error {
+	if len(sessions) == 0 {
+		fmt.Fprintln(w, "No sessions connected.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SESSION\tCONNECTED AT\tSUBSCRIPTIONS\tKEEPALIVE\tKEEPALIVE TIMEOUT\tMESSAGES SENT")
+	for _, s := range sessions {
+		keepalive := "on"
+		if !s.KeepaliveEnabled {
+			keepalive = "off"
+		}
+
+		messageTypes := []string{}
+		for t := range s.MessagesSent {
+			messageTypes = append(messageTypes, t)
+		}
+		sort.Strings(messageTypes)
+
+		messages := []string{}
+		for _, t := range messageTypes {
+			messages = append(messages, fmt.Sprintf("%v=%v", t, s.MessagesSent[t]))
+		}
+
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%vs\t%v\n", s.ID, s.ConnectedAt, s.SubscriptionCount, keepalive, s.KeepaliveTimeoutSeconds, strings.Join(messages, " "))
+	}
+	return tw.Flush()
+}
+
+// Prints subscriptions as a table; Used by the interactive shell and "twitch event websocket subscriptions"
+func PrintSubscriptions(w io.Writer, subscriptions []SubscriptionPostSuccessResponseBody) error {
+	if len(subscriptions) == 0 {
+		fmt.Fprintln(w, "No subscriptions.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tVERSION\tSTATUS\tTRANSPORT\tCONDITION")
+	for _, s := range subscriptions {
+		transport := s.Transport.Method
+		switch {
+		case s.Transport.SessionID != "":
+			transport += " " + s.Transport.SessionID
+		case s.Transport.Callback != "":
+			transport += " " + s.Transport.Callback
+		case s.Transport.ConduitID != "":
+			transport += " " + s.Transport.ConduitID
+		}
+
+		condition, _ := json.Marshal(s.Condition)
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\n", s.ID, s.Type, s.Version, s.Status, transport, string(condition))
+	}
+	return tw.Flush()
+}
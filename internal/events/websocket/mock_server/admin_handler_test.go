@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+// Calls an admin handler, returning the decoded data of its response
+func adminRequest(t *testing.T, handler http.HandlerFunc, target string) []map[string]interface{} {
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, target, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("%v responded %v: %v", target, w.Code, w.Body.String())
+	}
+
+	var body struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Data
+}
+
+// Returns the keys of a JSON object
+func jsonKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestAdminListings(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 0)
+	defer f.close()
+	f.ws.StrictMode = true
+	f.connect(t, 2)
+
+	subscribed, other := f.clients[0], f.clients[1]
+	f.ws.muSubscriptions.Lock()
+	f.ws.Subscriptions[subscribed.name] = []Subscription{{
+		SubscriptionID:    "7c5d6d1a-0c9f-4d2b-8a54-5b1a3b0b1f2e",
+		Type:              "channel.follow",
+		Version:           "2",
+		Status:            STATUS_ENABLED,
+		Conditions:        models.EventsubCondition{BroadcasterUserID: "3", ModeratorUserID: "3"},
+		CreatedAt:         "2024-01-01T00:00:00Z",
+		ClientConnectedAt: "2024-01-01T00:00:00Z",
+		Cost:              0,
+	}}
+	f.ws.subscriptionsChanged()
+	f.ws.muSubscriptions.Unlock()
+
+	a.Equal(1, f.send(t, "3"))
+
+	// twitch event websocket sessions --json
+	sessions := adminRequest(t, adminSessionsHandler, "/admin/sessions")
+	a.Len(sessions, 2)
+	byID := map[string]map[string]interface{}{}
+	for _, s := range sessions {
+		a.ElementsMatch([]string{"id", "connected_at", "keepalive_enabled", "keepalive_timeout_seconds", "subscription_count", "messages_sent"}, jsonKeys(s))
+		byID[s["id"].(string)] = s
+	}
+
+	s := byID[f.ws.ServerId+"_"+subscribed.name]
+	a.NotNil(s)
+	a.Equal(float64(1), s["subscription_count"])
+	a.Equal(map[string]interface{}{"session_welcome": float64(1), "notification": float64(1)}, s["messages_sent"])
+
+	s = byID[f.ws.ServerId+"_"+other.name]
+	a.NotNil(s)
+	a.Equal(float64(0), s["subscription_count"])
+	a.Equal(map[string]interface{}{"session_welcome": float64(1)}, s["messages_sent"])
+
+	// twitch event websocket subscriptions --json
+	subscriptions := adminRequest(t, adminSubscriptionsHandler, "/admin/subscriptions?session="+f.ws.ServerId+"_"+subscribed.name)
+	a.Equal([]map[string]interface{}{{
+		"id":         "7c5d6d1a-0c9f-4d2b-8a54-5b1a3b0b1f2e",
+		"status":     STATUS_ENABLED,
+		"type":       "channel.follow",
+		"version":    "2",
+		"condition":  map[string]interface{}{"broadcaster_user_id": "3", "moderator_user_id": "3"},
+		"created_at": "2024-01-01T00:00:00Z",
+		"cost":       float64(0),
+		"transport": map[string]interface{}{
+			"method":       "websocket",
+			"session_id":   f.ws.ServerId + "_" + subscribed.name,
+			"connected_at": "2024-01-01T00:00:00Z",
+		},
+	}}, subscriptions)
+
+	a.Empty(adminRequest(t, adminSubscriptionsHandler, "/admin/subscriptions?session="+f.ws.ServerId+"_"+other.name))
+
+	w := httptest.NewRecorder()
+	adminSubscriptionsHandler(w, httptest.NewRequest(http.MethodGet, "/admin/subscriptions?session=missing", nil))
+	a.Equal(http.StatusNotFound, w.Code)
+}
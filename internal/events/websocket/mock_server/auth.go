@@ -0,0 +1,114 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+)
+
+// Validates the request's Client-Id header and Bearer token against the authorizations issued by the mock API's auth
+// endpoints, as production does. Writes an error response and returns false if either is missing or invalid
+func authenticateRequest(w http.ResponseWriter, r *http.Request) (subscriptionOwner, bool) {
+	clientID := r.Header.Get("client-id")
+	if clientID == "" {
+		handlerResponseErrorUnauthorized(w, "Client-Id header required")
+		return subscriptionOwner{}, false
+	}
+
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") || strings.TrimSpace(header[7:]) == "" {
+		handlerResponseErrorUnauthorized(w, "OAuth token is missing")
+		return subscriptionOwner{}, false
+	}
+	token := strings.TrimSpace(header[7:])
+
+	if serverManager.db == nil {
+		handlerResponseErrorInternalServerError(w, "Unable to validate OAuth token; The mock API database could not be opened.")
+		return subscriptionOwner{}, false
+	}
+
+	auth, err := serverManager.db.NewQuery(nil, 100).GetAuthorizationByToken(token)
+	if err != nil {
+		log.Printf("Failed to look up OAuth token: %v", err)
+		handlerResponseErrorInternalServerError(w, "Unable to validate OAuth token")
+		return subscriptionOwner{}, false
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339Nano, auth.ExpiresAt)
+	if auth.Token == "" || err != nil || time.Now().After(expiresAt) {
+		handlerResponseErrorUnauthorized(w, "Invalid OAuth token")
+		return subscriptionOwner{}, false
+	}
+
+	if auth.ClientID != clientID {
+		handlerResponseErrorUnauthorized(w, "Client ID and OAuth token do not match")
+		return subscriptionOwner{}, false
+	}
+
+	return subscriptionOwner{
+		ClientID: clientID,
+		Token:    token,
+		UserID:   auth.UserID,
+		Scopes:   auth.Scopes,
+		appToken: auth.UserID == "",
+	}, true
+}
+
+// WebSocket subscriptions must be created with a user token, and webhook and conduit subscriptions with an app token
+func (o subscriptionOwner) canUseTransport(method string) bool {
+	if strings.EqualFold(method, models.TransportWebSocket) {
+		return !o.appToken
+	}
+	return o.appToken
+}
+
+// Returns whether the owner is authorized to subscribe to the topic. Topics without required scopes are open to anyone.
+// Otherwise, user tokens must belong to a user in the condition and include one of the scopes, and app tokens require a
+// user in the condition to have authorized the client with one of them
+func (o subscriptionOwner) canSubscribe(topic string, version string, condition models.EventsubCondition) bool {
+	if len(types.RequiredScopes(topic, version)) == 0 {
+		return true
+	}
+	if !o.appToken {
+		for _, userID := range conditionUserIDs(condition) {
+			if userID == o.UserID {
+				return types.HasRequiredScope(topic, version, o.Scopes)
+			}
+		}
+		return false
+	}
+
+	q := serverManager.db.NewQuery(nil, 100)
+	for _, userID := range conditionUserIDs(condition) {
+		auths, err := q.GetUserAuthorizations(o.ClientID, userID)
+		if err != nil {
+			continue
+		}
+		for _, a := range auths {
+			if types.HasRequiredScope(topic, version, a.Scopes) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns the values of the condition's *user_id fields, sorted
+func conditionUserIDs(condition models.EventsubCondition) []string {
+	userIDs := []string{}
+	for field, userID := range types.ConditionFields(condition) {
+		if strings.HasSuffix(field, "user_id") && userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Strings(userIDs)
+	return userIDs
+}
@@ -0,0 +1,154 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Probabilities, from 0 to 1, of the network failures injected by the --chaos-* flags. Notification failures are rolled
+// for each notification; Keepalive, disconnect, and reconnect failures are rolled for each client every keepalive interval.
+type ChaosOptions struct {
+	Latency        float64       // Delays a notification by a random duration up to MaxLatency
+	MaxLatency     time.Duration // Maximum delay added by Latency
+	DropKeepalive  float64       // Skips a session_keepalive message
+	DelayKeepalive float64       // Delays a session_keepalive message by up to the session's keepalive timeout
+	Disconnect     float64       // Closes the TCP connection without a close frame
+	Reconnect      float64       // Sends a session_reconnect message, though the server isn't restarting
+	Duplicate      float64       // Sends a notification twice, with the same message_id
+}
+
+func (c ChaosOptions) Validate() error {
+	probabilities := map[string]float64{
+		"--chaos-latency":         c.Latency,
+		"--chaos-drop-keepalive":  c.DropKeepalive,
+		"--chaos-delay-keepalive": c.DelayKeepalive,
+		"--chaos-disconnect":      c.Disconnect,
+		"--chaos-reconnect":       c.Reconnect,
+		"--chaos-duplicate":       c.Duplicate,
+	}
+	for flag, p := range probabilities {
+		if p < 0 || p > 1 {
+			return fmt.Errorf("%v must be a probability between 0 and 1", flag)
+		}
+	}
+	if c.Latency > 0 && c.MaxLatency <= 0 {
+		return fmt.Errorf("--chaos-max-latency must be greater than 0")
+	}
+	return nil
+}
+
+func (c ChaosOptions) Enabled() bool {
+	return c.Latency > 0 || c.DropKeepalive > 0 || c.DelayKeepalive > 0 || c.Disconnect > 0 || c.Reconnect > 0 || c.Duplicate > 0
+}
+
+// Source of the chaos rolls and delays, which tests replace with a seeded one
+var chaosRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+var muChaosRand sync.Mutex
+
+// Runs f after the delay; Tests replace it to run delayed messages without waiting
+var chaosAfterFunc = func(delay time.Duration, f func()) {
+	time.AfterFunc(delay, f)
+}
+
+// Returns true with the given probability
+func chaosRoll(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+
+	muChaosRand.Lock()
+	defer muChaosRand.Unlock()
+	return chaosRand.Float64() < probability
+}
+
+// Returns a random duration between 0 and max
+func chaosDelay(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	muChaosRand.Lock()
+	defer muChaosRand.Unlock()
+	return time.Duration(chaosRand.Int63n(int64(max)))
+}
+
+// Sends a notification or revocation message to the client, adding latency or duplicating it per --chaos-latency and
+// --chaos-duplicate
+func sendWithChaos(client *Client, msg []byte, metadata MessageMetadata) error {
+	chaos := serverManager.chaos
+
+	sends := 1
+	if chaosRoll(chaos.Duplicate) {
+		sends = 2
+		log.Printf("Chaos: Duplicating notification to client [%v]", client.clientName)
+	}
+
+	if chaosRoll(chaos.Latency) {
+		delay := chaosDelay(chaos.MaxLatency)
+		log.Printf("Chaos: Delaying notification to client [%v] by %v", client.clientName, delay)
+		chaosAfterFunc(delay, func() {
+			for i := 0; i < sends; i++ {
+				client.QueueMessage(websocket.TextMessage, msg, metadata)
+			}
+		})
+		return nil
+	}
+
+	for i := 0; i < sends; i++ {
+		if err := client.QueueMessage(websocket.TextMessage, msg, metadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Applies the keepalive interval's connection failures to the client. Returns whether the regular session_keepalive
+// should be sent, and whether the keepalive loop should stop because the client was disconnected or told to reconnect.
+func (ws *WebSocketServer) applyKeepaliveChaos(client *Client, keepAliveMsg []byte, keepAliveMetadata MessageMetadata) (send bool, stop bool) {
+	chaos := serverManager.chaos
+
+	if ws.Status != 2 || serverManager.reconnectTesting {
+		return true, false
+	}
+
+	if chaosRoll(chaos.Disconnect) {
+		log.Printf("Chaos: Dropping the TCP connection of client [%v]", client.clientName)
+		ws.muClients.Lock()
+		client.CloseDirty()
+		ws.handleClientConnectionClose(client, closeNetworkError)
+		ws.muClients.Unlock()
+		return false, true
+	}
+
+	if chaosRoll(chaos.Reconnect) {
+		log.Printf("Chaos: Sending session_reconnect to client [%v]", client.clientName)
+		ws.sendSessionReconnect(client, closeClientDisconnected)
+		chaosAfterFunc(RECONNECT_GRACE_SECONDS*time.Second, func() { ws.expireSessionReconnect(client) })
+		return false, true
+	}
+
+	if chaosRoll(chaos.DropKeepalive) {
+		log.Printf("Chaos: Dropping session_keepalive to client [%v]", client.clientName)
+		keepaliveMisses.Inc("dropped")
+		return false, false
+	}
+
+	if chaosRoll(chaos.DelayKeepalive) {
+		delay := chaosDelay(time.Duration(client.keepAliveSeconds) * time.Second)
+		log.Printf("Chaos: Delaying session_keepalive to client [%v] by %v", client.clientName, delay)
+		keepaliveMisses.Inc("delayed")
+		chaosAfterFunc(delay, func() {
+			client.SendMessage(websocket.TextMessage, keepAliveMsg, keepAliveMetadata)
+		})
+		return false, false
+	}
+
+	return true, false
+}
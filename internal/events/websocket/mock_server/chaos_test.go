@@ -0,0 +1,171 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+const testChaosSeed = 1
+
+// Functions scheduled by chaos failures, which only run when the test runs them
+type chaosSchedule struct {
+	delays    []time.Duration
+	functions []func()
+}
+
+func (s *chaosSchedule) run() {
+	for _, f := range s.functions {
+		f()
+	}
+	s.delays, s.functions = nil, nil
+}
+
+// Enables chaos mode with the given options and a seeded RNG, capturing the delayed functions instead of running them
+func setChaos(t *testing.T, options ChaosOptions) *chaosSchedule {
+	serverManager.chaos = options
+
+	muChaosRand.Lock()
+	chaosRand = rand.New(rand.NewSource(testChaosSeed))
+	muChaosRand.Unlock()
+
+	s := &chaosSchedule{}
+	afterFunc := chaosAfterFunc
+	chaosAfterFunc = func(delay time.Duration, f func()) {
+		s.delays = append(s.delays, delay)
+		s.functions = append(s.functions, f)
+	}
+	t.Cleanup(func() { chaosAfterFunc = afterFunc })
+	return s
+}
+
+func getTestClient(f *fanoutServer, c *fanoutClient) *Client {
+	f.ws.muClients.Lock()
+	defer f.ws.muClients.Unlock()
+	client, _ := f.ws.Clients.Get(c.name)
+	return client
+}
+
+// Sends a channel.follow event to every session. Callers wait on f.delivered for the notifications they expect
+func forwardChaosEvent(t *testing.T, f *fanoutServer) {
+	body, _ := json.Marshal(models.EventsubResponse{
+		Subscription: models.EventsubSubscription{
+			ID:        util.RandomGUID(),
+			Status:    STATUS_ENABLED,
+			Type:      "channel.follow",
+			Version:   "2",
+			Condition: models.EventsubCondition{BroadcasterUserID: "1", ModeratorUserID: "1"},
+			Transport: models.EventsubTransport{Method: models.TransportWebSocket},
+			CreatedAt: util.GetTimestamp().Format(time.RFC3339Nano),
+		},
+	})
+
+	if ok, msg := f.ws.HandleEventSubForwarding(string(body), "", "", ""); !ok {
+		t.Fatal(msg)
+	}
+}
+
+func TestChaosNotifications(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 1)
+	defer f.close()
+	client := getTestClient(f, f.clients[0])
+
+	// Without chaos, each notification is sent once, right away
+	schedule := setChaos(t, ChaosOptions{})
+	f.delivered.Add(1)
+	forwardChaosEvent(t, f)
+	f.delivered.Wait()
+	a.Empty(schedule.delays)
+	a.Equal(1, client.MessageCounts()["notification"])
+
+	// Duplicated notifications are sent twice
+	setChaos(t, ChaosOptions{Duplicate: 1})
+	f.delivered.Add(2)
+	forwardChaosEvent(t, f)
+	f.delivered.Wait()
+	a.Equal(3, client.MessageCounts()["notification"])
+
+	// Delayed notifications are sent once the delay, drawn from the seeded RNG, passes
+	maxLatency := 500 * time.Millisecond
+	schedule = setChaos(t, ChaosOptions{Latency: 1, MaxLatency: maxLatency})
+	expected := rand.New(rand.NewSource(testChaosSeed))
+	expected.Float64()
+	delay := time.Duration(expected.Int63n(int64(maxLatency)))
+
+	forwardChaosEvent(t, f)
+	a.Equal([]time.Duration{delay}, schedule.delays)
+	a.Equal(3, client.MessageCounts()["notification"])
+
+	f.delivered.Add(1)
+	schedule.run()
+	f.delivered.Wait()
+	a.Equal(4, client.MessageCounts()["notification"])
+}
+
+func TestChaosKeepalive(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 2)
+	defer f.close()
+	client := getTestClient(f, f.clients[0])
+	keepAliveMetadata := MessageMetadata{MessageType: "session_keepalive"}
+	keepAliveMsg, _ := json.Marshal(KeepaliveMessage{Metadata: keepAliveMetadata, Payload: KeepaliveMessagePayload{}})
+
+	// Without chaos, the keepalive is sent as usual
+	schedule := setChaos(t, ChaosOptions{})
+	send, stop := f.ws.applyKeepaliveChaos(client, keepAliveMsg, keepAliveMetadata)
+	a.True(send)
+	a.False(stop)
+	a.Empty(schedule.delays)
+
+	// Dropped keepalives aren't sent
+	schedule = setChaos(t, ChaosOptions{DropKeepalive: 1})
+	send, stop = f.ws.applyKeepaliveChaos(client, keepAliveMsg, keepAliveMetadata)
+	a.False(send)
+	a.False(stop)
+	a.Empty(schedule.delays)
+	a.Zero(client.MessageCounts()["session_keepalive"])
+
+	// Delayed keepalives are sent within the session's keepalive timeout
+	schedule = setChaos(t, ChaosOptions{DelayKeepalive: 1})
+	send, stop = f.ws.applyKeepaliveChaos(client, keepAliveMsg, keepAliveMetadata)
+	a.False(send)
+	a.False(stop)
+	a.Len(schedule.delays, 1)
+	a.Less(schedule.delays[0], time.Duration(client.keepAliveSeconds)*time.Second)
+	schedule.run()
+	a.Equal(1, client.MessageCounts()["session_keepalive"])
+
+	// Reconnects send session_reconnect, and expire after the grace period
+	schedule = setChaos(t, ChaosOptions{Reconnect: 1})
+	send, stop = f.ws.applyKeepaliveChaos(client, keepAliveMsg, keepAliveMetadata)
+	a.False(send)
+	a.True(stop)
+	a.Equal([]time.Duration{RECONNECT_GRACE_SECONDS * time.Second}, schedule.delays)
+	a.Equal(1, client.MessageCounts()["session_reconnect"])
+
+	// Disconnects drop the connection, removing the session
+	other := getTestClient(f, f.clients[1])
+	setChaos(t, ChaosOptions{Disconnect: 1})
+	send, stop = f.ws.applyKeepaliveChaos(other, keepAliveMsg, keepAliveMetadata)
+	a.False(send)
+	a.True(stop)
+	a.Nil(getTestClient(f, f.clients[1]))
+
+	// No failures are injected while reconnect testing is in progress
+	setChaos(t, ChaosOptions{Disconnect: 1, Reconnect: 1, DropKeepalive: 1, DelayKeepalive: 1})
+	serverManager.reconnectTesting = true
+	send, stop = f.ws.applyKeepaliveChaos(client, keepAliveMsg, keepAliveMetadata)
+	serverManager.reconnectTesting = false
+	a.True(send)
+	a.False(stop)
+}
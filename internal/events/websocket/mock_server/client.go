@@ -1,12 +1,31 @@
 package mock_server
 
 import (
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/twitchdev/twitch-cli/internal/events/websocket/transcript"
 )
 
+// Messages that can be queued for a client before it's considered too slow to keep up, and disconnected
+const CLIENT_SEND_QUEUE_SIZE = 1024
+
+// Time a write to a client can block before the connection is considered broken
+const CLIENT_WRITE_TIMEOUT = 10 * time.Second
+
+var errClientClosed = errors.New("the client's connection is closed")
+var errSendQueueFull = errors.New("the client's send queue is full")
+
+// A message waiting in a client's send queue
+type queuedMessage struct {
+	messageType int
+	data        []byte
+	metadata    MessageMetadata // Metadata of text messages, which they're counted by
+	done        chan error      // Receives the result of the write; nil if nothing waits for it
+}
+
 type Client struct {
 	clientName           string // Unique name for the client. Not the Client ID.
 	conn                 *websocket.Conn
@@ -14,6 +33,18 @@ type Client struct {
 	ConnectedAtTimestamp string // RFC3339Nano timestamp indicating when the client connected to the server
 	connectionUrl        string
 	KeepAliveEnabled     bool
+	messageCounts        map[string]int     // Text messages sent to the client, by metadata.message_type
+	transcript           *transcript.Writer // Records the session's frames with --record-dir; nil otherwise
+
+	reconnecting     bool          // Was sent a session_reconnect message
+	reconnected      bool          // Reconnected on a new connection, though this one is still open. Events are only sent to the new one
+	closeOnReconnect *CloseMessage // Closes this connection once the client reconnects; nil leaves it open until the client closes it or the grace period expires
+
+	sendQueue       chan queuedMessage // Messages written to the connection in order by the client's writer goroutine; nil if it wasn't started
+	writerDone      chan struct{}      // Closed when the writer goroutine stops
+	stopWriterOnce  sync.Once
+	queueFullOnce   sync.Once
+	onSendQueueFull func() // Called once, in its own goroutine, when a message can't be queued because the queue is full
 
 	mustSubscribeTimer *time.Timer
 	keepAliveChanOpen  bool
@@ -25,20 +56,156 @@ type Client struct {
 	pingTimer          *time.Ticker
 }
 
-func (c *Client) SendMessage(messageType int, data []byte) error {
+// Starts the goroutine writing the client's queued messages to the connection, which runs until stopWriter is called
+func (c *Client) startWriter() {
+	c.sendQueue = make(chan queuedMessage, CLIENT_SEND_QUEUE_SIZE)
+	c.writerDone = make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-c.writerDone:
+				return
+			case msg := <-c.sendQueue:
+				err := c.write(msg.messageType, msg.data, msg.metadata)
+				if msg.done != nil {
+					msg.done <- err
+				}
+			}
+		}
+	}()
+}
+
+// Stops the writer goroutine. Messages still in the queue are dropped.
+func (c *Client) stopWriter() {
+	c.stopWriterOnce.Do(func() {
+		if c.writerDone != nil {
+			close(c.writerDone)
+		}
+	})
+}
+
+// Queues a message and waits for it to be written, after the messages queued before it. metadata is the metadata the
+// message was built with, which it's counted by; Frames other than EventSub messages pass the zero value.
+func (c *Client) SendMessage(messageType int, data []byte, metadata MessageMetadata) error {
+	if c.sendQueue == nil {
+		return c.write(messageType, data, metadata)
+	}
+
+	done := make(chan error, 1)
+	select {
+	case c.sendQueue <- queuedMessage{messageType: messageType, data: data, metadata: metadata, done: done}:
+	case <-c.writerDone:
+		return errClientClosed
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-c.writerDone:
+		return errClientClosed
+	}
+}
+
+// Queues a message without waiting for it to be written, so events can be sent to many clients without waiting on each.
+// If the queue is full, the message is dropped, and onSendQueueFull is called since the client isn't keeping up.
+func (c *Client) QueueMessage(messageType int, data []byte, metadata MessageMetadata) error {
+	if c.sendQueue == nil {
+		return c.write(messageType, data, metadata)
+	}
+
+	select {
+	case <-c.writerDone:
+		return errClientClosed
+	default:
+	}
+
+	select {
+	case c.sendQueue <- queuedMessage{messageType: messageType, data: data, metadata: metadata}:
+		return nil
+	default:
+		if c.onSendQueueFull != nil {
+			c.queueFullOnce.Do(func() { go c.onSendQueueFull() })
+		}
+		return errSendQueueFull
+	}
+}
+
+// Writes a message to the connection, recording it in the transcript and message counts
+func (c *Client) write(messageType int, data []byte, metadata MessageMetadata) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	return c.conn.WriteMessage(messageType, data)
+
+	c.conn.SetWriteDeadline(time.Now().Add(CLIENT_WRITE_TIMEOUT))
+	err := c.conn.WriteMessage(messageType, data)
+	if err == nil {
+		c.recordSent(messageType, data)
+	}
+	if err == nil && metadata.MessageType != "" {
+		if c.messageCounts == nil {
+			c.messageCounts = make(map[string]int)
+		}
+		c.messageCounts[metadata.MessageType]++
+		if metadata.MessageType == "notification" {
+			notificationsSent.Inc(metadata.SubscriptionType, metadata.SubscriptionVersion)
+		}
+	}
+	return err
+}
+
+// Returns the number of messages sent to the client, by message type
+func (c *Client) MessageCounts() map[string]int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	counts := make(map[string]int, len(c.messageCounts))
+	for t, n := range c.messageCounts {
+		counts[t] = n
+	}
+	return counts
 }
 
 func (c *Client) CloseWithReason(reason *CloseMessage) {
-	c.conn.WriteControl(
+	err := c.conn.WriteControl(
 		websocket.CloseMessage,
 		websocket.FormatCloseMessage(reason.code, reason.message),
 		time.Now().Add(2*time.Second),
 	)
+	if err == nil {
+		c.transcript.Record(transcript.Entry{
+			Direction:   transcript.DirectionSent,
+			Frame:       transcript.FrameClose,
+			CloseCode:   reason.code,
+			CloseReason: reason.message,
+		})
+	}
 }
 
 func (c *Client) CloseDirty() {
 	c.conn.Close()
+	c.transcript.Record(transcript.Entry{Direction: transcript.DirectionSent, Frame: transcript.FrameDisconnect})
+}
+
+// Records a frame sent to the client in its transcript
+func (c *Client) recordSent(messageType int, data []byte) {
+	switch messageType {
+	case websocket.TextMessage:
+		c.transcript.RecordMessage(transcript.DirectionSent, transcript.FrameText, data)
+	case websocket.BinaryMessage:
+		c.transcript.RecordMessage(transcript.DirectionSent, transcript.FrameBinary, data)
+	case websocket.PingMessage:
+		c.transcript.Record(transcript.Entry{Direction: transcript.DirectionSent, Frame: transcript.FramePing})
+	}
+}
+
+// Records a frame received from the client in its transcript
+func (c *Client) recordReceived(messageType int, data []byte) {
+	switch messageType {
+	case websocket.TextMessage:
+		c.transcript.RecordMessage(transcript.DirectionReceived, transcript.FrameText, data)
+	case websocket.BinaryMessage:
+		c.transcript.RecordMessage(transcript.DirectionReceived, transcript.FrameBinary, data)
+	case websocket.PongMessage:
+		c.transcript.Record(transcript.Entry{Direction: transcript.DirectionReceived, Frame: transcript.FramePong})
+	}
 }
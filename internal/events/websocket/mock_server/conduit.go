@@ -0,0 +1,870 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+const (
+	TRANSPORT_CONDUIT = "conduit"
+
+	CONDUIT_MAX_PER_CLIENT = 5
+	CONDUIT_MAX_SHARDS     = 20000
+
+	// Shard status used when a session doesn't reconnect to the new server during reconnect testing
+	STATUS_WEBSOCKET_FAILED_TO_RECONNECT = "websocket_failed_to_reconnect"
+)
+
+type Conduit struct {
+	ID        string  // Random GUID for the conduit
+	ClientID  string  // Client ID included in headers
+	Shards    []Shard // Shards by their ID, which is their index
+	nextShard int     // Index of the shard the next event is sent to, if it's enabled
+}
+
+type Shard struct {
+	ID     string // Index of the shard in the conduit
+	Status string // Status of the shard; Only enabled shards receive events
+
+	Method         string // websocket or webhook. Empty until the shard is assigned a transport
+	SessionID      string // WebSocket only; Session events are sent to
+	ConnectedAt    string // WebSocket only; Time the session connected
+	DisconnectedAt string // WebSocket only; Time the session disconnected
+	Callback       string // Webhook only; URL events are sent to
+	Secret         string // Webhook only; Secret used to sign events
+}
+
+// Request - POST /eventsub/conduits
+// Request - PATCH /eventsub/conduits
+type ConduitRequest struct {
+	ID         string `json:"id"`
+	ShardCount int    `json:"shard_count"`
+}
+
+// Response (Success) - GET/POST/PATCH /eventsub/conduits
+type ConduitResponse struct {
+	Data []ConduitResponseBody `json:"data"`
+}
+
+type ConduitResponseBody struct {
+	ID         string `json:"id"`
+	ShardCount int    `json:"shard_count"`
+}
+
+// Request - PATCH /eventsub/conduits/shards
+type ShardsPatchRequest struct {
+	ConduitID string                    `json:"conduit_id"`
+	Shards    []ShardsPatchRequestShard `json:"shards"`
+}
+
+type ShardsPatchRequestShard struct {
+	ID        string                           `json:"id"`
+	Transport SubscriptionPostRequestTransport `json:"transport"`
+}
+
+// Response (Success) - GET /eventsub/conduits/shards
+type ShardsGetResponse struct {
+	Data       []ShardResponseBody `json:"data"`
+	Pagination EmptyStruct         `json:"pagination"`
+}
+
+// Response (Success) - PATCH /eventsub/conduits/shards
+type ShardsPatchResponse struct {
+	Data   []ShardResponseBody `json:"data"`
+	Errors []ShardError        `json:"errors"`
+}
+
+type ShardResponseBody struct {
+	ID        string                `json:"id"`
+	Status    string                `json:"status"`
+	Transport SubscriptionTransport `json:"transport"`
+}
+
+type ShardError struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+func (s Shard) toResponseBody() ShardResponseBody {
+	body := ShardResponseBody{
+		ID:     s.ID,
+		Status: s.Status,
+		Transport: SubscriptionTransport{
+			Method: s.Method,
+		},
+	}
+
+	if s.Method == models.TransportWebhook {
+		body.Transport.Callback = s.Callback
+	} else {
+		body.Transport.SessionID = s.SessionID
+		body.Transport.ConnectedAt = s.ConnectedAt
+		body.Transport.DisconnectedAt = s.DisconnectedAt
+	}
+
+	return body
+}
+
+// Returns the next enabled shard, so events are spread evenly across the conduit's enabled shards
+func (c *Conduit) nextEnabledShard() (Shard, bool) {
+	for i := 0; i < len(c.Shards); i++ {
+		idx := (c.nextShard + i) % len(c.Shards)
+		if c.Shards[idx].Status == STATUS_ENABLED {
+			c.nextShard = idx + 1
+			return c.Shards[idx], true
+		}
+	}
+	return Shard{}, false
+}
+
+// Grows or shrinks the conduit to the given number of shards. Shards that are added haven't been assigned a transport.
+func (c *Conduit) resize(shardCount int) {
+	if shardCount < len(c.Shards) {
+		c.Shards = c.Shards[:shardCount]
+		return
+	}
+
+	for i := len(c.Shards); i < shardCount; i++ {
+		c.Shards = append(c.Shards, Shard{
+			ID:     strconv.Itoa(i),
+			Status: STATUS_WEBSOCKET_DISCONNECTED, // Unassigned shards are reported as disconnected
+		})
+	}
+}
+
+// Returns the conduit with the given ID if it belongs to the client. Must be called while holding muConduits.
+func (sm *ServerManager) getConduit(clientID string, conduitID string) (*Conduit, bool) {
+	c, ok := sm.getConduitByID(conduitID)
+	if !ok || c.ClientID != clientID {
+		return nil, false
+	}
+	return c, true
+}
+
+// Returns the conduit with the given ID, whichever client it belongs to. Must be called while holding muConduits.
+func (sm *ServerManager) getConduitByID(conduitID string) (*Conduit, bool) {
+	for _, c := range sm.conduits {
+		if c.ID == conduitID {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Returns whether the session is assigned to any conduit shard
+func (sm *ServerManager) isConduitShardSession(sessionID string) bool {
+	sm.muConduits.Lock()
+	defer sm.muConduits.Unlock()
+
+	for _, c := range sm.conduits {
+		for _, s := range c.Shards {
+			if s.Method == models.TransportWebSocket && s.SessionID == sessionID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func conduitPageHandler(w http.ResponseWriter, r *http.Request) {
+	method := strings.ToUpper(r.Method)
+
+	if method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Accept-Language, Authorization, Client-Id, Twitch-Api-Token, X-Forwarded-Proto, X-Requested-With, X-Csrf-Token, Content-Type, X-Device-Id, X-Twitch-Vhscf, X-Forwarded-Ip")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("ratelimit-limit", "800")
+	w.Header().Set("ratelimit-remaining", "799")
+	w.Header().Set("ratelimit-reset", fmt.Sprintf("%d", time.Now().Unix()+1)) // 1 second from now
+
+	owner, ok := authenticateRequest(w, r)
+	if !ok {
+		return
+	}
+	if !owner.appToken {
+		handlerResponseErrorUnauthorized(w, "An app access token is required")
+		return
+	}
+	clientID := owner.ClientID
+
+	switch method {
+	case "GET":
+		conduitPageHandlerGet(w, clientID)
+	case "POST":
+		conduitPageHandlerPost(w, r, clientID)
+	case "PATCH":
+		conduitPageHandlerPatch(w, r, clientID)
+	case "DELETE":
+		conduitPageHandlerDelete(w, r, clientID)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func conduitPageHandlerGet(w http.ResponseWriter, clientID string) {
+	serverManager.muConduits.Lock()
+	conduits := []ConduitResponseBody{}
+	for _, c := range serverManager.conduits {
+		if c.ClientID == clientID {
+			conduits = append(conduits, ConduitResponseBody{ID: c.ID, ShardCount: len(c.Shards)})
+		}
+	}
+	serverManager.muConduits.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&ConduitResponse{Data: conduits})
+}
+
+func conduitPageHandlerPost(w http.ResponseWriter, r *http.Request, clientID string) {
+	var body ConduitRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		handlerResponseErrorBadRequest(w, "error validating json")
+		return
+	}
+	if body.ShardCount < 1 || body.ShardCount > CONDUIT_MAX_SHARDS {
+		handlerResponseErrorBadRequest(w, fmt.Sprintf("The value specified in the 'shard_count' field is not valid; Must be between 1-%v", CONDUIT_MAX_SHARDS))
+		return
+	}
+
+	serverManager.muConduits.Lock()
+
+	owned := 0
+	for _, c := range serverManager.conduits {
+		if c.ClientID == clientID {
+			owned++
+		}
+	}
+	if owned >= CONDUIT_MAX_PER_CLIENT {
+		serverManager.muConduits.Unlock()
+		handlerResponseErrorBadRequest(w, fmt.Sprintf("You may only create %v conduits per Client ID", CONDUIT_MAX_PER_CLIENT))
+		return
+	}
+
+	conduit := &Conduit{
+		ID:       util.RandomGUID(),
+		ClientID: clientID,
+		Shards:   []Shard{},
+	}
+	conduit.resize(body.ShardCount)
+	serverManager.conduits = append(serverManager.conduits, conduit)
+
+	serverManager.muConduits.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&ConduitResponse{
+		Data: []ConduitResponseBody{{ID: conduit.ID, ShardCount: body.ShardCount}},
+	})
+
+	if serverManager.debugEnabled {
+		log.Printf("Client ID [%v] created conduit [%v] with [%v] shards", clientID, conduit.ID, body.ShardCount)
+	}
+}
+
+func conduitPageHandlerPatch(w http.ResponseWriter, r *http.Request, clientID string) {
+	var body ConduitRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		handlerResponseErrorBadRequest(w, "error validating json")
+		return
+	}
+	if body.ShardCount < 1 || body.ShardCount > CONDUIT_MAX_SHARDS {
+		handlerResponseErrorBadRequest(w, fmt.Sprintf("The value specified in the 'shard_count' field is not valid; Must be between 1-%v", CONDUIT_MAX_SHARDS))
+		return
+	}
+
+	serverManager.muConduits.Lock()
+	conduit, ok := serverManager.getConduit(clientID, body.ID)
+	if !ok {
+		serverManager.muConduits.Unlock()
+		handlerResponseErrorNotFound(w, "Conduit not found")
+		return
+	}
+	conduit.resize(body.ShardCount)
+	serverManager.muConduits.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&ConduitResponse{
+		Data: []ConduitResponseBody{{ID: body.ID, ShardCount: body.ShardCount}},
+	})
+}
+
+func conduitPageHandlerDelete(w http.ResponseWriter, r *http.Request, clientID string) {
+	conduitID := r.URL.Query().Get("id")
+	if conduitID == "" {
+		handlerResponseErrorBadRequest(w, "The id query parameter is required")
+		return
+	}
+
+	serverManager.muConduits.Lock()
+	defer serverManager.muConduits.Unlock()
+
+	for i, c := range serverManager.conduits {
+		if c.ID == conduitID && c.ClientID == clientID {
+			serverManager.conduits = append(serverManager.conduits[:i], serverManager.conduits[i+1:]...)
+
+			// Subscriptions using the conduit are deleted along with it
+			subs := []Subscription{}
+			for _, s := range serverManager.conduitSubscriptions {
+				if s.ConduitID != conduitID {
+					subs = append(subs, s)
+				}
+			}
+			serverManager.conduitSubscriptions = subs
+
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	handlerResponseErrorNotFound(w, "Conduit not found")
+}
+
+func conduitShardsPageHandler(w http.ResponseWriter, r *http.Request) {
+	method := strings.ToUpper(r.Method)
+
+	if method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Accept-Language, Authorization, Client-Id, Twitch-Api-Token, X-Forwarded-Proto, X-Requested-With, X-Csrf-Token, Content-Type, X-Device-Id, X-Twitch-Vhscf, X-Forwarded-Ip")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PATCH")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("ratelimit-limit", "800")
+	w.Header().Set("ratelimit-remaining", "799")
+	w.Header().Set("ratelimit-reset", fmt.Sprintf("%d", time.Now().Unix()+1)) // 1 second from now
+
+	owner, ok := authenticateRequest(w, r)
+	if !ok {
+		return
+	}
+	if !owner.appToken {
+		handlerResponseErrorUnauthorized(w, "An app access token is required")
+		return
+	}
+	clientID := owner.ClientID
+
+	switch method {
+	case "GET":
+		conduitShardsPageHandlerGet(w, r, clientID)
+	case "PATCH":
+		conduitShardsPageHandlerPatch(w, r, clientID)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func conduitShardsPageHandlerGet(w http.ResponseWriter, r *http.Request, clientID string) {
+	conduitID := r.URL.Query().Get("conduit_id")
+	status := r.URL.Query().Get("status")
+	if conduitID == "" {
+		handlerResponseErrorBadRequest(w, "The conduit_id query parameter is required")
+		return
+	}
+
+	serverManager.muConduits.Lock()
+	conduit, ok := serverManager.getConduit(clientID, conduitID)
+	if !ok {
+		serverManager.muConduits.Unlock()
+		handlerResponseErrorNotFound(w, "Conduit not found")
+		return
+	}
+
+	shards := []ShardResponseBody{}
+	for _, s := range conduit.Shards {
+		if status == "" || s.Status == status {
+			shards = append(shards, s.toResponseBody())
+		}
+	}
+	serverManager.muConduits.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&ShardsGetResponse{
+		Data:       shards,
+		Pagination: EmptyStruct{},
+	})
+}
+
+func conduitShardsPageHandlerPatch(w http.ResponseWriter, r *http.Request, clientID string) {
+	var body ShardsPatchRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		handlerResponseErrorBadRequest(w, "error validating json")
+		return
+	}
+	if body.ConduitID == "" {
+		handlerResponseErrorBadRequest(w, "The value specified in the 'conduit_id' field is not valid")
+		return
+	}
+
+	response := ShardsPatchResponse{
+		Data:   []ShardResponseBody{},
+		Errors: []ShardError{},
+	}
+	pendingWebhooks := []Shard{}
+
+	serverManager.muConduits.Lock()
+
+	conduit, ok := serverManager.getConduit(clientID, body.ConduitID)
+	if !ok {
+		serverManager.muConduits.Unlock()
+		handlerResponseErrorNotFound(w, "Conduit not found")
+		return
+	}
+
+	for _, update := range body.Shards {
+		idx, err := strconv.Atoi(update.ID)
+		if err != nil || idx < 0 || idx >= len(conduit.Shards) {
+			response.Errors = append(response.Errors, ShardError{ID: update.ID, Message: "The shard ID is outside of the conduit's range", Code: "invalid_shard_id"})
+			continue
+		}
+
+		shard := Shard{ID: update.ID}
+		switch strings.ToLower(update.Transport.Method) {
+		case models.TransportWebSocket:
+			_, client, ok := getClientBySession(update.Transport.SessionID)
+			if !ok {
+				response.Errors = append(response.Errors, ShardError{ID: update.ID, Message: "The websocket session is not connected", Code: "websocket_session_not_found"})
+				continue
+			}
+
+			shard.Method = models.TransportWebSocket
+			shard.Status = STATUS_ENABLED
+			shard.SessionID = update.Transport.SessionID
+			shard.ConnectedAt = client.ConnectedAtTimestamp
+
+		case models.TransportWebhook:
+			if msg := validateWebhookTransport(update.Transport); msg != "" {
+				response.Errors = append(response.Errors, ShardError{ID: update.ID, Message: msg, Code: "invalid_transport"})
+				continue
+			}
+
+			shard.Method = models.TransportWebhook
+			shard.Status = STATUS_WEBHOOK_CALLBACK_VERIFICATION_PENDING // Enabled once the callback responds to the challenge
+			shard.Callback = update.Transport.Callback
+			shard.Secret = update.Transport.Secret
+			pendingWebhooks = append(pendingWebhooks, shard)
+
+		default:
+			response.Errors = append(response.Errors, ShardError{ID: update.ID, Message: "The value specified in the 'method' field is not valid", Code: "invalid_transport"})
+			continue
+		}
+
+		conduit.Shards[idx] = shard
+		response.Data = append(response.Data, shard.toResponseBody())
+	}
+
+	serverManager.muConduits.Unlock()
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(&response)
+
+	for _, shard := range pendingWebhooks {
+		go serverManager.verifyWebhookShard(body.ConduitID, shard)
+	}
+
+	if serverManager.debugEnabled {
+		log.Printf("Client ID [%v] updated [%v] shards of conduit [%v]", clientID, len(response.Data), body.ConduitID)
+	}
+}
+
+// Sends the webhook_callback_verification challenge to a shard's callback, enabling the shard only if the callback
+// responds with the challenge
+func (sm *ServerManager) verifyWebhookShard(conduitID string, shard Shard) {
+	status := STATUS_ENABLED
+	err := sendWebhookChallenge(shard.Callback, shard.Secret, models.EventsubSubscription{
+		ID:     conduitID,
+		Status: STATUS_WEBHOOK_CALLBACK_VERIFICATION_PENDING,
+		Transport: models.EventsubTransport{
+			Method:   models.TransportWebhook,
+			Callback: shard.Callback,
+		},
+		CreatedAt: util.GetTimestamp().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		status = STATUS_WEBHOOK_CALLBACK_VERIFICATION_FAILED
+		log.Printf("Shard [%v] of conduit [%v] failed verification: %v", shard.ID, conduitID, err)
+	} else {
+		log.Printf("Shard [%v] of conduit [%v] verified; Events will be sent to %v", shard.ID, conduitID, shard.Callback)
+	}
+
+	sm.muConduits.Lock()
+	defer sm.muConduits.Unlock()
+
+	conduit, ok := sm.getConduitByID(conduitID)
+	if !ok {
+		return
+	}
+	for i, s := range conduit.Shards {
+		// The shard may have been reassigned while the challenge was sent
+		if s.ID == shard.ID && s.Method == models.TransportWebhook && s.Callback == shard.Callback {
+			conduit.Shards[i].Status = status
+		}
+	}
+}
+
+func subscriptionPageHandlerPostConduit(w http.ResponseWriter, r *http.Request, body SubscriptionPostRequest, owner subscriptionOwner) {
+	clientID := owner.ClientID
+
+	subscription := Subscription{
+		SubscriptionID: util.RandomGUID(),
+		ClientID:       clientID,
+		Type:           body.Type,
+		Version:        body.Version,
+		CreatedAt:      time.Now().UTC().Format(time.RFC3339Nano),
+		Status:         STATUS_ENABLED,
+		Conditions:     body.Condition,
+		ConduitID:      body.Transport.ConduitID,
+		Token:          owner.Token,
+		UserID:         owner.UserID,
+	}
+	subscription.Cost = subscriptionCost(clientID, subscription)
+
+	// The maximum total cost is shared with webhook subscriptions, so both are locked while it's checked
+	serverManager.muWebhookSubscriptions.Lock()
+	serverManager.muConduits.Lock()
+
+	if _, ok := serverManager.getConduit(clientID, body.Transport.ConduitID); !ok {
+		serverManager.muConduits.Unlock()
+		serverManager.muWebhookSubscriptions.Unlock()
+		handlerResponseErrorBadRequest(w, "The value specified in the 'conduit_id' field is not valid")
+		return
+	}
+
+	for _, s := range serverManager.conduitSubscriptions {
+		if s.ClientID == clientID && s.Type == body.Type && s.Version == body.Version && s.Conditions == body.Condition && s.ConduitID == body.Transport.ConduitID {
+			serverManager.muConduits.Unlock()
+			serverManager.muWebhookSubscriptions.Unlock()
+			handlerResponseErrorConflict(w, "Subscription by the specified type, version, condition, and conduit combination for the specified Client ID already exists")
+			return
+		}
+	}
+
+	total, totalCost := serverManager.webhookCostTotals(clientID)
+	if totalCost+subscription.Cost > WEBHOOK_MAX_TOTAL_COST {
+		serverManager.muConduits.Unlock()
+		serverManager.muWebhookSubscriptions.Unlock()
+		handlerResponseErrorTooManyRequests(w, fmt.Sprintf("The subscription exceeds the maximum total cost of %v", WEBHOOK_MAX_TOTAL_COST))
+		return
+	}
+
+	serverManager.conduitSubscriptions = append(serverManager.conduitSubscriptions, subscription)
+
+	serverManager.muConduits.Unlock()
+	serverManager.muWebhookSubscriptions.Unlock()
+
+	// Return 202 status code and response body
+	w.WriteHeader(http.StatusAccepted)
+
+	json.NewEncoder(w).Encode(&SubscriptionPostSuccessResponse{
+		Data: []SubscriptionPostSuccessResponseBody{
+			{
+				ID:        subscription.SubscriptionID,
+				Status:    subscription.Status,
+				Type:      subscription.Type,
+				Version:   subscription.Version,
+				Condition: subscription.Conditions,
+				CreatedAt: subscription.CreatedAt,
+				Transport: SubscriptionTransport{
+					Method:    TRANSPORT_CONDUIT,
+					ConduitID: subscription.ConduitID,
+				},
+				Cost: subscription.Cost,
+			},
+		},
+		Total:        total + 1,
+		MaxTotalCost: WEBHOOK_MAX_TOTAL_COST,
+		TotalCost:    totalCost + subscription.Cost,
+	})
+
+	if serverManager.debugEnabled {
+		log.Printf(
+			"Client ID [%v] created conduit subscription [%v/%v] at subscription ID [%v] for conduit [%v]",
+			clientID,
+			subscription.Type,
+			subscription.Version,
+			subscription.SubscriptionID,
+			subscription.ConduitID,
+		)
+	}
+}
+
+type conduitDelivery struct {
+	subscription Subscription
+	shard        Shard
+}
+
+// Sends an event to one enabled shard of each conduit with a subscription matching the event's topic, version, and
+// condition. Returns the number of subscriptions the event was sent to.
+func (sm *ServerManager) HandleConduitForwarding(eventsubBody string, messageID string, messageTimestamp string) (int, error) {
+	eventObj := models.EventsubResponse{}
+	err := json.Unmarshal([]byte(eventsubBody), &eventObj)
+	if err != nil {
+		return 0, fmt.Errorf("Error reading JSON forwarded from EventSub: %v", err.Error())
+	}
+
+	messageType := trigger.EventSubMessageTypeNotification
+	if eventObj.Subscription.Status != "" && eventObj.Subscription.Status != STATUS_ENABLED {
+		messageType = trigger.EventSubMessageTypeRevocation
+	}
+
+	if messageTimestamp == "" {
+		messageTimestamp = util.GetTimestamp().Format(time.RFC3339Nano)
+	}
+
+	// Shards are picked while holding the lock, but events are sent after it's released
+	sm.muConduits.Lock()
+	deliveries := []conduitDelivery{}
+	for i, sub := range sm.conduitSubscriptions {
+		if sub.Type != eventObj.Subscription.Type || sub.Version != eventObj.Subscription.Version || sub.Status != STATUS_ENABLED ||
+			!types.ConditionMatches(sub.Conditions, eventObj.Subscription.Condition) {
+			continue
+		}
+
+		conduit, ok := sm.getConduit(sub.ClientID, sub.ConduitID)
+		if !ok {
+			continue
+		}
+
+		// Revocations are delivered like any other message, through one of the conduit's shards
+		shard, ok := conduit.nextEnabledShard()
+		if !ok {
+			log.Printf("Could not send [%v / %v] to conduit subscription [%v]: Conduit [%v] has no enabled shards", sub.Type, sub.Version, sub.SubscriptionID, sub.ConduitID)
+			continue
+		}
+
+		if messageType == trigger.EventSubMessageTypeRevocation {
+			sm.conduitSubscriptions[i].Status = eventObj.Subscription.Status
+			tNow := util.GetTimestamp()
+			sm.conduitSubscriptions[i].DisabledAt = &tNow
+		}
+
+		deliveries = append(deliveries, conduitDelivery{subscription: sub, shard: shard})
+	}
+	sm.muConduits.Unlock()
+
+	sent := 0
+	for _, d := range deliveries {
+		// The payload describes the subscription it's sent to
+		subEventObj := eventObj
+		subEventObj.Subscription = d.subscription.toEventsubSubscription()
+		if messageType == trigger.EventSubMessageTypeRevocation {
+			subEventObj.Subscription.Status = eventObj.Subscription.Status
+		}
+
+		id := messageID
+		if id == "" {
+			id = util.RandomGUID()
+		}
+
+		if d.shard.Method == models.TransportWebhook {
+			body, _ := json.Marshal(subEventObj)
+			resp, err := trigger.ForwardEvent(trigger.ForwardParamters{
+				ID:                  id,
+				ForwardAddress:      d.shard.Callback,
+				JSON:                body,
+				Transport:           models.TransportWebhook,
+				Timestamp:           messageTimestamp,
+				Secret:              d.shard.Secret,
+				Event:               d.subscription.Type,
+				Type:                messageType,
+				SubscriptionVersion: d.subscription.Version,
+			})
+			if err != nil {
+				log.Printf("Failed to send [%v / %v] to shard [%v] of conduit [%v]: %v", d.subscription.Type, d.subscription.Version, d.shard.ID, d.subscription.ConduitID, err)
+				continue
+			}
+			resp.Body.Close()
+		} else {
+			_, client, ok := getClientBySession(d.shard.SessionID)
+			if !ok {
+				log.Printf("Failed to send [%v / %v] to shard [%v] of conduit [%v]: Session [%v] is not connected", d.subscription.Type, d.subscription.Version, d.shard.ID, d.subscription.ConduitID, d.shard.SessionID)
+				continue
+			}
+
+			err := sendNotification(client, messageType, id, messageTimestamp, subEventObj)
+			if err != nil {
+				log.Printf("Failed to send [%v / %v] to shard [%v] of conduit [%v]: %v", d.subscription.Type, d.subscription.Version, d.shard.ID, d.subscription.ConduitID, err)
+				continue
+			}
+		}
+
+		log.Printf("Sent [%v / %v] to shard [%v] of conduit [%v]", d.subscription.Type, d.subscription.Version, d.shard.ID, d.subscription.ConduitID)
+		sent++
+	}
+
+	return sent, nil
+}
+
+// Points shards assigned to a session at the new session it reconnected as, so they stay enabled through reconnects
+func (sm *ServerManager) moveConduitShards(oldSessionID string, newSessionID string, connectedAt string) {
+	sm.muConduits.Lock()
+	defer sm.muConduits.Unlock()
+
+	for _, c := range sm.conduits {
+		for i, s := range c.Shards {
+			if s.Method == models.TransportWebSocket && s.SessionID == oldSessionID {
+				c.Shards[i].SessionID = newSessionID
+				c.Shards[i].ConnectedAt = connectedAt
+			}
+		}
+	}
+}
+
+// Disables the enabled shards assigned to a session that disconnected, and sends conduit.shard.disabled to the owners
+// of their conduits
+func (sm *ServerManager) disableConduitShards(sessionID string, status string) {
+	disconnectedAt := util.GetTimestamp().Format(time.RFC3339Nano)
+
+	disabled := []models.ConduitShardDisabledEventSubResponse{}
+
+	sm.muConduits.Lock()
+	for _, c := range sm.conduits {
+		for i, s := range c.Shards {
+			if s.Method != models.TransportWebSocket || s.SessionID != sessionID || s.Status != STATUS_ENABLED {
+				continue
+			}
+
+			c.Shards[i].Status = status
+			c.Shards[i].DisconnectedAt = disconnectedAt
+
+			log.Printf("Shard [%v] of conduit [%v] disabled with status [%v]", s.ID, c.ID, status)
+
+			disabled = append(disabled, models.ConduitShardDisabledEventSubResponse{
+				Subscription: models.EventsubSubscription{
+					Type:    "conduit.shard.disabled",
+					Version: "1",
+					Status:  STATUS_ENABLED,
+					Condition: models.EventsubCondition{
+						ClientID: c.ClientID,
+					},
+				},
+				Event: &models.ConduitShardDisabledEvent{
+					ConduitID: c.ID,
+					ShardID:   s.ID,
+					Status:    status,
+					Transport: models.ConduitShardDisabledEventTransport{
+						Method:         models.TransportWebSocket,
+						SessionID:      sessionID,
+						ConnectedAt:    s.ConnectedAt,
+						DisconnectedAt: disconnectedAt,
+					},
+				},
+			})
+		}
+	}
+	sm.muConduits.Unlock()
+
+	for _, event := range disabled {
+		sm.emitServerEvent(event)
+	}
+}
+
+// Sends an event generated by the server itself to every enabled subscription matching it, over every transport. Unlike
+// triggered events, these are never sent to sessions that aren't subscribed to the event.
+func (sm *ServerManager) emitServerEvent(event interface{}) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error building server event JSON: %v", err)
+		return
+	}
+
+	eventObj := models.EventsubResponse{}
+	json.Unmarshal(body, &eventObj)
+
+	server, ok := sm.serverList.Get(sm.primaryServer)
+	if ok {
+		matches := server.getSubscriptionIndex().match(eventObj.Subscription)
+
+		server.muClients.Lock()
+		clients := server.Clients.All()
+		server.muClients.Unlock()
+
+		for _, client := range clients {
+			match, ok := matches[client.clientName]
+			if !ok || client.reconnected {
+				continue
+			}
+			subscription := match.subscription
+
+			clientEventObj := eventObj
+			clientEventObj.Subscription = subscription.toEventsubSubscription()
+			clientEventObj.Subscription.Transport = models.EventsubTransport{
+				Method:    models.TransportWebSocket,
+				SessionID: fmt.Sprintf("%v_%v", server.ServerId, client.clientName),
+			}
+
+			err := sendNotification(client, trigger.EventSubMessageTypeNotification, util.RandomGUID(), util.GetTimestamp().Format(time.RFC3339Nano), clientEventObj)
+			if err == nil {
+				log.Printf("Sent [%v / %v] to client [%v]", eventObj.Subscription.Type, eventObj.Subscription.Version, client.clientName)
+			}
+		}
+	}
+
+	sm.HandleWebhookForwarding(string(body), "", "")
+	if _, err := sm.HandleConduitForwarding(string(body), "", ""); err != nil {
+		log.Printf("Error sending server event to conduits: %v", err)
+	}
+}
+
+// Returns the server and client of a session ID given in its welcome message
+func getClientBySession(sessionID string) (*WebSocketServer, *Client, bool) {
+	if !sessionRegex.MatchString(sessionID) {
+		return nil, nil, false
+	}
+
+	sessionRegexExec := sessionRegex.FindAllStringSubmatch(sessionID, -1)
+	server, ok := serverManager.serverList.Get(sessionRegexExec[0][1])
+	if !ok {
+		return nil, nil, false
+	}
+	server.muClients.Lock()
+	client, ok := server.Clients.Get(sessionRegexExec[0][2])
+	server.muClients.Unlock()
+	if !ok {
+		return nil, nil, false
+	}
+
+	return server, client, true
+}
+
+// Sends a notification or revocation message to a WebSocket client
+func sendNotification(client *Client, messageType string, messageID string, messageTimestamp string, payload models.EventsubResponse) error {
+	metadata := MessageMetadata{
+		MessageID:           messageID,
+		MessageType:         messageType,
+		MessageTimestamp:    messageTimestamp,
+		SubscriptionType:    payload.Subscription.Type,
+		SubscriptionVersion: payload.Subscription.Version,
+	}
+	msg, err := json.Marshal(NotificationMessage{Metadata: metadata, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	return sendWithChaos(client, msg, metadata)
+}
@@ -0,0 +1,214 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestConduitHandlers(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 2)
+	defer f.close()
+	clientID, token := newTestAuthorization(t, "", "")
+	otherClientID, otherToken := newTestAuthorization(t, "", "")
+	userClientID, userToken := newTestAuthorization(t, "1", "")
+
+	session := func(c *fanoutClient) string { return f.ws.ServerId + "_" + c.name }
+
+	// Conduits require an app token
+	w := handlerRequest(conduitPageHandler, http.MethodGet, "/eventsub/conduits", userClientID, userToken, nil)
+	a.Equal(http.StatusUnauthorized, w.Code)
+	w = handlerRequest(conduitPageHandler, http.MethodGet, "/eventsub/conduits", clientID, "", nil)
+	a.Equal(http.StatusUnauthorized, w.Code)
+
+	// Create
+	w = handlerRequest(conduitPageHandler, http.MethodPost, "/eventsub/conduits", clientID, token, ConduitRequest{ShardCount: 0})
+	a.Equal(http.StatusBadRequest, w.Code)
+	w = handlerRequest(conduitPageHandler, http.MethodPost, "/eventsub/conduits", clientID, token, ConduitRequest{ShardCount: CONDUIT_MAX_SHARDS + 1})
+	a.Equal(http.StatusBadRequest, w.Code)
+
+	w = handlerRequest(conduitPageHandler, http.MethodPost, "/eventsub/conduits", clientID, token, ConduitRequest{ShardCount: 3})
+	a.Equal(http.StatusOK, w.Code)
+	var conduits ConduitResponse
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &conduits))
+	a.Len(conduits.Data, 1)
+	a.Equal(3, conduits.Data[0].ShardCount)
+	conduitID := conduits.Data[0].ID
+
+	// Conduits are only visible to the client that created them
+	w = handlerRequest(conduitPageHandler, http.MethodGet, "/eventsub/conduits", clientID, token, nil)
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &conduits))
+	a.Len(conduits.Data, 1)
+	w = handlerRequest(conduitPageHandler, http.MethodGet, "/eventsub/conduits", otherClientID, otherToken, nil)
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &conduits))
+	a.Empty(conduits.Data)
+	w = handlerRequest(conduitPageHandler, http.MethodPatch, "/eventsub/conduits", otherClientID, otherToken, ConduitRequest{ID: conduitID, ShardCount: 1})
+	a.Equal(http.StatusNotFound, w.Code)
+
+	// Including to a client with the ID "debug"
+	debugClient, err := serverManager.db.NewQuery(nil, 100).InsertOrUpdateAuthenticationClient(database.AuthenticationClient{ID: "debug", Name: "mock_server_test"}, true)
+	a.Nil(err)
+	a.Equal("debug", debugClient.ID)
+	debugAuth, err := serverManager.db.NewQuery(nil, 100).CreateAuthorization(database.Authorization{ClientID: debugClient.ID})
+	a.Nil(err)
+	w = handlerRequest(conduitPageHandler, http.MethodGet, "/eventsub/conduits", "debug", debugAuth.Token, nil)
+	a.Equal(http.StatusOK, w.Code)
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &conduits))
+	a.Empty(conduits.Data)
+	w = handlerRequest(conduitPageHandler, http.MethodDelete, "/eventsub/conduits?id="+conduitID, "debug", debugAuth.Token, nil)
+	a.Equal(http.StatusNotFound, w.Code)
+	w = handlerRequest(conduitShardsPageHandler, http.MethodGet, "/eventsub/conduits/shards?conduit_id="+conduitID, "debug", debugAuth.Token, nil)
+	a.Equal(http.StatusNotFound, w.Code)
+
+	// Update
+	w = handlerRequest(conduitPageHandler, http.MethodPatch, "/eventsub/conduits", clientID, token, ConduitRequest{ID: conduitID, ShardCount: 2})
+	a.Equal(http.StatusOK, w.Code)
+	w = handlerRequest(conduitPageHandler, http.MethodPatch, "/eventsub/conduits", clientID, token, ConduitRequest{ID: util.RandomGUID(), ShardCount: 2})
+	a.Equal(http.StatusNotFound, w.Code)
+
+	// Shard assignment validates each shard, and assigns the valid ones
+	w = handlerRequest(conduitShardsPageHandler, http.MethodPatch, "/eventsub/conduits/shards", clientID, token, ShardsPatchRequest{
+		ConduitID: conduitID,
+		Shards: []ShardsPatchRequestShard{
+			{ID: "0", Transport: SubscriptionPostRequestTransport{Method: models.TransportWebSocket, SessionID: session(f.clients[0])}},
+			{ID: "1", Transport: SubscriptionPostRequestTransport{Method: models.TransportWebSocket, SessionID: session(f.clients[1])}},
+			{ID: "2", Transport: SubscriptionPostRequestTransport{Method: models.TransportWebSocket, SessionID: session(f.clients[0])}},
+			{ID: "0", Transport: SubscriptionPostRequestTransport{Method: models.TransportWebSocket, SessionID: "fanout_missing"}},
+			{ID: "1", Transport: SubscriptionPostRequestTransport{Method: models.TransportWebhook, Callback: "not a url", Secret: testWebhookSecret}},
+			{ID: "1", Transport: SubscriptionPostRequestTransport{Method: "carrier_pigeon"}},
+		},
+	})
+	a.Equal(http.StatusAccepted, w.Code)
+	var shards ShardsPatchResponse
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &shards))
+	a.Len(shards.Data, 2)
+	codes := []string{}
+	for _, e := range shards.Errors {
+		codes = append(codes, e.Code)
+	}
+	a.Equal([]string{"invalid_shard_id", "websocket_session_not_found", "invalid_transport", "invalid_transport"}, codes)
+
+	w = handlerRequest(conduitShardsPageHandler, http.MethodPatch, "/eventsub/conduits/shards", otherClientID, otherToken, ShardsPatchRequest{ConduitID: conduitID})
+	a.Equal(http.StatusNotFound, w.Code)
+
+	w = handlerRequest(conduitShardsPageHandler, http.MethodGet, "/eventsub/conduits/shards?status=enabled&conduit_id="+conduitID, clientID, token, nil)
+	a.Equal(http.StatusOK, w.Code)
+	var enabled ShardsGetResponse
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &enabled))
+	a.Len(enabled.Data, 2)
+	a.Equal(session(f.clients[0]), enabled.Data[0].Transport.SessionID)
+
+	// Subscriptions can only use the client's own conduits
+	subscribe := func(clientID string, token string, conduitID string) int {
+		return handlerRequest(subscriptionPageHandler, http.MethodPost, "/eventsub/subscriptions", clientID, token, SubscriptionPostRequest{
+			Type:      "channel.update",
+			Version:   "2",
+			Condition: models.EventsubCondition{BroadcasterUserID: "1"},
+			Transport: SubscriptionPostRequestTransport{Method: TRANSPORT_CONDUIT, ConduitID: conduitID},
+		}).Code
+	}
+	a.Equal(http.StatusAccepted, subscribe(clientID, token, conduitID))
+	a.Equal(http.StatusConflict, subscribe(clientID, token, conduitID))
+	a.Equal(http.StatusBadRequest, subscribe(otherClientID, otherToken, conduitID))
+	a.Len(serverManager.conduitSubscriptions, 1)
+
+	// Deleting the conduit deletes its subscriptions
+	w = handlerRequest(conduitPageHandler, http.MethodDelete, "/eventsub/conduits", clientID, token, nil)
+	a.Equal(http.StatusBadRequest, w.Code)
+	w = handlerRequest(conduitPageHandler, http.MethodDelete, "/eventsub/conduits?id="+conduitID, otherClientID, otherToken, nil)
+	a.Equal(http.StatusNotFound, w.Code)
+	w = handlerRequest(conduitPageHandler, http.MethodDelete, "/eventsub/conduits?id="+conduitID, clientID, token, nil)
+	a.Equal(http.StatusNoContent, w.Code)
+	a.Empty(serverManager.conduits)
+	a.Empty(serverManager.conduitSubscriptions)
+}
+
+func TestConduitShardRotation(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	c := &Conduit{}
+	c.resize(3)
+	for i := range c.Shards {
+		c.Shards[i].Status = STATUS_ENABLED
+	}
+
+	next := func() string {
+		shard, ok := c.nextEnabledShard()
+		a.True(ok)
+		return shard.ID
+	}
+	a.Equal([]string{"0", "1", "2", "0"}, []string{next(), next(), next(), next()})
+
+	// Disabled shards are skipped
+	c.Shards[2].Status = STATUS_WEBSOCKET_DISCONNECTED
+	a.Equal([]string{"1", "0", "1"}, []string{next(), next(), next()})
+
+	c.Shards[0].Status = STATUS_WEBSOCKET_DISCONNECTED
+	c.Shards[1].Status = STATUS_WEBSOCKET_DISCONNECTED
+	_, ok := c.nextEnabledShard()
+	a.False(ok)
+}
+
+func TestConduitFailover(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 2)
+	defer f.close()
+
+	conduit := &Conduit{ID: "conduit", ClientID: "client"}
+	conduit.resize(2)
+	for i, c := range f.clients {
+		conduit.Shards[i].Method = models.TransportWebSocket
+		conduit.Shards[i].Status = STATUS_ENABLED
+		conduit.Shards[i].SessionID = f.ws.ServerId + "_" + c.name
+	}
+	serverManager.conduits = []*Conduit{conduit}
+	serverManager.conduitSubscriptions = []Subscription{{
+		SubscriptionID: "conduit_subscription", ClientID: "client", Type: "channel.update", Version: "2", Status: STATUS_ENABLED,
+		ConduitID: "conduit", Conditions: models.EventsubCondition{BroadcasterUserID: "1"},
+	}}
+
+	body, _ := json.Marshal(models.EventsubResponse{
+		Subscription: models.EventsubSubscription{
+			Type:      "channel.update",
+			Version:   "2",
+			Status:    STATUS_ENABLED,
+			Condition: models.EventsubCondition{BroadcasterUserID: "1"},
+		},
+	})
+	send := func(count int) {
+		f.delivered.Add(count)
+		for i := 0; i < count; i++ {
+			sent, err := serverManager.HandleConduitForwarding(string(body), "", "")
+			a.Nil(err)
+			a.Equal(1, sent)
+		}
+		f.delivered.Wait()
+	}
+
+	// Events are spread across the shards
+	send(4)
+	a.Len(f.clients[0].notifications, 2)
+	a.Len(f.clients[1].notifications, 2)
+
+	// When a shard's session disconnects, the shard is disabled and events go to the remaining shards
+	f.clients[0].conn.Close()
+	a.Eventually(func() bool {
+		serverManager.muConduits.Lock()
+		defer serverManager.muConduits.Unlock()
+		return conduit.Shards[0].Status == STATUS_WEBSOCKET_DISCONNECTED
+	}, time.Second, 10*time.Millisecond)
+
+	send(3)
+	a.Len(f.clients[1].notifications, 5)
+}
@@ -0,0 +1,286 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestSubscriptionIndex(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	subscription := func(id string, version string, status string, condition models.EventsubCondition) Subscription {
+		return Subscription{SubscriptionID: id, Type: "channel.follow", Version: version, Status: status, Conditions: condition}
+	}
+	idx := newSubscriptionIndex(map[string][]Subscription{
+		"broadcaster": {
+			subscription("1", "2", STATUS_ENABLED, models.EventsubCondition{BroadcasterUserID: "1", ModeratorUserID: "5"}),
+		},
+		"other": {
+			subscription("2", "2", STATUS_ENABLED, models.EventsubCondition{BroadcasterUserID: "2"}),
+		},
+		"any": {
+			subscription("3", "2", STATUS_ENABLED, models.EventsubCondition{BroadcasterUserID: "1", UserID: "3"}),
+			subscription("4", "2", STATUS_ENABLED, models.EventsubCondition{}),
+		},
+		"disabled": {
+			subscription("5", "2", STATUS_WEBSOCKET_DISCONNECTED, models.EventsubCondition{BroadcasterUserID: "1"}),
+		},
+		"version": {
+			subscription("6", "1", STATUS_ENABLED, models.EventsubCondition{BroadcasterUserID: "1"}),
+		},
+	})
+
+	event := models.EventsubSubscription{Type: "channel.follow", Version: "2", Condition: models.EventsubCondition{BroadcasterUserID: "1", UserID: "3"}}
+	matches := idx.match(event)
+	a.Len(matches, 2)
+	a.Equal("1", matches["broadcaster"].subscription.SubscriptionID)
+	a.Equal("3", matches["any"].subscription.SubscriptionID) // The client's first matching subscription
+
+	event.Condition.UserID = "4"
+	matches = idx.match(event)
+	a.Len(matches, 2)
+	a.Equal("4", matches["any"].subscription.SubscriptionID)
+
+	a.True(idx.hasTopicSubscription("other", event))
+	a.False(idx.hasTopicSubscription("disabled", event))
+	a.False(idx.hasTopicSubscription("version", event))
+	a.Empty(idx.match(models.EventsubSubscription{Type: "channel.ban", Version: "1"}))
+}
+
+func TestFanout(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 20)
+	defer f.close()
+
+	// Half of the sessions subscribe to their own broadcaster; The others receive every event
+	f.ws.muSubscriptions.Lock()
+	for i, c := range f.clients[:10] {
+		f.ws.Subscriptions[c.name] = []Subscription{{
+			SubscriptionID: util.RandomGUID(),
+			Type:           "channel.follow",
+			Version:        "2",
+			Status:         STATUS_ENABLED,
+			Conditions:     models.EventsubCondition{BroadcasterUserID: fmt.Sprint(i)},
+		}}
+	}
+	f.ws.subscriptionsChanged()
+	f.ws.muSubscriptions.Unlock()
+
+	a.Equal(11, f.send(t, "3"))
+	for i, c := range f.clients {
+		if i == 3 || i >= 10 {
+			a.Len(c.notifications, 1)
+		} else {
+			a.Empty(c.notifications)
+		}
+	}
+
+	f.close()
+
+	// With --require-subscription, only the subscribed session receives it
+	strict := newFanoutServer(t, 0)
+	defer strict.close()
+	strict.ws.StrictMode = true
+	strict.connect(t, 2)
+
+	strict.ws.muSubscriptions.Lock()
+	strict.ws.Subscriptions[strict.clients[0].name] = []Subscription{{
+		SubscriptionID: util.RandomGUID(),
+		Type:           "channel.follow",
+		Version:        "2",
+		Status:         STATUS_ENABLED,
+		Conditions:     models.EventsubCondition{BroadcasterUserID: "3"},
+	}}
+	strict.ws.subscriptionsChanged()
+	strict.ws.muSubscriptions.Unlock()
+
+	a.Equal(1, strict.send(t, "3"))
+	a.Len(strict.clients[0].notifications, 1)
+	a.Empty(strict.clients[1].notifications)
+}
+
+func BenchmarkFanout(b *testing.B) {
+	const sessions = 5000
+
+	f := newFanoutServer(b, sessions)
+	defer f.close()
+
+	// Every session receives the event
+	b.Run("Broadcast", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			f.send(b, "1")
+		}
+	})
+
+	// Every session subscribes to a different broadcaster, so the event is sent to one of them
+	f.ws.muSubscriptions.Lock()
+	for i, c := range f.clients {
+		f.ws.Subscriptions[c.name] = []Subscription{{
+			SubscriptionID: util.RandomGUID(),
+			Type:           "channel.follow",
+			Version:        "2",
+			Status:         STATUS_ENABLED,
+			Conditions:     models.EventsubCondition{BroadcasterUserID: fmt.Sprint(i)},
+		}}
+	}
+	f.ws.subscriptionsChanged()
+	f.ws.muSubscriptions.Unlock()
+
+	b.Run("Targeted", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			f.send(b, fmt.Sprint(i%sessions))
+		}
+	})
+}
+
+// A WebSocket server with connected sessions, which count the notifications and revocations they receive
+type fanoutServer struct {
+	ws        *WebSocketServer
+	server    *httptest.Server
+	clients   []*fanoutClient
+	delivered sync.WaitGroup
+	handlers  sync.WaitGroup // Connections still being handled
+	logOutput io.Writer
+}
+
+type fanoutClient struct {
+	name          string
+	conn          *websocket.Conn
+	notifications chan int // Receives 1 for each notification, buffered so nothing blocks when they aren't read
+	revocations   chan int // Receives 1 for each revocation
+}
+
+func newFanoutServer(tb testing.TB, sessions int) *fanoutServer {
+	f := &fanoutServer{logOutput: log.Writer()}
+	log.SetOutput(io.Discard)
+
+	serverManager = &ServerManager{protocolHttp: "http", protocolWs: "ws"}
+	f.ws = &WebSocketServer{
+		ServerId:         "fanout",
+		Status:           2,
+		Upgrader:         websocket.Upgrader{},
+		Clients:          &util.List[Client]{Elements: make(map[string]*Client)},
+		Subscriptions:    make(map[string][]Subscription),
+		ReconnectClients: &util.List[[]Subscription]{Elements: make(map[string]*[]Subscription)},
+	}
+	serverManager.serverList = &util.List[WebSocketServer]{Elements: map[string]*WebSocketServer{f.ws.ServerId: f.ws}}
+	serverManager.primaryServer = f.ws.ServerId
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.handlers.Add(1)
+		defer f.handlers.Done()
+		f.ws.WsPageHandler(w, r)
+	}))
+
+	f.connect(tb, sessions)
+	return f
+}
+
+// Connects sessions to the server. Server settings, such as StrictMode, must be set before sessions connect.
+func (f *fanoutServer) connect(tb testing.TB, sessions int) {
+	url := strings.Replace(f.server.URL, "http://", "ws://", 1)
+	for i := 0; i < sessions; i++ {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			f.close()
+			tb.Fatal(err)
+		}
+
+		welcome := WelcomeMessage{}
+		if err := conn.ReadJSON(&welcome); err != nil {
+			f.close()
+			tb.Fatal(err)
+		}
+
+		c := &fanoutClient{
+			name:          strings.TrimPrefix(welcome.Payload.Session.ID, f.ws.ServerId+"_"),
+			conn:          conn,
+			notifications: make(chan int, 100),
+			revocations:   make(chan int, 100),
+		}
+		f.clients = append(f.clients, c)
+
+		go func() {
+			for {
+				_, message, err := c.conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				if bytes.Contains(message, []byte(`"message_type":"notification"`)) {
+					select {
+					case c.notifications <- 1:
+					default:
+					}
+					f.delivered.Done()
+				} else if bytes.Contains(message, []byte(`"message_type":"revocation"`)) {
+					select {
+					case c.revocations <- 1:
+					default:
+					}
+					f.delivered.Done()
+				}
+			}
+		}()
+	}
+}
+
+// Triggers a channel.follow event for the broadcaster, waits for every session it was sent to to receive it, and returns
+// the number of sessions
+func (f *fanoutServer) send(tb testing.TB, broadcasterID string) int {
+	body, _ := json.Marshal(models.EventsubResponse{
+		Subscription: models.EventsubSubscription{
+			ID:        util.RandomGUID(),
+			Status:    STATUS_ENABLED,
+			Type:      "channel.follow",
+			Version:   "2",
+			Condition: models.EventsubCondition{BroadcasterUserID: broadcasterID, ModeratorUserID: broadcasterID},
+			Transport: models.EventsubTransport{Method: models.TransportWebSocket},
+			CreatedAt: util.GetTimestamp().Format(time.RFC3339Nano),
+		},
+	})
+
+	// Sessions with a matching subscription, and without --require-subscription, those not subscribed to the topic
+	idx := f.ws.getSubscriptionIndex()
+	expected := len(idx.match(models.EventsubSubscription{Type: "channel.follow", Version: "2", Condition: models.EventsubCondition{BroadcasterUserID: broadcasterID}}))
+	if !f.ws.StrictMode {
+		for _, c := range f.clients {
+			if !idx.hasTopicSubscription(c.name, models.EventsubSubscription{Type: "channel.follow", Version: "2"}) {
+				expected++
+			}
+		}
+	}
+	f.delivered.Add(expected)
+
+	if ok, msg := f.ws.HandleEventSubForwarding(string(body), "", "", ""); !ok {
+		tb.Fatal(msg)
+	}
+	f.delivered.Wait()
+
+	return expected
+}
+
+func (f *fanoutServer) close() {
+	// Waits for the server to handle the disconnects, so nothing is left running when the next test replaces serverManager
+	for _, c := range f.clients {
+		c.conn.Close()
+	}
+	f.handlers.Wait()
+	f.server.Close()
+	log.SetOutput(f.logOutput)
+}
@@ -0,0 +1,276 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+// Creates a client and an authorization for it in the mock API database, returning the client ID and token. An app token
+// is created when userID is empty. The server manager must already be created.
+func newTestAuthorization(t *testing.T, userID string, scopes string) (string, string) {
+	db, err := database.NewConnection(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverManager.db = &db
+
+	q := db.NewQuery(nil, 100)
+	client, err := q.InsertOrUpdateAuthenticationClient(database.AuthenticationClient{ID: util.RandomClientID(), Name: "mock_server_test"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := q.CreateAuthorization(database.Authorization{ClientID: client.ID, UserID: userID, Scopes: scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client.ID, auth.Token
+}
+
+// Sends a request to a Helix handler of the mock EventSub server, authenticated with the client ID and token when set
+func handlerRequest(handler http.HandlerFunc, method string, target string, clientID string, token string, body interface{}) *httptest.ResponseRecorder {
+	var b []byte
+	if body != nil {
+		b, _ = json.Marshal(body)
+	}
+
+	r := httptest.NewRequest(method, target, bytes.NewReader(b))
+	if clientID != "" {
+		r.Header.Set("Client-Id", clientID)
+	}
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+// Creates a channel.update subscription for the broadcaster, returning the response
+func createChannelUpdateSubscription(clientID string, token string, broadcasterID string, transport SubscriptionPostRequestTransport) *httptest.ResponseRecorder {
+	return handlerRequest(subscriptionPageHandler, http.MethodPost, "/eventsub/subscriptions", clientID, token, SubscriptionPostRequest{
+		Type:      "channel.update",
+		Version:   "2",
+		Condition: models.EventsubCondition{BroadcasterUserID: broadcasterID},
+		Transport: transport,
+	})
+}
+
+func TestWebSocketSubscriptionLimits(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 5)
+	defer f.close()
+	session := func(i int) SubscriptionPostRequestTransport {
+		return SubscriptionPostRequestTransport{Method: models.TransportWebSocket, SessionID: f.ws.ServerId + "_" + f.clients[i].name}
+	}
+
+	// Subscriptions for the authorized user cost 0, so only the connection limit applies
+	clientID, token := newTestAuthorization(t, "limits_user", "")
+	for i := 0; i < WEBSOCKET_MAX_CONNECTIONS; i++ {
+		w := createChannelUpdateSubscription(clientID, token, "limits_user", session(i))
+		a.Equal(http.StatusAccepted, w.Code, w.Body.String())
+	}
+	w := createChannelUpdateSubscription(clientID, token, "limits_user", session(WEBSOCKET_MAX_CONNECTIONS))
+	a.Equal(http.StatusTooManyRequests, w.Code)
+
+	// Sessions already counted can have more subscriptions, up to the total cost
+	for i := 0; i < WEBSOCKET_MAX_TOTAL_COST; i++ {
+		w := createChannelUpdateSubscription(clientID, token, fmt.Sprint(i), session(0))
+		a.Equal(http.StatusAccepted, w.Code, w.Body.String())
+	}
+	w = createChannelUpdateSubscription(clientID, token, "other", session(0))
+	a.Equal(http.StatusTooManyRequests, w.Code)
+
+	// Other users of the client have their own limits
+	otherClientID, otherToken := newTestAuthorization(t, "other_limits_user", "")
+	w = createChannelUpdateSubscription(otherClientID, otherToken, "other_limits_user", session(WEBSOCKET_MAX_CONNECTIONS))
+	a.Equal(http.StatusAccepted, w.Code, w.Body.String())
+
+	// Enabled subscriptions of every client count towards the session's limit
+	last := f.clients[len(f.clients)-1].name
+	f.ws.muSubscriptions.Lock()
+	for i := 0; i < WEBSOCKET_MAX_ENABLED_SUBSCRIPTIONS; i++ {
+		f.ws.Subscriptions[last] = append(f.ws.Subscriptions[last], Subscription{
+			SubscriptionID: util.RandomGUID(),
+			ClientID:       "another_client",
+			Type:           "channel.update",
+			Version:        "2",
+			Status:         STATUS_ENABLED,
+			Conditions:     models.EventsubCondition{BroadcasterUserID: fmt.Sprint(i)},
+		})
+	}
+	f.ws.subscriptionsChanged()
+	f.ws.muSubscriptions.Unlock()
+
+	w = createChannelUpdateSubscription(otherClientID, otherToken, "other_limits_user", session(len(f.clients)-1))
+	a.Equal(http.StatusTooManyRequests, w.Code)
+
+	// Listings only include the token's subscriptions, and their totals agree
+	w = handlerRequest(subscriptionPageHandler, http.MethodGet, "/eventsub/subscriptions", clientID, token, nil)
+	a.Equal(http.StatusOK, w.Code)
+	var list SubscriptionGetSuccessResponse
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &list))
+	a.Len(list.Data, WEBSOCKET_MAX_CONNECTIONS+WEBSOCKET_MAX_TOTAL_COST)
+	a.Equal(len(list.Data), list.Total)
+	a.Equal(WEBSOCKET_MAX_TOTAL_COST, list.TotalCost)
+	a.Equal(WEBSOCKET_MAX_TOTAL_COST, list.MaxTotalCost)
+}
+
+func TestWebhookSubscriptionCostLimit(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 0)
+	defer f.close()
+	callback := newTestCallback(func(challenge string) string { return challenge })
+	defer callback.server.Close()
+	clientID, token := newTestAuthorization(t, "", "")
+	webhook := SubscriptionPostRequestTransport{Method: models.TransportWebhook, Callback: callback.server.URL, Secret: testWebhookSecret}
+
+	// The client is one short of the maximum total cost, with a disabled subscription that doesn't count towards it
+	serverManager.muWebhookSubscriptions.Lock()
+	serverManager.webhookSubscriptions = []Subscription{
+		{SubscriptionID: util.RandomGUID(), ClientID: clientID, Type: "channel.update", Version: "2", Status: STATUS_ENABLED, Cost: WEBHOOK_MAX_TOTAL_COST - 1},
+		{SubscriptionID: util.RandomGUID(), ClientID: clientID, Type: "channel.update", Version: "2", Status: STATUS_WEBHOOK_CALLBACK_VERIFICATION_FAILED, Cost: 1},
+	}
+	serverManager.muWebhookSubscriptions.Unlock()
+
+	// Concurrent requests can't exceed it
+	var wg sync.WaitGroup
+	codes := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes <- createChannelUpdateSubscription(clientID, token, fmt.Sprint(i), webhook).Code
+		}(i)
+	}
+	wg.Wait()
+	close(codes)
+
+	accepted := 0
+	for code := range codes {
+		if code == http.StatusAccepted {
+			accepted++
+		} else {
+			a.Equal(http.StatusTooManyRequests, code)
+		}
+	}
+	a.Equal(1, accepted)
+
+	// Conduit subscriptions share the limit
+	w := handlerRequest(conduitPageHandler, http.MethodPost, "/eventsub/conduits", clientID, token, ConduitRequest{ShardCount: 1})
+	a.Equal(http.StatusOK, w.Code, w.Body.String())
+	var conduits ConduitResponse
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &conduits))
+	w = createChannelUpdateSubscription(clientID, token, "conduit", SubscriptionPostRequestTransport{Method: TRANSPORT_CONDUIT, ConduitID: conduits.Data[0].ID})
+	a.Equal(http.StatusTooManyRequests, w.Code)
+
+	w = handlerRequest(subscriptionPageHandler, http.MethodGet, "/eventsub/subscriptions", clientID, token, nil)
+	a.Equal(http.StatusOK, w.Code)
+	var list SubscriptionGetSuccessResponse
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &list))
+	a.Len(list.Data, 3)
+	a.Equal(3, list.Total)
+	a.Equal(WEBHOOK_MAX_TOTAL_COST, list.TotalCost)
+	a.Equal(WEBHOOK_MAX_TOTAL_COST, list.MaxTotalCost)
+}
+
+func TestSubscriptionAuthorization(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 1)
+	defer f.close()
+	websocketTransport := SubscriptionPostRequestTransport{Method: models.TransportWebSocket, SessionID: f.ws.ServerId + "_" + f.clients[0].name}
+	callback := newTestCallback(func(challenge string) string { return challenge })
+	defer callback.server.Close()
+
+	clientID, token := newTestAuthorization(t, "auth_user", "moderator:read:followers")
+	otherClientID, otherToken := newTestAuthorization(t, "auth_user", "moderator:read:followers")
+	appClientID, appToken := newTestAuthorization(t, "", "")
+
+	// Tokens of the same client for another user, and for the app
+	q := serverManager.db.NewQuery(nil, 100)
+	otherUserAuth, err := q.CreateAuthorization(database.Authorization{ClientID: clientID, UserID: "other_auth_user"})
+	a.Nil(err)
+	sameClientAppAuth, err := q.CreateAuthorization(database.Authorization{ClientID: clientID})
+	a.Nil(err)
+
+	follow := func(clientID string, token string) *httptest.ResponseRecorder {
+		return handlerRequest(subscriptionPageHandler, http.MethodPost, "/eventsub/subscriptions", clientID, token, SubscriptionPostRequest{
+			Type:      "channel.follow",
+			Version:   "2",
+			Condition: models.EventsubCondition{BroadcasterUserID: "auth_user", ModeratorUserID: "auth_user"},
+			Transport: websocketTransport,
+		})
+	}
+
+	// 401 without a valid Client-Id and token
+	for _, w := range []*httptest.ResponseRecorder{
+		follow("", token),
+		follow(clientID, ""),
+		follow(clientID, "not_a_token"),
+		follow(clientID, otherToken),
+		handlerRequest(subscriptionPageHandler, http.MethodGet, "/eventsub/subscriptions", clientID, "", nil),
+		handlerRequest(subscriptionPageHandler, http.MethodDelete, "/eventsub/subscriptions?id=1", "", token, nil),
+	} {
+		a.Equal(http.StatusUnauthorized, w.Code, w.Body.String())
+	}
+
+	// 403 without one of the topic's required scopes
+	noScopesAuth, err := q.CreateAuthorization(database.Authorization{ClientID: clientID, UserID: "auth_user"})
+	a.Nil(err)
+	w := follow(clientID, noScopesAuth.Token)
+	a.Equal(http.StatusForbidden, w.Code, w.Body.String())
+	w = follow(clientID, otherUserAuth.Token)
+	a.Equal(http.StatusForbidden, w.Code, w.Body.String())
+
+	// 403 for tokens of users that aren't in the condition, even with the scope
+	thirdClientID, thirdToken := newTestAuthorization(t, "third_auth_user", "moderator:read:followers")
+	w = follow(thirdClientID, thirdToken)
+	a.Equal(http.StatusForbidden, w.Code, w.Body.String())
+
+	w = follow(clientID, token)
+	a.Equal(http.StatusAccepted, w.Code, w.Body.String())
+	var created SubscriptionPostSuccessResponse
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &created))
+	websocketID := created.Data[0].ID
+
+	w = createChannelUpdateSubscription(appClientID, appToken, "1", SubscriptionPostRequestTransport{Method: models.TransportWebhook, Callback: callback.server.URL, Secret: testWebhookSecret})
+	a.Equal(http.StatusAccepted, w.Code, w.Body.String())
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &created))
+	webhookID := created.Data[0].ID
+
+	deleteSubscription := func(id string, clientID string, token string) int {
+		return handlerRequest(subscriptionPageHandler, http.MethodDelete, "/eventsub/subscriptions?id="+id, clientID, token, nil).Code
+	}
+
+	// 404 for subscriptions of other clients, other users of the client, and other transports than the token can use
+	a.Equal(http.StatusNotFound, deleteSubscription(websocketID, otherClientID, otherToken))
+	a.Equal(http.StatusNotFound, deleteSubscription(websocketID, clientID, otherUserAuth.Token))
+	a.Equal(http.StatusNotFound, deleteSubscription(websocketID, clientID, sameClientAppAuth.Token))
+	a.Equal(http.StatusNotFound, deleteSubscription(webhookID, clientID, sameClientAppAuth.Token))
+	a.Equal(http.StatusNotFound, deleteSubscription(webhookID, clientID, token))
+
+	f.ws.muSubscriptions.Lock()
+	a.Len(f.ws.Subscriptions[f.clients[0].name], 1)
+	f.ws.muSubscriptions.Unlock()
+	a.NotEmpty(webhookSubscriptionStatus(webhookID))
+
+	a.Equal(http.StatusNoContent, deleteSubscription(websocketID, clientID, token))
+	a.Equal(http.StatusNoContent, deleteSubscription(webhookID, appClientID, appToken))
+	a.Equal(http.StatusNotFound, deleteSubscription(websocketID, clientID, token))
+}
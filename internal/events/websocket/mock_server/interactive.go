@@ -0,0 +1,321 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/pflag"
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+type shellCommand struct {
+	Name  string
+	Usage string
+	Help  string
+	Run   func(w io.Writer, args []string) error
+}
+
+func shellCommands() []shellCommand {
+	return []shellCommand{
+		{"sessions", "sessions", "Lists the sessions connected to the server.", shellSessions},
+		{"subs", "subs [session]", "Lists the subscriptions of every transport, or only those of a session.", shellSubscriptions},
+		{"trigger", "trigger <event> [flags]", "Triggers an event. Accepts the flags of \"twitch event trigger\" used with the websocket transport; run \"trigger --help\" to list them.", shellTrigger},
+		{"reconnect", "reconnect [flags]", "Starts reconnect testing. Accepts --session, --percent, --waves, --wave-interval, --grace-period, and --close-old-connections; run \"reconnect --help\" to list them.", shellReconnect},
+		{"close", "close <session> <code>", "Closes a session with the given close code.", shellClose},
+		{"keepalive", "keepalive on|off <session>", "Enables or disables keepalive messages for a session.", shellKeepalive},
+		{"subscription", "subscription <subscription_id> <status>", "Changes the status of a subscription.", shellSubscriptionStatus},
+		{"help", "help", "Lists the available commands.", nil},
+		{"exit", "exit", "Stops the server. Ctrl + D and Ctrl + C on an empty line also work.", nil},
+	}
+}
+
+// Runs an interactive shell for controlling the server from the terminal it was started in, returning when the user exits
+func runInteractiveShell() error {
+	historyFile := ""
+	if home, err := util.GetApplicationDir(); err == nil {
+		historyFile = filepath.Join(home, "websocket-shell-history")
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          "eventsub> ",
+		HistoryFile:     historyFile,
+		AutoComplete:    shellCompleter(),
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+
+	// Logs are written through readline so they don't overwrite the line being typed
+	log.SetOutput(rl.Stderr())
+	defer log.SetOutput(os.Stderr)
+
+	commands := shellCommands()
+	fmt.Fprintln(rl.Stdout(), `Interactive shell enabled. Type "help" to list commands; Tab completes commands, sessions, and events.`)
+
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			if line == "" {
+				return nil
+			}
+			continue
+		} else if err != nil { // io.EOF
+			return nil
+		}
+
+		if exit := runShellLine(rl.Stdout(), commands, line); exit {
+			return nil
+		}
+	}
+}
+
+// Splits a line typed into the shell into its lowercased command name and arguments. The name is empty for blank lines.
+func parseShellLine(line string) (string, []string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), fields[1:]
+}
+
+// Runs the command on a line typed into the shell, writing its output and errors to w. Returns true if the user exits.
+func runShellLine(w io.Writer, commands []shellCommand, line string) bool {
+	name, args := parseShellLine(line)
+	switch name {
+	case "":
+		return false
+	case "exit", "quit":
+		return true
+	case "help":
+		printShellHelp(w, commands)
+		return false
+	}
+
+	for _, c := range commands {
+		if c.Name == name && c.Run != nil {
+			if err := c.Run(w, args); err != nil {
+				fmt.Fprintf(w, "Error: %v\n", err)
+			}
+			return false
+		}
+	}
+
+	fmt.Fprintf(w, "Unknown command %q. Type \"help\" to list commands.\n", strings.Fields(line)[0])
+	return false
+}
+
+func printShellHelp(w io.Writer, commands []shellCommand) {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %v\t%v\n", c.Usage, c.Help)
+	}
+	tw.Flush()
+}
+
+func shellCompleter() *readline.PrefixCompleter {
+	sessions := readline.PcItemDynamic(func(string) []string { return connectedSessionIDs() })
+	events := readline.PcItemDynamic(func(string) []string { return types.AllWebhookTopics() })
+
+	statuses := []readline.PrefixCompleterInterface{}
+	for _, s := range []string{STATUS_ENABLED, STATUS_AUTHORIZATION_REVOKED, STATUS_MODERATOR_REMOVED, STATUS_USER_REMOVED, STATUS_VERSION_REMOVED} {
+		statuses = append(statuses, readline.PcItem(s))
+	}
+
+	return readline.NewPrefixCompleter(
+		readline.PcItem("sessions"),
+		readline.PcItem("subs", sessions),
+		readline.PcItem("trigger", events),
+		readline.PcItem("reconnect",
+			readline.PcItem("--session", sessions),
+			readline.PcItem("--percent"),
+			readline.PcItem("--waves"),
+			readline.PcItem("--wave-interval"),
+			readline.PcItem("--grace-period"),
+			readline.PcItem("--close-old-connections"),
+		),
+		readline.PcItem("close", readline.PcItemDynamic(func(string) []string { return connectedSessionIDs() },
+			readline.PcItem("1000"), readline.PcItem("4000"), readline.PcItem("4001"), readline.PcItem("4002"),
+			readline.PcItem("4003"), readline.PcItem("4004"), readline.PcItem("4005"), readline.PcItem("4006"),
+			readline.PcItem("4007"),
+		)),
+		readline.PcItem("keepalive",
+			readline.PcItem("on", sessions),
+			readline.PcItem("off", sessions),
+		),
+		readline.PcItem("subscription", readline.PcItemDynamic(func(string) []string { return subscriptionIDs() }, statuses...)),
+		readline.PcItem("help"),
+		readline.PcItem("exit"),
+	)
+}
+
+// Returns the session IDs of the clients connected to the primary server, sorted by the time they connected
+func connectedSessionIDs() []string {
+	server, ok := serverManager.serverList.Get(serverManager.primaryServer)
+	if !ok {
+		return []string{}
+	}
+
+	server.muClients.Lock()
+	clients := server.Clients.All()
+	server.muClients.Unlock()
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ConnectedAtTimestamp < clients[j].ConnectedAtTimestamp })
+
+	ids := []string{}
+	for _, c := range clients {
+		ids = append(ids, fmt.Sprintf("%v_%v", server.ServerId, c.clientName))
+	}
+	return ids
+}
+
+// Returns the IDs of the primary server's WebSocket subscriptions
+func subscriptionIDs() []string {
+	server, ok := serverManager.serverList.Get(serverManager.primaryServer)
+	if !ok {
+		return []string{}
+	}
+
+	server.muSubscriptions.Lock()
+	defer server.muSubscriptions.Unlock()
+
+	ids := []string{}
+	for _, subs := range server.Subscriptions {
+		for _, s := range subs {
+			ids = append(ids, s.SubscriptionID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func shellSessions(w io.Writer, args []string) error {
+	sessions, err := listSessions()
+	if err != nil {
+		return err
+	}
+
+	return PrintSessions(w, sessions)
+}
+
+func shellSubscriptions(w io.Writer, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: subs [session]")
+	}
+
+	session := ""
+	if len(args) == 1 {
+		session = args[0]
+	}
+
+	subs, err := listSubscriptions(session)
+	if err != nil {
+		return err
+	}
+
+	return PrintSubscriptions(w, subs)
+}
+
+func shellTrigger(w io.Writer, args []string) error {
+	p := trigger.TriggerParameters{}
+
+	flags := triggerFlags(&p)
+	flags.SetOutput(w)
+
+	err := flags.Parse(args)
+	if errors.Is(err, pflag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: trigger <event> [flags]")
+	}
+	p.Event = flags.Arg(0)
+
+	// The server logs where each event was sent, so the payloads aren't printed as "twitch event trigger" does
+	for i := 0; i < p.Count; i++ {
+		_, err := trigger.Fire(p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the flags of the trigger command, used by the shell and the dashboard, which set the fields of p
+func triggerFlags(p *trigger.TriggerParameters) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("trigger", pflag.ContinueOnError)
+	trigger.AddFlags(flags, p, models.TransportWebSocket)
+	return flags
+}
+
+func shellReconnect(w io.Writer, args []string) error {
+	o := ReconnectOptions{GracePeriod: new(time.Duration)}
+
+	flags := pflag.NewFlagSet("reconnect", pflag.ContinueOnError)
+	flags.SetOutput(w)
+	flags.StringSliceVar(&o.Sessions, "session", nil, "Only reconnects these sessions. Can be repeated or comma-separated.")
+	flags.Float64Var(&o.Percent, "percent", 0, "Only reconnects this percentage of sessions, chosen at random.")
+	flags.IntVar(&o.Waves, "waves", 1, "Number of waves the sessions are reconnected in.")
+	flags.DurationVar(&o.WaveInterval, "wave-interval", 10*time.Second, "Time between waves.")
+	flags.DurationVar(o.GracePeriod, "grace-period", RECONNECT_GRACE_SECONDS*time.Second, "Time sessions have to reconnect before they're closed with 4004.")
+	flags.BoolVar(&o.CloseOldConnections, "close-old-connections", false, "Closes a session's old connection with 4004 as soon as it reconnects.")
+
+	err := flags.Parse(args)
+	if errors.Is(err, pflag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: reconnect [flags]")
+	}
+
+	return startReconnect(o)
+}
+
+func shellClose(w io.Writer, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: close <session> <code>")
+	}
+
+	closeCode, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("usage: close <session> <code>")
+	}
+
+	return closeSession(args[0], closeCode)
+}
+
+func shellKeepalive(w io.Writer, args []string) error {
+	if len(args) != 2 || (args[0] != "on" && args[0] != "off") {
+		return fmt.Errorf("usage: keepalive on|off <session>")
+	}
+
+	return setKeepalive(args[1], args[0] == "on")
+}
+
+func shellSubscriptionStatus(w io.Writer, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: subscription <subscription_id> <status>")
+	}
+
+	return setSubscriptionStatus(args[0], args[1])
+}
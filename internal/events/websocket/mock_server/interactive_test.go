@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestParseShellLine(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	name, args := parseShellLine("  Trigger channel.follow  -t 1234 ")
+	a.Equal("trigger", name)
+	a.Equal([]string{"channel.follow", "-t", "1234"}, args)
+
+	name, args = parseShellLine("sessions")
+	a.Equal("sessions", name)
+	a.Empty(args)
+
+	name, args = parseShellLine("   ")
+	a.Equal("", name)
+	a.Empty(args)
+}
+
+func TestRunShellLine(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	var received []string
+	commands := []shellCommand{
+		{"echo", "echo [args]", "Echoes its arguments.", func(w io.Writer, args []string) error {
+			received = args
+			fmt.Fprintln(w, args)
+			return nil
+		}},
+		{"fail", "fail", "Always fails.", func(w io.Writer, args []string) error { return fmt.Errorf("failed") }},
+		{"help", "help", "Lists the available commands.", nil},
+	}
+
+	run := func(line string) (string, bool) {
+		var w bytes.Buffer
+		exit := runShellLine(&w, commands, line)
+		return w.String(), exit
+	}
+
+	out, exit := run("ECHO a b")
+	a.False(exit)
+	a.Equal([]string{"a", "b"}, received)
+	a.Equal("[a b]\n", out)
+
+	out, _ = run("fail")
+	a.Equal("Error: failed\n", out)
+
+	out, _ = run("Nope x")
+	a.Contains(out, `Unknown command "Nope"`)
+
+	out, _ = run("help")
+	a.Contains(out, "echo [args]")
+	a.Contains(out, "Always fails.")
+
+	out, exit = run("")
+	a.Empty(out)
+	a.False(exit)
+
+	_, exit = run("exit")
+	a.True(exit)
+	_, exit = run("quit")
+	a.True(exit)
+}
+
+func TestShellCommands(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 1)
+	defer f.close()
+	session := f.ws.ServerId + "_" + f.clients[0].name
+
+	run := func(line string) string {
+		var w bytes.Buffer
+		a.False(runShellLine(&w, shellCommands(), line))
+		return w.String()
+	}
+
+	a.Contains(run("sessions"), session)
+	a.Contains(run("subs"), "No subscriptions.")
+	a.Contains(run("subs "+session+" extra"), "Error: usage: subs [session]")
+
+	// Arguments are validated before anything is sent
+	a.Contains(run("close "+session), "Error: usage: close <session> <code>")
+	a.Contains(run("close "+session+" abc"), "Error: usage: close <session> <code>")
+	a.Contains(run("keepalive maybe "+session), "Error: usage: keepalive on|off <session>")
+	a.Contains(run("subscription 1234"), "Error: usage: subscription <subscription_id> <status>")
+	a.Contains(run("subscription 1234 not_a_status"), "Error: Changing a subscription's status requires")
+	a.Contains(run("reconnect now"), "Error: usage: reconnect [flags]")
+	a.Contains(run("trigger"), "Error: usage: trigger <event> [flags]")
+	a.Contains(run("trigger --help"), "--to-user")
+	a.Contains(run("trigger --help"), "--ban-end") // The flags are shared with "twitch event trigger"
+
+	client, _ := f.ws.Clients.Get(f.clients[0].name)
+	a.Empty(run("keepalive off " + session))
+	a.False(client.KeepAliveEnabled)
+	a.Empty(run("keepalive on " + session))
+	a.True(client.KeepAliveEnabled)
+
+	// Completing session IDs is safe while sessions connect
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.connect(t, 3)
+	}()
+	for completing := true; completing; {
+		select {
+		case <-done:
+			completing = false
+		default:
+			connectedSessionIDs()
+		}
+	}
+	a.Len(connectedSessionIDs(), 4)
+	a.Equal(session, connectedSessionIDs()[0])
+}
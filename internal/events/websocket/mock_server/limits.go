@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+// Production EventSub limits
+// https://dev.twitch.tv/docs/eventsub/manage-subscriptions/#subscription-limits
+const (
+	WEBSOCKET_MAX_CONNECTIONS           = 3   // WebSocket sessions with enabled subscriptions per user token and client ID
+	WEBSOCKET_MAX_ENABLED_SUBSCRIPTIONS = 300 // Enabled subscriptions per WebSocket session
+	WEBSOCKET_MAX_TOTAL_COST            = 10  // Total cost of enabled WebSocket subscriptions per user token and client ID
+	WEBHOOK_MAX_TOTAL_COST              = 10000
+)
+
+// Identifies who created a subscription, from the request's Authorization header and the mock API's authorizations.
+// See authenticateRequest
+type subscriptionOwner struct {
+	ClientID string
+	Token    string // Bearer token of the request
+	UserID   string // User the token was issued to; Empty for app tokens
+	Scopes   string // Space-separated scopes of the token
+	appToken bool   // Token is an app access token
+}
+
+// Limits for WebSocket subscriptions apply to the user, or the token when the user isn't known
+func (o subscriptionOwner) userKey() string {
+	if o.UserID != "" {
+		return o.UserID
+	}
+	return o.Token
+}
+
+func (s Subscription) owner() subscriptionOwner {
+	return subscriptionOwner{ClientID: s.ClientID, Token: s.Token, UserID: s.UserID}
+}
+
+// User tokens can list and delete the WebSocket subscriptions created for their user with the client
+func (o subscriptionOwner) ownsWebSocketSubscription(s Subscription) bool {
+	return !o.appToken && s.ClientID == o.ClientID && s.owner().userKey() == o.userKey()
+}
+
+// App tokens can list and delete the client's webhook and conduit subscriptions
+func (o subscriptionOwner) ownsAppSubscription(s Subscription) bool {
+	return o.appToken && s.ClientID == o.ClientID
+}
+
+// Subscriptions cost 0 when a user in their condition has authorized the client, and 1 otherwise
+func subscriptionCost(clientID string, s Subscription) int {
+	if serverManager.db == nil {
+		return 1
+	}
+
+	q := serverManager.db.NewQuery(nil, 100)
+	for _, userID := range conditionUserIDs(s.Conditions) {
+		if authorized, err := q.IsUserAuthorized(clientID, userID); err == nil && authorized {
+			return 0
+		}
+	}
+
+	return 1
+}
+
+// Returns the number and total cost of the enabled WebSocket subscriptions created by the owner, and the number of
+// sessions they're on. Caller must hold server.muSubscriptions
+func (ws *WebSocketServer) websocketCostTotals(owner subscriptionOwner) (count int, totalCost int, sessions map[string]bool) {
+	sessions = map[string]bool{}
+	for clientName, subs := range ws.Subscriptions {
+		for _, s := range subs {
+			if s.Status != STATUS_ENABLED || s.ClientID != owner.ClientID || s.owner().userKey() != owner.userKey() {
+				continue
+			}
+			count++
+			totalCost += s.Cost
+			sessions[clientName] = true
+		}
+	}
+	return
+}
+
+// Returns the number and total cost of the client's webhook and conduit subscriptions. Pending webhook subscriptions
+// count towards the total, as in production. Caller must hold sm.muWebhookSubscriptions and sm.muConduits, locked in
+// that order, so the limit can be checked and the subscription added atomically
+func (sm *ServerManager) webhookCostTotals(clientID string) (count int, totalCost int) {
+	for _, s := range sm.webhookSubscriptions {
+		if s.ClientID == clientID && (s.Status == STATUS_ENABLED || s.Status == STATUS_WEBHOOK_CALLBACK_VERIFICATION_PENDING) {
+			count++
+			totalCost += s.Cost
+		}
+	}
+
+	for _, s := range sm.conduitSubscriptions {
+		if s.ClientID == clientID && s.Status == STATUS_ENABLED {
+			count++
+			totalCost += s.Cost
+		}
+	}
+
+	return
+}
+
+// Returns the total cost, and maximum total cost, of the subscriptions created with the request's token, which are the
+// WebSocket subscriptions for user tokens, and the webhook and conduit subscriptions for app tokens
+func costTotalsForRequest(server *WebSocketServer, owner subscriptionOwner) (totalCost int, maxTotalCost int) {
+	if owner.appToken {
+		serverManager.muWebhookSubscriptions.Lock()
+		serverManager.muConduits.Lock()
+		_, totalCost = serverManager.webhookCostTotals(owner.ClientID)
+		serverManager.muConduits.Unlock()
+		serverManager.muWebhookSubscriptions.Unlock()
+		return totalCost, WEBHOOK_MAX_TOTAL_COST
+	}
+
+	server.muSubscriptions.Lock()
+	_, totalCost, _ = server.websocketCostTotals(owner)
+	server.muSubscriptions.Unlock()
+	return totalCost, WEBSOCKET_MAX_TOTAL_COST
+}
@@ -11,14 +11,17 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/gorilla/websocket"
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/certs"
+	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/events/types"
-	rpc_handler "github.com/twitchdev/twitch-cli/internal/rpc"
+	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/internal/util"
 )
 
@@ -28,29 +31,62 @@ type ServerManager struct {
 	primaryServer    string // The current primary server by its ID. This should be in serverList
 	ip               string // IP the server will bind to
 	port             int    // Port the server will bind to
+	adminPort        int    // Port the admin API will bind to
 	debugEnabled     bool   // Indicates if the server was started with --debug
 	strictMode       bool   // Indicates if the server was started with --require-subscriptions
 	sslEnabled       bool   // Indicates if the server was started with --ssl
 	protocolHttp     string // String for the HTTP protocol URIs (http or https)
 	protocolWs       string // String for the WS protocol URIs (ws or wss)
+
+	chaos     ChaosOptions // Network failures injected with the --chaos-* flags
+	recordDir string       // Directory session transcripts are written to with --record-dir; Empty if disabled
+
+	persist              bool   // WebSocket subscriptions are saved to the database with --persist-subscriptions
+	persistedFingerprint string // Subscriptions last saved, to skip saving when they're unchanged
+
+	webhookSubscriptions   []Subscription // Subscriptions using the webhook transport, which aren't tied to a server
+	muWebhookSubscriptions sync.Mutex     // Mutex for ServerManager.webhookSubscriptions
+
+	db *database.CLIDatabase // Mock API database, used to look up authorizations; nil if it couldn't be opened
+
+	conduits             []*Conduit     // Conduits created with the mock /eventsub/conduits endpoint
+	conduitSubscriptions []Subscription // Subscriptions using the conduit transport
+	muConduits           sync.Mutex     // Mutex for ServerManager.conduits and ServerManager.conduitSubscriptions
 }
 
 var serverManager *ServerManager
 
-func StartWebsocketServer(enableDebug bool, ip string, port int, enableSSL bool, strictMode bool) {
+func StartWebsocketServer(enableDebug bool, ip string, port int, adminPort int, enableSSL bool, strictMode bool, interactive bool, chaos ChaosOptions, recordDir string, persist bool) {
 	serverManager = &ServerManager{
 		serverList: &util.List[WebSocketServer]{
 			Elements: make(map[string]*WebSocketServer),
 		},
-		ip:               ip,
-		port:             port,
-		reconnectTesting: false,
-		strictMode:       strictMode,
-		sslEnabled:       enableSSL,
+		ip:                   ip,
+		port:                 port,
+		adminPort:            adminPort,
+		reconnectTesting:     false,
+		strictMode:           strictMode,
+		sslEnabled:           enableSSL,
+		chaos:                chaos,
+		recordDir:            recordDir,
+		persist:              persist,
+		webhookSubscriptions: []Subscription{},
+		conduits:             []*Conduit{},
+		conduitSubscriptions: []Subscription{},
 	}
 
 	serverManager.debugEnabled = enableDebug
 
+	// Subscription costs are based on the authorizations made with the mock API
+	if db, err := database.NewConnection(false); err == nil {
+		serverManager.db = &db
+	} else {
+		log.Printf("Could not open the mock API database; Every subscription will cost 1: %v", err)
+	}
+	if persist && serverManager.db == nil {
+		log.Fatalf("Cannot persist subscriptions without the database.")
+	}
+
 	// Start initial websocket server
 	initialServer := &WebSocketServer{
 		ServerId: util.RandomGUID()[:8],
@@ -67,6 +103,17 @@ func StartWebsocketServer(enableDebug bool, ip string, port int, enableSSL bool,
 			Elements: make(map[string]*[]Subscription),
 		},
 	}
+
+	// Restore the subscriptions saved by the last run with --persist-subscriptions
+	restoredCount := 0
+	if persist {
+		state, err := serverManager.loadSubscriptions()
+		if err != nil {
+			log.Fatalf("Cannot restore saved subscriptions: %v", err)
+		}
+		restoredCount = initialServer.restoreSubscriptions(state) + serverManager.restoreTransportSubscriptions(state)
+	}
+
 	serverManager.serverList.Put(initialServer.ServerId, initialServer)
 	serverManager.primaryServer = initialServer.ServerId
 
@@ -78,7 +125,11 @@ func StartWebsocketServer(enableDebug bool, ip string, port int, enableSSL bool,
 
 	// Register URL handler
 	m.HandleFunc("/ws", wsPageHandler)
-	m.HandleFunc("/eventsub/subscriptions", subscriptionPageHandler)
+	m.Handle("/eventsub/subscriptions", httpMetrics.Middleware("/eventsub/subscriptions", http.HandlerFunc(subscriptionPageHandler)))
+	m.Handle("/eventsub/conduits", httpMetrics.Middleware("/eventsub/conduits", http.HandlerFunc(conduitPageHandler)))
+	m.Handle("/eventsub/conduits/shards", httpMetrics.Middleware("/eventsub/conduits/shards", http.HandlerFunc(conduitShardsPageHandler)))
+	m.Handle("/metrics", metricsRegistry.Handler())
+	registerUIHandlers(m)
 
 	// Start HTTP server
 	go func() {
@@ -89,44 +140,23 @@ func StartWebsocketServer(enableDebug bool, ip string, port int, enableSSL bool,
 			return
 		}
 
-		lightYellow := color.New(color.FgHiYellow).SprintFunc()
-		lightRed := color.New(color.FgHiRed).SprintFunc()
-		brightWhite := color.New(color.FgHiWhite).SprintFunc()
-
 		// Serve HTTP server
 		if serverManager.sslEnabled {
 			serverManager.protocolHttp = "https"
 			serverManager.protocolWs = "wss"
 
-			home, err := util.GetApplicationDir()
+			// Uses localhost.crt and localhost.key from the application directory, generating them if they're missing or
+			// aren't valid for the IP the server binds to
+			files, err := certs.Localhost(ip)
 			if err != nil {
 				log.Fatalf("Cannot start HTTP server: %v", err)
 				return
 			}
-
-			crtFile := filepath.Join(home, "localhost.crt")
-			keyFile := filepath.Join(home, "localhost.key")
-			_, crtFileErr := os.Stat(crtFile)
-			_, keyFileErr := os.Stat(keyFile)
-			if errors.Is(crtFileErr, os.ErrNotExist) || errors.Is(keyFileErr, os.ErrNotExist) {
-				log.Fatalf(`%v
-** Files must be placed in %v as %v and %v **
-%v
-** However, if you wish to generate the files using OpenSSL, run these commands: **
-	openssl genrsa -out "%v" 2048
-	openssl req -new -x509 -sha256 -key "%v" -out "%v" -days 365`,
-					lightRed("ERROR: Missing one of the required SSL crt/key files."),
-					brightWhite(home),
-					brightWhite("localhost.crt"),
-					brightWhite("localhost.key"),
-					lightYellow("** Testing with Twitch CLI using SSL is meant for users experienced with SSL already, as these files must be added to your systems keychain to work without errors. **"),
-					keyFile, keyFile, crtFile)
-				return
-			}
+			printCertificateMsg(files)
 
 			printWelcomeMsg()
 
-			if err := http.ServeTLS(listen, m, crtFile, keyFile); err != nil {
+			if err := http.ServeTLS(listen, m, files.CertFile, files.KeyFile); err != nil {
 				log.Fatalf("Cannot start HTTP server: %v", err)
 				return
 			}
@@ -144,22 +174,60 @@ func StartWebsocketServer(enableDebug bool, ip string, port int, enableSSL bool,
 
 	}()
 
-	// Initalize RPC handler, to accept EventSub transports
-	rpc := rpc_handler.RPCHandler{
-		Port:     44747,
-		Handlers: make(map[string]rpc_handler.HandlerCallback),
+	// Start admin API, which other CLI commands and test suites use to fire events and control the server
+	adminServer := admin.NewServer(adminPort)
+	registerAdminHandlers(adminServer)
+	if err := adminServer.Start(); err != nil {
+		log.Fatalf("Cannot start admin API on port %v: %v\nUse --admin-port to run multiple servers on the same host.", adminPort, err)
+	}
+	defer adminServer.Shutdown()
+
+	if persist {
+		log.Printf("Restored %v subscriptions. Subscriptions are saved to the database as they change.", restoredCount)
+
+		persistStop := make(chan struct{})
+		persistDone := make(chan struct{})
+		go serverManager.persistSubscriptionsLoop(persistStop, persistDone)
+		defer func() {
+			close(persistStop)
+			<-persistDone
+			if err := serverManager.saveSubscriptions(); err != nil {
+				log.Printf("Could not save WebSocket subscriptions: %v", err)
+			}
+		}()
 	}
 
-	rpc.RegisterHandler("EventSubWebSocketReconnect", RPCReconnectHandler)
-	rpc.RegisterHandler("EventSubWebSocketForwardEvent", RPCFireEventSubHandler)
-	rpc.RegisterHandler("EventSubWebSocketCloseClient", RPCCloseHandler)
-	rpc.RegisterHandler("EventSubWebSocketSubscription", RPCSubscriptionHandler)
-	rpc.RegisterHandler("EventSubWebSocketKeepalive", RPCKeepaliveHandler)
-	rpc.StartBackgroundServer()
+	if !interactive {
+		<-stop // Wait for Ctrl + C
+		return
+	}
 
-	// TODO: Interactive shell maybe?
+	// The shell runs until the user exits it, but the server can still be stopped with a signal
+	shellDone := make(chan error, 1)
+	go func() {
+		shellDone <- runInteractiveShell()
+	}()
 
-	<-stop // Wait for Ctrl + C
+	select {
+	case <-stop:
+	case err := <-shellDone:
+		if err != nil {
+			log.Printf("Interactive shell failed: %v", err)
+			<-stop
+		}
+	}
+}
+
+func printCertificateMsg(files certs.Files) {
+	lightYellow := color.New(color.FgHiYellow).SprintFunc()
+
+	if files.Generated {
+		log.Printf(lightYellow("Generated an SSL certificate for localhost at %v"), files.CertFile)
+	}
+	if files.CACert != "" {
+		log.Println(lightYellow(certs.TrustHint(files.CACert)))
+	}
+	fmt.Println()
 }
 
 func printWelcomeMsg() {
@@ -172,12 +240,23 @@ func printWelcomeMsg() {
 	if serverManager.strictMode {
 		log.Println(lightBlue("--require-subscription enabled. Clients will have 10 seconds to subscribe before being disconnected."))
 	}
+	if serverManager.recordDir != "" {
+		log.Printf(lightBlue("Recording session transcripts to %v"), serverManager.recordDir)
+	}
+	if c := serverManager.chaos; c.Enabled() {
+		lightRed := color.New(color.FgHiRed).SprintFunc()
+		log.Print(lightRed(fmt.Sprintf("Chaos mode enabled. Probabilities: latency %v (up to %v), dropped keepalive %v, delayed keepalive %v, disconnect %v, reconnect %v, duplicate %v",
+			c.Latency, c.MaxLatency, c.DropKeepalive, c.DelayKeepalive, c.Disconnect, c.Reconnect, c.Duplicate)))
+	}
 
 	fmt.Println()
 
 	log.Printf(yellow("Simulate subscribing to events at: %v://%v:%v/eventsub/subscriptions"), serverManager.protocolHttp, serverManager.ip, serverManager.port)
-	log.Println(yellow("POST, GET, and DELETE are supported"))
+	log.Println(yellow("POST, GET, and DELETE are supported, with the websocket, webhook, and conduit transports"))
+	log.Printf(yellow("Conduits can be managed at: %v://%v:%v/eventsub/conduits"), serverManager.protocolHttp, serverManager.ip, serverManager.port)
 	log.Println(yellow("For more info: https://dev.twitch.tv/docs/cli/websocket-event-command/#simulate-subscribing-to-mock-eventsub"))
+	log.Printf(yellow("Prometheus metrics are served at: %v://%v:%v/metrics"), serverManager.protocolHttp, serverManager.ip, serverManager.port)
+	log.Printf(yellow("Open the dashboard at: %v://%v:%v/_ui"), serverManager.protocolHttp, serverManager.ip, serverManager.port)
 
 	fmt.Println()
 
@@ -190,6 +269,7 @@ func printWelcomeMsg() {
 	fmt.Println()
 
 	log.Printf(lightBlue("Connect to the WebSocket server at: ")+"%v://%v:%v/ws", serverManager.protocolWs, serverManager.ip, serverManager.port)
+	log.Printf(lightBlue("Admin API available at: ")+"http://127.0.0.1:%v/admin", serverManager.adminPort)
 }
 
 func wsPageHandler(w http.ResponseWriter, r *http.Request) {
@@ -245,10 +325,8 @@ func subscriptionPageHandlerGet(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("ratelimit-remaining", "799")
 	w.Header().Set("ratelimit-reset", fmt.Sprintf("%d", time.Now().Unix()+1)) // 1 second from now
 
-	// Basic error checking
-	clientID := r.Header.Get("client-id")
-	if clientID == "" {
-		handlerResponseErrorUnauthorized(w, "Client-Id header required")
+	owner, ok := authenticateRequest(w, r)
+	if !ok {
 		return
 	}
 
@@ -269,7 +347,7 @@ func subscriptionPageHandlerGet(w http.ResponseWriter, r *http.Request) {
 				disabledAndExpired = true
 			}
 
-			if clientID == "debug" || (subscription.ClientID == clientID && !disabledAndExpired) {
+			if owner.ownsWebSocketSubscription(subscription) && !disabledAndExpired {
 				allSubscriptions = append(allSubscriptions, SubscriptionPostSuccessResponseBody{
 					ID:        subscription.SubscriptionID,
 					Status:    subscription.Status,
@@ -283,7 +361,7 @@ func subscriptionPageHandlerGet(w http.ResponseWriter, r *http.Request) {
 						ConnectedAt:    subscription.ClientConnectedAt,
 						DisconnectedAt: subscription.ClientDisconnectedAt,
 					},
-					Cost: 0,
+					Cost: subscription.Cost,
 				})
 			}
 		}
@@ -291,12 +369,59 @@ func subscriptionPageHandlerGet(w http.ResponseWriter, r *http.Request) {
 
 	server.muSubscriptions.Unlock()
 
+	serverManager.muWebhookSubscriptions.Lock()
+
+	for _, subscription := range serverManager.webhookSubscriptions {
+		if owner.ownsAppSubscription(subscription) {
+			allSubscriptions = append(allSubscriptions, SubscriptionPostSuccessResponseBody{
+				ID:        subscription.SubscriptionID,
+				Status:    subscription.Status,
+				Type:      subscription.Type,
+				Version:   subscription.Version,
+				Condition: subscription.Conditions,
+				CreatedAt: subscription.CreatedAt,
+				Transport: SubscriptionTransport{
+					Method:   models.TransportWebhook,
+					Callback: subscription.Callback,
+				},
+				Cost: subscription.Cost,
+			})
+		}
+	}
+
+	serverManager.muWebhookSubscriptions.Unlock()
+
+	serverManager.muConduits.Lock()
+
+	for _, subscription := range serverManager.conduitSubscriptions {
+		if owner.ownsAppSubscription(subscription) {
+			allSubscriptions = append(allSubscriptions, SubscriptionPostSuccessResponseBody{
+				ID:        subscription.SubscriptionID,
+				Status:    subscription.Status,
+				Type:      subscription.Type,
+				Version:   subscription.Version,
+				Condition: subscription.Conditions,
+				CreatedAt: subscription.CreatedAt,
+				Transport: SubscriptionTransport{
+					Method:    TRANSPORT_CONDUIT,
+					ConduitID: subscription.ConduitID,
+				},
+				Cost: subscription.Cost,
+			})
+		}
+	}
+
+	serverManager.muConduits.Unlock()
+
+	// total counts every subscription listed, while total_cost only includes the enabled ones, as in production
+	totalCost, maxTotalCost := costTotalsForRequest(server, owner)
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(&SubscriptionGetSuccessResponse{
 		Total:        len(allSubscriptions),
 		Data:         allSubscriptions,
-		TotalCost:    0,
-		MaxTotalCost: 10,
+		TotalCost:    totalCost,
+		MaxTotalCost: maxTotalCost,
 		Pagination:   EmptyStruct{},
 	})
 }
@@ -308,6 +433,11 @@ func subscriptionPageHandlerPost(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("ratelimit-remaining", "799")
 	w.Header().Set("ratelimit-reset", fmt.Sprintf("%d", time.Now().Unix()+1)) // 1 second from now
 
+	owner, ok := authenticateRequest(w, r)
+	if !ok {
+		return
+	}
+
 	var body SubscriptionPostRequest
 
 	err := json.NewDecoder(r.Body).Decode(&body)
@@ -317,15 +447,27 @@ func subscriptionPageHandlerPost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Basic error checking
-	if r.Header.Get("client-id") == "" {
-		handlerResponseErrorUnauthorized(w, "Client-Id header required")
+	isWebhook := strings.EqualFold(body.Transport.Method, models.TransportWebhook)
+	isConduit := strings.EqualFold(body.Transport.Method, TRANSPORT_CONDUIT)
+	if !isWebhook && !isConduit && !strings.EqualFold(body.Transport.Method, models.TransportWebSocket) {
+		handlerResponseErrorBadRequest(w, "The value specified in the 'method' field is not valid")
 		return
 	}
-	if !strings.EqualFold(body.Transport.Method, "websocket") {
-		handlerResponseErrorBadRequest(w, "The value specified in the 'method' field is not valid")
+	if !owner.canUseTransport(body.Transport.Method) {
+		handlerResponseErrorBadRequest(w, "Invalid transport and auth combination")
 		return
 	}
-	if !sessionRegex.MatchString(body.Transport.SessionID) {
+	if isConduit {
+		if body.Transport.ConduitID == "" {
+			handlerResponseErrorBadRequest(w, "The value specified in the 'conduit_id' field is not valid")
+			return
+		}
+	} else if isWebhook {
+		if msg := validateWebhookTransport(body.Transport); msg != "" {
+			handlerResponseErrorBadRequest(w, msg)
+			return
+		}
+	} else if !sessionRegex.MatchString(body.Transport.SessionID) {
 		handlerResponseErrorBadRequest(w, "The value specified in the 'session_id' field is not valid")
 		return
 	}
@@ -346,12 +488,38 @@ func subscriptionPageHandlerPost(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	_, err = types.GetByTriggerAndTransportAndVersion(body.Type, body.Transport.Method, body.Version)
+	// Conduits can be used with any topic available to webhooks
+	eventTransport := body.Transport.Method
+	if isConduit {
+		eventTransport = models.TransportWebhook
+	}
+
+	_, err = types.GetByTriggerAndTransportAndVersion(body.Type, eventTransport, body.Version)
 	if err != nil {
 		handlerResponseErrorBadRequest(w, "The combination of values in the type and version fields is not valid")
 		return
 	}
 
+	err = types.ValidateCondition(body.Type, body.Version, body.Condition)
+	if err != nil {
+		handlerResponseErrorBadRequest(w, err.Error())
+		return
+	}
+
+	if !owner.canSubscribe(body.Type, body.Version, body.Condition) {
+		handlerResponseErrorForbidden(w, "subscription missing proper authorization")
+		return
+	}
+
+	if isWebhook {
+		subscriptionPageHandlerPostWebhook(w, r, body, owner)
+		return
+	}
+	if isConduit {
+		subscriptionPageHandlerPostConduit(w, r, body, owner)
+		return
+	}
+
 	sessionRegexExec := sessionRegex.FindAllStringSubmatch(body.Transport.SessionID, -1)
 	clientName := sessionRegexExec[0][2]
 
@@ -361,39 +529,63 @@ func subscriptionPageHandlerPost(w http.ResponseWriter, r *http.Request) {
 		handlerResponseErrorBadRequest(w, "non-existent session_id")
 		return
 	}
+	server.muClients.Lock()
 	client, ok := server.Clients.Get(clientName)
+	server.muClients.Unlock()
 	if !ok {
 		handlerResponseErrorBadRequest(w, "non-existent session_id")
 		return
 	}
 
+	// Add subscription
+	subscription := Subscription{
+		SubscriptionID:    util.RandomGUID(),
+		ClientID:          r.Header.Get("client-id"),
+		Type:              body.Type,
+		Version:           body.Version,
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339Nano),
+		Status:            STATUS_ENABLED, // https://dev.twitch.tv/docs/api/reference/#get-eventsub-subscriptions
+		Conditions:        body.Condition,
+		ClientConnectedAt: client.ConnectedAtTimestamp,
+		Token:             owner.Token,
+		UserID:            owner.UserID,
+	}
+	subscription.Cost = subscriptionCost(subscription.ClientID, subscription)
+
 	server.muSubscriptions.Lock()
 
 	// Check for duplicate subscription
 	for _, s := range server.Subscriptions[clientName] {
-		if s.ClientID == r.Header.Get("client-id") && s.Type == body.Type && s.Version == body.Version {
-			handlerResponseErrorConflict(w, "Subscription by the specified type and version combination for the specified Client ID already exists")
+		if s.ClientID == r.Header.Get("client-id") && s.Type == body.Type && s.Version == body.Version && s.Conditions == body.Condition {
+			handlerResponseErrorConflict(w, "Subscription by the specified type, version, and condition combination for the specified Client ID already exists")
 			server.muSubscriptions.Unlock()
 			return
 		}
 	}
 
-	if len(server.Subscriptions[clientName]) >= 100 {
-		handlerResponseErrorBadRequest(w, "You may only create 100 subscriptions within a single WebSocket connection")
+	// Check production limits
+	enabledCount := 0
+	for _, s := range server.Subscriptions[clientName] {
+		if s.Status == STATUS_ENABLED {
+			enabledCount++
+		}
+	}
+	if enabledCount >= WEBSOCKET_MAX_ENABLED_SUBSCRIPTIONS {
+		handlerResponseErrorTooManyRequests(w, fmt.Sprintf("You may only have %v enabled subscriptions within a single WebSocket connection", WEBSOCKET_MAX_ENABLED_SUBSCRIPTIONS))
 		server.muSubscriptions.Unlock()
 		return
 	}
 
-	// Add subscription
-	subscription := Subscription{
-		SubscriptionID:    util.RandomGUID(),
-		ClientID:          r.Header.Get("client-id"),
-		Type:              body.Type,
-		Version:           body.Version,
-		CreatedAt:         time.Now().UTC().Format(time.RFC3339Nano),
-		Status:            STATUS_ENABLED, // https://dev.twitch.tv/docs/api/reference/#get-eventsub-subscriptions
-		Conditions:        body.Condition,
-		ClientConnectedAt: client.ConnectedAtTimestamp,
+	total, totalCost, sessions := server.websocketCostTotals(owner)
+	if !sessions[clientName] && len(sessions) >= WEBSOCKET_MAX_CONNECTIONS {
+		handlerResponseErrorTooManyRequests(w, fmt.Sprintf("The number of WebSocket connections with enabled subscriptions for this user token and client ID exceeds the limit of %v", WEBSOCKET_MAX_CONNECTIONS))
+		server.muSubscriptions.Unlock()
+		return
+	}
+	if totalCost+subscription.Cost > WEBSOCKET_MAX_TOTAL_COST {
+		handlerResponseErrorTooManyRequests(w, fmt.Sprintf("The subscription exceeds the maximum total cost of %v", WEBSOCKET_MAX_TOTAL_COST))
+		server.muSubscriptions.Unlock()
+		return
 	}
 
 	var subs []Subscription
@@ -406,6 +598,7 @@ func subscriptionPageHandlerPost(w http.ResponseWriter, r *http.Request) {
 
 	subs = append(subs, subscription)
 	server.Subscriptions[clientName] = subs
+	server.subscriptionsChanged()
 
 	server.muSubscriptions.Unlock()
 
@@ -426,12 +619,12 @@ func subscriptionPageHandlerPost(w http.ResponseWriter, r *http.Request) {
 					SessionID:   fmt.Sprintf("%v_%v", server.ServerId, clientName),
 					ConnectedAt: client.ConnectedAtTimestamp,
 				},
-				Cost: 0,
+				Cost: subscription.Cost,
 			},
 		},
-		Total:        0,
-		MaxTotalCost: 10,
-		TotalCost:    0,
+		Total:        total + 1,
+		MaxTotalCost: WEBSOCKET_MAX_TOTAL_COST,
+		TotalCost:    totalCost + subscription.Cost,
 	})
 
 	if serverManager.debugEnabled {
@@ -445,6 +638,68 @@ func subscriptionPageHandlerPost(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func subscriptionPageHandlerPostWebhook(w http.ResponseWriter, r *http.Request, body SubscriptionPostRequest, owner subscriptionOwner) {
+	subscription := Subscription{
+		SubscriptionID: util.RandomGUID(),
+		ClientID:       r.Header.Get("client-id"),
+		Type:           body.Type,
+		Version:        body.Version,
+		CreatedAt:      time.Now().UTC().Format(time.RFC3339Nano),
+		Status:         STATUS_WEBHOOK_CALLBACK_VERIFICATION_PENDING, // Enabled once the callback responds to the challenge
+		Conditions:     body.Condition,
+		Callback:       body.Transport.Callback,
+		Secret:         body.Transport.Secret,
+		Token:          owner.Token,
+		UserID:         owner.UserID,
+	}
+	subscription.Cost = subscriptionCost(subscription.ClientID, subscription)
+
+	total, totalCost, err := serverManager.addWebhookSubscription(subscription)
+	if errors.Is(err, errMaxTotalCost) {
+		handlerResponseErrorTooManyRequests(w, fmt.Sprintf("The subscription exceeds the maximum total cost of %v", WEBHOOK_MAX_TOTAL_COST))
+		return
+	}
+	if err != nil {
+		handlerResponseErrorConflict(w, "Subscription by the specified type, version, condition, and callback combination for the specified Client ID already exists")
+		return
+	}
+
+	// Return 202 status code and response body
+	w.WriteHeader(http.StatusAccepted)
+
+	json.NewEncoder(w).Encode(&SubscriptionPostSuccessResponse{
+		Data: []SubscriptionPostSuccessResponseBody{
+			{
+				ID:        subscription.SubscriptionID,
+				Status:    subscription.Status,
+				Type:      subscription.Type,
+				Version:   subscription.Version,
+				Condition: subscription.Conditions,
+				CreatedAt: subscription.CreatedAt,
+				Transport: SubscriptionTransport{
+					Method:   models.TransportWebhook,
+					Callback: subscription.Callback,
+				},
+				Cost: subscription.Cost,
+			},
+		},
+		Total:        total + 1,
+		MaxTotalCost: WEBHOOK_MAX_TOTAL_COST,
+		TotalCost:    totalCost + subscription.Cost,
+	})
+
+	if serverManager.debugEnabled {
+		log.Printf(
+			"Client ID [%v] created webhook subscription [%v/%v] at subscription ID [%v] for callback [%v]",
+			r.Header.Get("client-id"),
+			subscription.Type,
+			subscription.Version,
+			subscription.SubscriptionID,
+			subscription.Callback,
+		)
+	}
+}
+
 func subscriptionPageHandlerDelete(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -455,8 +710,8 @@ func subscriptionPageHandlerDelete(w http.ResponseWriter, r *http.Request) {
 	subscriptionId := r.URL.Query().Get("id")
 
 	// Basic error checking
-	if r.Header.Get("client-id") == "" {
-		handlerResponseErrorUnauthorized(w, "Client-Id header required")
+	owner, ok := authenticateRequest(w, r)
+	if !ok {
 		return
 	}
 	if subscriptionId == "" {
@@ -474,15 +729,17 @@ func subscriptionPageHandlerDelete(w http.ResponseWriter, r *http.Request) {
 
 	server.muSubscriptions.Lock()
 
+	// Subscriptions of other clients and users are reported as not found
 	for client, clientSubscriptions := range server.Subscriptions {
 		for i, subscription := range clientSubscriptions {
-			if subscription.SubscriptionID == subscriptionId {
+			if subscription.SubscriptionID == subscriptionId && owner.ownsWebSocketSubscription(subscription) {
 				subFound = true
 				subsPart := make([]Subscription, 0)
 				subsPart = append(subsPart, server.Subscriptions[client][:i]...)
 
 				newSubs := append(subsPart, server.Subscriptions[client][i+1:]...)
 				server.Subscriptions[client] = newSubs
+				server.subscriptionsChanged()
 
 				if serverManager.debugEnabled {
 					log.Printf(
@@ -499,6 +756,50 @@ func subscriptionPageHandlerDelete(w http.ResponseWriter, r *http.Request) {
 
 	server.muSubscriptions.Unlock()
 
+	serverManager.muWebhookSubscriptions.Lock()
+
+	for i, subscription := range serverManager.webhookSubscriptions {
+		if subscription.SubscriptionID == subscriptionId && owner.ownsAppSubscription(subscription) {
+			subFound = true
+			serverManager.webhookSubscriptions = append(serverManager.webhookSubscriptions[:i], serverManager.webhookSubscriptions[i+1:]...)
+
+			if serverManager.debugEnabled {
+				log.Printf(
+					"Deleted webhook subscription [%v/%v] of ID [%v] owned by client ID [%v]",
+					subscription.Type,
+					subscription.Version,
+					subscription.SubscriptionID,
+					r.Header.Get("client-id"),
+				)
+			}
+			break
+		}
+	}
+
+	serverManager.muWebhookSubscriptions.Unlock()
+
+	serverManager.muConduits.Lock()
+
+	for i, subscription := range serverManager.conduitSubscriptions {
+		if subscription.SubscriptionID == subscriptionId && owner.ownsAppSubscription(subscription) {
+			subFound = true
+			serverManager.conduitSubscriptions = append(serverManager.conduitSubscriptions[:i], serverManager.conduitSubscriptions[i+1:]...)
+
+			if serverManager.debugEnabled {
+				log.Printf(
+					"Deleted conduit subscription [%v/%v] of ID [%v] owned by client ID [%v]",
+					subscription.Type,
+					subscription.Version,
+					subscription.SubscriptionID,
+					r.Header.Get("client-id"),
+				)
+			}
+			break
+		}
+	}
+
+	serverManager.muConduits.Unlock()
+
 	if subFound {
 		// Return 204 status code
 		w.WriteHeader(http.StatusNoContent)
@@ -523,7 +824,7 @@ func handlerResponseErrorBadRequest(w http.ResponseWriter, message string) {
 }
 
 func handlerResponseErrorUnauthorized(w http.ResponseWriter, message string) {
-	w.WriteHeader(http.StatusBadRequest)
+	w.WriteHeader(http.StatusUnauthorized)
 	bytes, _ := json.Marshal(&SubscriptionPostErrorResponse{
 		Error:   "Unauthorized",
 		Message: message,
@@ -532,6 +833,26 @@ func handlerResponseErrorUnauthorized(w http.ResponseWriter, message string) {
 	w.Write(bytes)
 }
 
+func handlerResponseErrorForbidden(w http.ResponseWriter, message string) {
+	w.WriteHeader(http.StatusForbidden)
+	bytes, _ := json.Marshal(&SubscriptionPostErrorResponse{
+		Error:   "Forbidden",
+		Message: message,
+		Status:  403,
+	})
+	w.Write(bytes)
+}
+
+func handlerResponseErrorNotFound(w http.ResponseWriter, message string) {
+	w.WriteHeader(http.StatusNotFound)
+	bytes, _ := json.Marshal(&SubscriptionPostErrorResponse{
+		Error:   "Not Found",
+		Message: message,
+		Status:  404,
+	})
+	w.Write(bytes)
+}
+
 func handlerResponseErrorConflict(w http.ResponseWriter, message string) {
 	w.WriteHeader(http.StatusConflict)
 	bytes, _ := json.Marshal(&SubscriptionPostErrorResponse{
@@ -542,6 +863,16 @@ func handlerResponseErrorConflict(w http.ResponseWriter, message string) {
 	w.Write(bytes)
 }
 
+func handlerResponseErrorTooManyRequests(w http.ResponseWriter, message string) {
+	w.WriteHeader(http.StatusTooManyRequests)
+	bytes, _ := json.Marshal(&SubscriptionPostErrorResponse{
+		Error:   "Too Many Requests",
+		Message: message,
+		Status:  429,
+	})
+	w.Write(bytes)
+}
+
 func handlerResponseErrorInternalServerError(w http.ResponseWriter, message string) {
 	w.WriteHeader(http.StatusInternalServerError)
 	bytes, _ := json.Marshal(&SubscriptionPostErrorResponse{
@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"github.com/twitchdev/twitch-cli/internal/metrics"
+)
+
+// Metrics served at /metrics, for monitoring long running tests against the server
+var (
+	metricsRegistry = metrics.NewRegistry()
+	httpMetrics     = metrics.NewHTTPMetrics(metricsRegistry)
+
+	notificationsSent = metricsRegistry.Counter("twitch_cli_websocket_notifications_sent_total",
+		"Notifications sent to WebSocket sessions, by subscription type and version.", "subscription_type", "subscription_version")
+	sessionsClosed = metricsRegistry.Counter("twitch_cli_websocket_closes_total",
+		"WebSocket sessions closed, by close code.", "code")
+	keepaliveMisses = metricsRegistry.Counter("twitch_cli_websocket_keepalive_misses_total",
+		"session_keepalive messages not sent on time, by reason: disabled, dropped, or delayed.", "reason")
+)
+
+func init() {
+	metricsRegistry.GaugeFunc("twitch_cli_websocket_sessions", "WebSocket sessions currently connected.", nil, collectSessions)
+	metricsRegistry.GaugeFunc("twitch_cli_eventsub_subscriptions", "EventSub subscriptions, by transport and status.", []string{"transport", "status"}, collectSubscriptions)
+}
+
+// Counts the sessions connected to every server, including servers being restarted. Old connections of sessions that
+// already reconnected aren't counted.
+func collectSessions(set func(float64, ...string)) {
+	sessions := 0
+	if serverManager != nil {
+		for _, server := range serverManager.serverList.All() {
+			server.muClients.Lock()
+			for _, client := range server.Clients.All() {
+				if !client.reconnected {
+					sessions++
+				}
+			}
+			server.muClients.Unlock()
+		}
+	}
+	set(float64(sessions))
+}
+
+// Counts the subscriptions listed by GET /eventsub/subscriptions
+func collectSubscriptions(set func(float64, ...string)) {
+	if serverManager == nil {
+		return
+	}
+	subscriptions, err := listSubscriptions("")
+	if err != nil {
+		return
+	}
+
+	counts := make(map[[2]string]int)
+	for _, s := range subscriptions {
+		counts[[2]string{s.Transport.Method, s.Status}]++
+	}
+	for labels, n := range counts {
+		set(float64(n), labels[0], labels[1])
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+// How often the subscriptions are saved with --persist-subscriptions, when they changed
+const PERSIST_INTERVAL = time.Second
+
+// Subscriptions, pending reconnects and conduits saved by a previous run of the server
+type persistedState struct {
+	serverID             string                    // ID of the primary server when the state was saved
+	subscriptions        map[string][]Subscription // WebSocket subscriptions by client name
+	reconnectClients     map[string][]Subscription // By the session ID being reconnected from
+	webhookSubscriptions []Subscription
+	conduits             []*Conduit
+	conduitSubscriptions []Subscription
+}
+
+// Saves the subscriptions, pending reconnects and conduits every PERSIST_INTERVAL, until stop is closed. done is closed
+// once the loop returns, so the final save can't run at the same time as one from the loop.
+func (sm *ServerManager) persistSubscriptionsLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(PERSIST_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := sm.saveSubscriptions(); err != nil {
+				log.Printf("Could not save WebSocket subscriptions: %v", err)
+			}
+		}
+	}
+}
+
+// Saves the primary server's WebSocket subscriptions and pending reconnects, along with the webhook subscriptions, conduits
+// and conduit subscriptions, unless they're unchanged since the last save
+func (sm *ServerManager) saveSubscriptions() error {
+	server, ok := sm.serverList.Get(sm.primaryServer)
+	if !ok {
+		return nil
+	}
+
+	rows := []database.EventSubWebSocketSubscription{}
+
+	server.muSubscriptions.Lock()
+	for clientName, subscriptions := range server.Subscriptions {
+		for _, s := range subscriptions {
+			rows = append(rows, persistedRow(fmt.Sprintf("%v_%v", server.ServerId, clientName), false, s))
+		}
+	}
+	server.muSubscriptions.Unlock()
+
+	server.muReconnectClients.Lock()
+	for sessionID, subscriptions := range server.ReconnectClients.Elements {
+		for _, s := range *subscriptions {
+			rows = append(rows, persistedRow(sessionID, true, s))
+		}
+	}
+	server.muReconnectClients.Unlock()
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].SessionID != rows[j].SessionID {
+			return rows[i].SessionID < rows[j].SessionID
+		}
+		if rows[i].PendingReconnect != rows[j].PendingReconnect {
+			return !rows[i].PendingReconnect
+		}
+		return rows[i].SubscriptionID < rows[j].SubscriptionID
+	})
+
+	subscriptionRows := []database.EventSubSubscription{}
+	conduitRows := []database.EventSubConduit{}
+
+	sm.muWebhookSubscriptions.Lock()
+	for _, s := range sm.webhookSubscriptions {
+		subscriptionRows = append(subscriptionRows, persistedSubscriptionRow(models.TransportWebhook, s))
+	}
+	sm.muWebhookSubscriptions.Unlock()
+
+	sm.muConduits.Lock()
+	for _, s := range sm.conduitSubscriptions {
+		subscriptionRows = append(subscriptionRows, persistedSubscriptionRow(TRANSPORT_CONDUIT, s))
+	}
+	for _, c := range sm.conduits {
+		j, _ := json.Marshal(c)
+		conduitRows = append(conduitRows, database.EventSubConduit{ConduitID: c.ID, JSON: string(j)})
+	}
+	sm.muConduits.Unlock()
+
+	sort.Slice(subscriptionRows, func(i, j int) bool { return subscriptionRows[i].SubscriptionID < subscriptionRows[j].SubscriptionID })
+	sort.Slice(conduitRows, func(i, j int) bool { return conduitRows[i].ConduitID < conduitRows[j].ConduitID })
+
+	fingerprint := ""
+	for _, r := range rows {
+		fingerprint += "\n" + r.SessionID + "\n" + r.JSON
+	}
+	for _, r := range subscriptionRows {
+		fingerprint += "\n" + r.Transport + "\n" + r.JSON
+	}
+	for _, r := range conduitRows {
+		fingerprint += "\nconduit\n" + r.JSON
+	}
+	if fingerprint == sm.persistedFingerprint {
+		return nil
+	}
+
+	err := sm.db.NewQuery(nil, 100).ReplaceEventSubState(database.EventSubState{
+		WebSocketSubscriptions: rows,
+		Subscriptions:          subscriptionRows,
+		Conduits:               conduitRows,
+	})
+	if err != nil {
+		return err
+	}
+	sm.persistedFingerprint = fingerprint
+
+	return nil
+}
+
+func persistedRow(sessionID string, pendingReconnect bool, s Subscription) database.EventSubWebSocketSubscription {
+	j, _ := json.Marshal(s)
+	return database.EventSubWebSocketSubscription{
+		SessionID:        sessionID,
+		PendingReconnect: pendingReconnect,
+		SubscriptionID:   s.SubscriptionID,
+		JSON:             string(j),
+	}
+}
+
+func persistedSubscriptionRow(transport string, s Subscription) database.EventSubSubscription {
+	j, _ := json.Marshal(s)
+	return database.EventSubSubscription{
+		SubscriptionID: s.SubscriptionID,
+		Transport:      transport,
+		JSON:           string(j),
+	}
+}
+
+// Loads the state saved by a previous run of the server. The returned state is empty if nothing was saved, and has no
+// server ID if no session had subscriptions.
+func (sm *ServerManager) loadSubscriptions() (persistedState, error) {
+	state := persistedState{
+		subscriptions:    make(map[string][]Subscription),
+		reconnectClients: make(map[string][]Subscription),
+	}
+
+	saved, err := sm.db.NewQuery(nil, 100).GetEventSubState()
+	if err != nil {
+		return state, err
+	}
+
+	for _, r := range saved.WebSocketSubscriptions {
+		serverID, clientName, _ := strings.Cut(r.SessionID, "_")
+		if !r.PendingReconnect {
+			state.serverID = serverID
+		}
+
+		var s Subscription
+		if err := json.Unmarshal([]byte(r.JSON), &s); err != nil {
+			return state, fmt.Errorf("Invalid saved subscription [%v]: %v", r.SubscriptionID, err)
+		}
+
+		if r.PendingReconnect {
+			state.reconnectClients[r.SessionID] = append(state.reconnectClients[r.SessionID], s)
+		} else {
+			state.subscriptions[clientName] = append(state.subscriptions[clientName], s)
+		}
+	}
+
+	for _, r := range saved.Subscriptions {
+		var s Subscription
+		if err := json.Unmarshal([]byte(r.JSON), &s); err != nil {
+			return state, fmt.Errorf("Invalid saved subscription [%v]: %v", r.SubscriptionID, err)
+		}
+
+		switch r.Transport {
+		case models.TransportWebhook:
+			state.webhookSubscriptions = append(state.webhookSubscriptions, s)
+		case TRANSPORT_CONDUIT:
+			state.conduitSubscriptions = append(state.conduitSubscriptions, s)
+		default:
+			return state, fmt.Errorf("Invalid transport [%v] for saved subscription [%v]", r.Transport, r.SubscriptionID)
+		}
+	}
+
+	for _, r := range saved.Conduits {
+		c := &Conduit{}
+		if err := json.Unmarshal([]byte(r.JSON), c); err != nil {
+			return state, fmt.Errorf("Invalid saved conduit [%v]: %v", r.ConduitID, err)
+		}
+		state.conduits = append(state.conduits, c)
+	}
+
+	return state, nil
+}
+
+// Restores the saved state to the server, which takes the ID of the saved server so the subscriptions keep their session
+// IDs. None of the sessions are connected anymore, so enabled subscriptions are disabled with websocket_disconnected.
+// Pending reconnects can still be completed within the reconnect grace period.
+func (ws *WebSocketServer) restoreSubscriptions(state persistedState) int {
+	if state.serverID != "" {
+		ws.ServerId = state.serverID
+	}
+
+	tNow := util.GetTimestamp()
+	count := 0
+	for clientName, subscriptions := range state.subscriptions {
+		for i := range subscriptions {
+			if subscriptions[i].Status == STATUS_ENABLED {
+				subscriptions[i].Status = STATUS_WEBSOCKET_DISCONNECTED
+				subscriptions[i].ClientConnectedAt = ""
+				subscriptions[i].ClientDisconnectedAt = tNow.Format(time.RFC3339Nano)
+				subscriptions[i].DisabledAt = &tNow
+			}
+		}
+		ws.Subscriptions[clientName] = subscriptions
+		count += len(subscriptions)
+	}
+
+	for sessionID, subscriptions := range state.reconnectClients {
+		s := subscriptions
+		ws.ReconnectClients.Put(sessionID, &s)
+
+		reconnectID := sessionID
+		time.AfterFunc(RECONNECT_GRACE_SECONDS*time.Second, func() {
+			ws.muReconnectClients.Lock()
+			ws.ReconnectClients.Delete(reconnectID)
+			ws.muReconnectClients.Unlock()
+		})
+	}
+
+	return count
+}
+
+// Restores the saved webhook subscriptions, conduits and conduit subscriptions. They aren't tied to a connection, so they
+// keep their status, except for conduit shards assigned to WebSocket sessions, which were closed with the old process.
+func (sm *ServerManager) restoreTransportSubscriptions(state persistedState) int {
+	disconnectedAt := util.GetTimestamp().Format(time.RFC3339Nano)
+	for _, c := range state.conduits {
+		for i, s := range c.Shards {
+			if s.Method == models.TransportWebSocket && s.Status == STATUS_ENABLED {
+				c.Shards[i].Status = STATUS_WEBSOCKET_DISCONNECTED
+				c.Shards[i].DisconnectedAt = disconnectedAt
+			}
+		}
+	}
+
+	sm.muWebhookSubscriptions.Lock()
+	sm.webhookSubscriptions = append(sm.webhookSubscriptions, state.webhookSubscriptions...)
+	sm.muWebhookSubscriptions.Unlock()
+
+	sm.muConduits.Lock()
+	sm.conduits = append(sm.conduits, state.conduits...)
+	sm.conduitSubscriptions = append(sm.conduitSubscriptions, state.conduitSubscriptions...)
+	sm.muConduits.Unlock()
+
+	return len(state.webhookSubscriptions) + len(state.conduitSubscriptions)
+}
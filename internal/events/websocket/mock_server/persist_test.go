@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestPersistSubscriptions(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 0)
+	defer f.close()
+	db, err := database.NewConnection(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverManager.db = &db
+
+	f.ws.Subscriptions["client"] = []Subscription{{SubscriptionID: "websocket", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED, ClientConnectedAt: "2026-01-01T00:00:00Z"}}
+	f.ws.ReconnectClients.Put("fanout_reconnecting", &[]Subscription{{SubscriptionID: "reconnect", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED}})
+	serverManager.webhookSubscriptions = []Subscription{{SubscriptionID: "webhook", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED, Callback: "https://localhost/callback", Secret: "secretsecret"}}
+	serverManager.conduits = []*Conduit{{
+		ID:       "conduit",
+		ClientID: "clientid",
+		Shards: []Shard{
+			{ID: "0", Status: STATUS_ENABLED, Method: models.TransportWebSocket, SessionID: "fanout_client"},
+			{ID: "1", Status: STATUS_ENABLED, Method: models.TransportWebhook, Callback: "https://localhost/shard", Secret: "secretsecret"},
+		},
+	}}
+	serverManager.conduitSubscriptions = []Subscription{{SubscriptionID: "conduit-subscription", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED, ConduitID: "conduit"}}
+
+	a.Nil(serverManager.saveSubscriptions())
+
+	// Restore to a new server, as when the process is started again
+	sm := &ServerManager{db: &db, webhookSubscriptions: []Subscription{}, conduits: []*Conduit{}, conduitSubscriptions: []Subscription{}}
+	ws := &WebSocketServer{
+		ServerId:         "restarted",
+		Subscriptions:    make(map[string][]Subscription),
+		ReconnectClients: &util.List[[]Subscription]{Elements: make(map[string]*[]Subscription)},
+	}
+	state, err := sm.loadSubscriptions()
+	a.Nil(err)
+	a.Equal(1, ws.restoreSubscriptions(state))
+	a.Equal(2, sm.restoreTransportSubscriptions(state))
+
+	// WebSocket subscriptions keep their session IDs, but their connections were closed
+	a.Equal("fanout", ws.ServerId)
+	a.Len(ws.Subscriptions["client"], 1)
+	a.Equal("websocket", ws.Subscriptions["client"][0].SubscriptionID)
+	a.Equal(STATUS_WEBSOCKET_DISCONNECTED, ws.Subscriptions["client"][0].Status)
+	a.Empty(ws.Subscriptions["client"][0].ClientConnectedAt)
+
+	// Pending reconnects can still be completed
+	reconnect, ok := ws.ReconnectClients.Get("fanout_reconnecting")
+	a.True(ok)
+	a.Len(*reconnect, 1)
+	a.Equal("reconnect", (*reconnect)[0].SubscriptionID)
+
+	// Webhook and conduit subscriptions aren't tied to a connection
+	a.Equal(serverManager.webhookSubscriptions, sm.webhookSubscriptions)
+	a.Equal(serverManager.conduitSubscriptions, sm.conduitSubscriptions)
+
+	// Conduits keep their shards, but shards assigned to sessions are disconnected
+	a.Len(sm.conduits, 1)
+	a.Equal("clientid", sm.conduits[0].ClientID)
+	a.Len(sm.conduits[0].Shards, 2)
+	a.Equal(STATUS_WEBSOCKET_DISCONNECTED, sm.conduits[0].Shards[0].Status)
+	a.Equal("fanout_client", sm.conduits[0].Shards[0].SessionID)
+	a.NotEmpty(sm.conduits[0].Shards[0].DisconnectedAt)
+	a.Equal(serverManager.conduits[0].Shards[1], sm.conduits[0].Shards[1])
+}
+
+func TestPersistSubscriptionsLoop(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 0)
+	defer f.close()
+
+	// The loop signals it returned, so the final save doesn't run at the same time as one of its saves
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go serverManager.persistSubscriptionsLoop(stop, done)
+	close(stop)
+
+	_, open := <-done
+	a.False(open)
+}
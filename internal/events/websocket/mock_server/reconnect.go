@@ -0,0 +1,233 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Default time a client has to reconnect after a session_reconnect message before it's disconnected with 4004
+const RECONNECT_GRACE_SECONDS = 30
+
+// Options for reconnect testing. With only Waves set to 1, the server is restarted, reconnecting every session at once.
+type ReconnectOptions struct {
+	Sessions            []string       // Only reconnects these sessions
+	Percent             float64        // Only reconnects this percentage of sessions, chosen at random; 0 reconnects every session
+	Waves               int            // Number of waves the sessions are reconnected in; Must be at least 1
+	WaveInterval        time.Duration  // Time between waves
+	GracePeriod         *time.Duration // Time sessions have to reconnect before they're closed with 4004; nil uses RECONNECT_GRACE_SECONDS
+	CloseOldConnections bool           // Closes a session's old connection with 4004 as soon as it reconnects, as if the client didn't close it
+}
+
+func (o ReconnectOptions) Validate() error {
+	if o.Percent < 0 || o.Percent > 100 {
+		return fmt.Errorf("The percentage of sessions to reconnect must be between 0 and 100")
+	}
+	if len(o.Sessions) > 0 && o.Percent > 0 {
+		return fmt.Errorf("Reconnects can target either sessions or a percentage of sessions, not both")
+	}
+	if o.Waves < 1 {
+		return fmt.Errorf("The number of waves must be at least 1")
+	}
+	if o.WaveInterval < 0 || o.gracePeriod() < 0 {
+		return fmt.Errorf("The wave interval and grace period can't be negative")
+	}
+	return nil
+}
+
+// Returns whether the reconnect only targets some sessions, or targets them in several waves. Rolling reconnects are sent
+// by the primary server, which keeps running, as when only some of the edge servers are restarted.
+func (o ReconnectOptions) Rolling() bool {
+	return len(o.Sessions) > 0 || (o.Percent > 0 && o.Percent < 100) || o.Waves > 1
+}
+
+func (o ReconnectOptions) gracePeriod() time.Duration {
+	if o.GracePeriod == nil {
+		return RECONNECT_GRACE_SECONDS * time.Second
+	}
+	return *o.GracePeriod
+}
+
+// Close message sent to a session's old connection once it reconnects, or nil to leave it open
+func (o ReconnectOptions) closeOnReconnect() *CloseMessage {
+	if o.CloseOldConnections {
+		return closeReconnectGraceTimeExpired
+	}
+	return nil
+}
+
+// Returns the clients targeted by a rolling reconnect, in a random order
+func (ws *WebSocketServer) selectReconnectClients(o ReconnectOptions) ([]*Client, error) {
+	ws.muClients.Lock()
+	defer ws.muClients.Unlock()
+
+	if len(o.Sessions) > 0 {
+		clients := []*Client{}
+		for _, session := range o.Sessions {
+			clientName := clientNameFromSession(session)
+			client, ok := ws.Clients.Get(clientName)
+			if !ok {
+				return nil, newAdminError(http.StatusNotFound, "Client [%v] does not exist on WebSocket server.", clientName)
+			}
+			clients = append(clients, client)
+		}
+		return clients, nil
+	}
+
+	// Sessions that were already sent a session_reconnect, such as with --chaos-reconnect, aren't reconnected again
+	clients := []*Client{}
+	for _, client := range ws.Clients.All() {
+		if !client.reconnecting {
+			clients = append(clients, client)
+		}
+	}
+	rand.Shuffle(len(clients), func(i, j int) { clients[i], clients[j] = clients[j], clients[i] })
+
+	if o.Percent > 0 {
+		clients = clients[:int(math.Ceil(float64(len(clients))*o.Percent/100))]
+	}
+	if len(clients) == 0 {
+		return nil, newAdminError(http.StatusNotFound, "No sessions to reconnect on WebSocket server.")
+	}
+
+	return clients, nil
+}
+
+// Splits the clients into waves of even size, with later waves taking the remainder. There are never more waves than
+// clients, so no wave is empty.
+func reconnectWaves(clients []*Client, waves int) [][]*Client {
+	if waves > len(clients) {
+		waves = len(clients)
+	}
+
+	split := [][]*Client{}
+	for i := 0; i < waves; i++ {
+		split = append(split, clients[i*len(clients)/waves:(i+1)*len(clients)/waves])
+	}
+	return split
+}
+
+// Sends session_reconnect messages to the clients in waves, spread evenly across o.Waves, and waits until the last
+// wave's grace period expires. Clients that didn't reconnect in time are closed with 4004.
+func (ws *WebSocketServer) RollingRestart(clients []*Client, o ReconnectOptions) {
+	waves := reconnectWaves(clients, o.Waves)
+
+	var wg sync.WaitGroup
+	for i, wave := range waves {
+		if i > 0 {
+			time.Sleep(o.WaveInterval)
+		}
+
+		log.Printf("Sending reconnect notices to wave %v of %v on server [%v] (%v clients)", i+1, len(waves), ws.ServerId, len(wave))
+
+		for _, client := range wave {
+			// Skip clients that disconnected since the reconnect started
+			ws.muClients.Lock()
+			c, ok := ws.Clients.Get(client.clientName)
+			ws.muClients.Unlock()
+			if !ok || c != client {
+				continue
+			}
+
+			ws.sendSessionReconnect(client, o.closeOnReconnect())
+
+			wg.Add(1)
+			reconnectingClient := client
+			time.AfterFunc(o.gracePeriod(), func() {
+				ws.expireSessionReconnect(reconnectingClient)
+				wg.Done()
+			})
+		}
+	}
+
+	log.Printf("Reconnect notices sent to all waves. Will disconnect clients that haven't reconnected in %v...", o.gracePeriod())
+	wg.Wait()
+}
+
+// Sends a session_reconnect message to a single client, as if only its edge server was restarting. The client's
+// subscriptions carry over when it connects to the reconnect URL, which points to the same server. Once it does, the old
+// connection stops receiving events, and is closed with closeOnReconnect unless that's nil.
+func (ws *WebSocketServer) sendSessionReconnect(client *Client, closeOnReconnect *CloseMessage) {
+	sessionID := fmt.Sprintf("%v_%v", ws.ServerId, client.clientName)
+
+	ws.muSubscriptions.Lock()
+	subscriptions := append([]Subscription{}, ws.Subscriptions[client.clientName]...)
+	ws.muSubscriptions.Unlock()
+
+	ws.muReconnectClients.Lock()
+	ws.ReconnectClients.Put(sessionID, &subscriptions)
+	ws.muReconnectClients.Unlock()
+
+	ws.muClients.Lock()
+	if client.keepAliveChanOpen {
+		close(client.keepAliveLoopChan)
+		client.keepAliveChanOpen = false
+	}
+	client.reconnecting = true
+	client.closeOnReconnect = closeOnReconnect
+	ws.muClients.Unlock()
+
+	reconnectMsg, reconnectMetadata := ws.buildReconnectMessage(client)
+	err := client.SendMessage(websocket.TextMessage, reconnectMsg, reconnectMetadata)
+	if err != nil {
+		log.Printf("Error sending session_reconnect to client [%v]: %v", client.clientName, err.Error())
+	}
+}
+
+// Ends a single client's reconnect grace period. The client's subscriptions no longer carry over, and its old connection
+// is closed with 4004 if it's still open.
+func (ws *WebSocketServer) expireSessionReconnect(client *Client) {
+	ws.muReconnectClients.Lock()
+	ws.ReconnectClients.Delete(fmt.Sprintf("%v_%v", ws.ServerId, client.clientName))
+	ws.muReconnectClients.Unlock()
+
+	ws.muClients.Lock()
+	defer ws.muClients.Unlock()
+	if c, ok := ws.Clients.Get(client.clientName); ok && c == client {
+		client.CloseWithReason(closeReconnectGraceTimeExpired)
+		ws.handleClientConnectionClose(client, closeReconnectGraceTimeExpired)
+	}
+}
+
+// Handles the connection a client reconnected from, on this server or the one being restarted, once the client is
+// welcomed on its new connection.
+func closeReconnectedClient(reconnectID string) {
+	matches := sessionRegex.FindStringSubmatch(reconnectID)
+	if matches == nil {
+		return
+	}
+	ws, ok := serverManager.serverList.Get(matches[1])
+	if !ok {
+		return
+	}
+	clientName := matches[2]
+
+	ws.muClients.Lock()
+	defer ws.muClients.Unlock()
+
+	client, ok := ws.Clients.Get(clientName)
+	if !ok || !client.reconnecting {
+		return
+	}
+
+	// The subscriptions were moved to the new session
+	ws.muSubscriptions.Lock()
+	delete(ws.Subscriptions, clientName)
+	ws.subscriptionsChanged()
+	ws.muSubscriptions.Unlock()
+
+	client.reconnected = true
+	if client.closeOnReconnect != nil {
+		log.Printf("Client [%v] reconnected; Closing its old connection with code [%v]", clientName, client.closeOnReconnect.code)
+		client.CloseWithReason(client.closeOnReconnect)
+		ws.handleClientConnectionClose(client, client.closeOnReconnect)
+	}
+}
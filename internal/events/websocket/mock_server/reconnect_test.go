@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestReconnectOptionsValidate(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	a.NoError(ReconnectOptions{Waves: 1}.Validate())
+	a.Equal(RECONNECT_GRACE_SECONDS*time.Second, ReconnectOptions{Waves: 1}.gracePeriod())
+
+	// A grace period of 0 expires reconnects right away, rather than using the default
+	gracePeriod := time.Duration(0)
+	o := ReconnectOptions{Waves: 1, GracePeriod: &gracePeriod}
+	a.NoError(o.Validate())
+	a.Zero(o.gracePeriod())
+
+	a.Error(ReconnectOptions{}.Validate())
+	a.Error(ReconnectOptions{Waves: -1}.Validate())
+	a.Error(ReconnectOptions{Waves: 1, WaveInterval: -time.Second}.Validate())
+	gracePeriod = -time.Second
+	a.Error(ReconnectOptions{Waves: 1, GracePeriod: &gracePeriod}.Validate())
+	a.Error(ReconnectOptions{Waves: 1, Percent: 101}.Validate())
+	a.Error(ReconnectOptions{Waves: 1, Percent: 50, Sessions: []string{"session"}}.Validate())
+}
+
+func TestReconnectWaves(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	clients := []*Client{}
+	for i := 0; i < 5; i++ {
+		clients = append(clients, &Client{clientName: fmt.Sprint(i)})
+	}
+
+	waves := reconnectWaves(clients, 2)
+	a.Len(waves, 2)
+	a.Equal(clients[:2], waves[0])
+	a.Equal(clients[2:], waves[1])
+
+	waves = reconnectWaves(clients, 3)
+	a.Len(waves, 3)
+	a.Equal(clients[:1], waves[0])
+	a.Equal(clients[1:3], waves[1])
+	a.Equal(clients[3:], waves[2])
+
+	// There's never an empty wave
+	waves = reconnectWaves(clients, 10)
+	a.Len(waves, 5)
+	for i, wave := range waves {
+		a.Equal(clients[i:i+1], wave)
+	}
+
+	a.Equal([][]*Client{clients}, reconnectWaves(clients, 1))
+}
+
+func TestSessionReconnectExpiry(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 2)
+	defer f.close()
+	client := getTestClient(f, f.clients[0])
+	sessionID := fmt.Sprintf("%v_%v", f.ws.ServerId, client.clientName)
+
+	f.ws.muSubscriptions.Lock()
+	f.ws.Subscriptions[client.clientName] = []Subscription{{SubscriptionID: "1", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED}}
+	f.ws.subscriptionsChanged()
+	f.ws.muSubscriptions.Unlock()
+
+	// The session's subscriptions carry over to its reconnect URL until the grace period expires
+	f.ws.sendSessionReconnect(client, nil)
+	a.Equal(1, client.MessageCounts()["session_reconnect"])
+	f.ws.muReconnectClients.Lock()
+	subscriptions, ok := f.ws.ReconnectClients.Get(sessionID)
+	f.ws.muReconnectClients.Unlock()
+	a.True(ok)
+	a.Len(*subscriptions, 1)
+	a.Equal("1", (*subscriptions)[0].SubscriptionID)
+
+	f.ws.expireSessionReconnect(client)
+	f.ws.muReconnectClients.Lock()
+	_, ok = f.ws.ReconnectClients.Get(sessionID)
+	f.ws.muReconnectClients.Unlock()
+	a.False(ok)
+	a.Nil(getTestClient(f, f.clients[0]))
+
+	// Other sessions aren't affected
+	a.NotNil(getTestClient(f, f.clients[1]))
+	a.Zero(getTestClient(f, f.clients[1]).MessageCounts()["session_reconnect"])
+}
+
+func TestRollingRestart(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 5)
+	defer f.close()
+	clients := []*Client{}
+	for _, c := range f.clients {
+		clients = append(clients, getTestClient(f, c))
+	}
+
+	// With no grace period, every session is closed as soon as it's sent its session_reconnect
+	gracePeriod := time.Duration(0)
+	f.ws.RollingRestart(clients, ReconnectOptions{Waves: 2, GracePeriod: &gracePeriod})
+
+	for i, client := range clients {
+		a.Equal(1, client.MessageCounts()["session_reconnect"])
+		a.Nil(getTestClient(f, f.clients[i]))
+	}
+	f.ws.muReconnectClients.Lock()
+	a.Empty(f.ws.ReconnectClients.All())
+	f.ws.muReconnectClients.Unlock()
+}
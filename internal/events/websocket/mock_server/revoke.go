@@ -0,0 +1,225 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+// A subscription disabled by a revoked authorization, and where its revocation message is sent
+type revokedSubscription struct {
+	subscription Subscription
+	client       *Client // WebSocket only
+	sessionID    string  // WebSocket only
+	shard        *Shard  // Conduit only; nil if the conduit has no enabled shards
+}
+
+// Handler - POST /admin/authorizations/revoke
+func adminRevokeAuthorizationsHandler(w http.ResponseWriter, r *http.Request) {
+	var body admin.RevokeRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+	if body.ClientID == "" {
+		admin.WriteError(w, http.StatusBadRequest, "The client_id field is required")
+		return
+	}
+
+	revoked := serverManager.revokeAuthorizations(body)
+	admin.WriteJSON(w, fmt.Sprintf("Revoked %v subscriptions", revoked), nil)
+}
+
+// Revokes the subscriptions that relied on revoked authorizations, as production does when a token is revoked or a
+// user disconnects a client from their account:
+//   - WebSocket subscriptions created with a revoked token
+//   - When the user revoked the client, the client's subscriptions made on behalf of the user, by token or condition
+//
+// Revocation messages are sent to each revoked subscription, and user.authorization.revoke to the client's subscriptions
+// to it when a user revoked the client. Returns the number of subscriptions revoked.
+func (sm *ServerManager) revokeAuthorizations(req admin.RevokeRequest) int {
+	tokens := map[string]bool{}
+	for _, t := range req.Tokens {
+		tokens[t] = true
+	}
+
+	// Whether the user revoked the client, and the subscription was made on their behalf
+	revokedByUser := func(sub Subscription) bool {
+		if req.UserID == "" || sub.ClientID != req.ClientID {
+			return false
+		}
+		if sub.UserID == req.UserID {
+			return true
+		}
+		for _, userID := range conditionUserIDs(sub.Conditions) {
+			if userID == req.UserID {
+				return true
+			}
+		}
+		return false
+	}
+
+	revoke := func(subs []Subscription, i int) {
+		subs[i].Status = STATUS_AUTHORIZATION_REVOKED
+		tNow := util.GetTimestamp()
+		subs[i].DisabledAt = &tNow
+	}
+
+	revoked := []revokedSubscription{}
+
+	for _, server := range sm.serverList.All() {
+		// Clients are looked up while the subscriptions are changed, so both are locked
+		server.muClients.Lock()
+		server.muSubscriptions.Lock()
+		changed := false
+		for clientName, clientSubscriptions := range server.Subscriptions {
+			for i, sub := range clientSubscriptions {
+				if sub.Status != STATUS_ENABLED || (!tokens[sub.Token] && !revokedByUser(sub)) {
+					continue
+				}
+
+				revoke(clientSubscriptions, i)
+				changed = true
+
+				r := revokedSubscription{subscription: clientSubscriptions[i], sessionID: fmt.Sprintf("%v_%v", server.ServerId, clientName)}
+				if client, ok := server.Clients.Get(clientName); ok && !client.reconnected {
+					r.client = client
+				}
+				revoked = append(revoked, r)
+			}
+		}
+		if changed {
+			server.subscriptionsChanged()
+		}
+		server.muSubscriptions.Unlock()
+		server.muClients.Unlock()
+	}
+
+	sm.muWebhookSubscriptions.Lock()
+	for i, sub := range sm.webhookSubscriptions {
+		if sub.Status == STATUS_ENABLED && revokedByUser(sub) {
+			revoke(sm.webhookSubscriptions, i)
+			revoked = append(revoked, revokedSubscription{subscription: sm.webhookSubscriptions[i]})
+		}
+	}
+	sm.muWebhookSubscriptions.Unlock()
+
+	sm.muConduits.Lock()
+	for i, sub := range sm.conduitSubscriptions {
+		if sub.Status == STATUS_ENABLED && revokedByUser(sub) {
+			revoke(sm.conduitSubscriptions, i)
+
+			r := revokedSubscription{subscription: sm.conduitSubscriptions[i]}
+			if conduit, ok := sm.getConduit(sub.ClientID, sub.ConduitID); ok {
+				if shard, ok := conduit.nextEnabledShard(); ok {
+					r.shard = &shard
+				}
+			}
+			revoked = append(revoked, r)
+		}
+	}
+	sm.muConduits.Unlock()
+
+	// Revocations are sent after the locks are released
+	for _, r := range revoked {
+		log.Printf("Subscription [%v] to [%v / %v], belonging to client ID [%v], has been revoked.", r.subscription.SubscriptionID, r.subscription.Type, r.subscription.Version, r.subscription.ClientID)
+		if err := sm.sendRevocation(r); err != nil {
+			log.Printf("Failed to send revocation of subscription [%v]: %v", r.subscription.SubscriptionID, err)
+		}
+	}
+
+	if req.UserID != "" {
+		sm.emitServerEvent(sm.authorizationRevokeEvent(req.ClientID, req.UserID))
+	}
+
+	return len(revoked)
+}
+
+// Sends a revocation message for the subscription over its transport
+func (sm *ServerManager) sendRevocation(r revokedSubscription) error {
+	payload := models.EventsubResponse{Subscription: r.subscription.toEventsubSubscription()}
+	messageID := util.RandomGUID()
+	messageTimestamp := util.GetTimestamp().Format(time.RFC3339Nano)
+
+	if r.sessionID != "" {
+		if r.client == nil {
+			// The subscription was revoked, but there's no connection left to tell
+			return nil
+		}
+		payload.Subscription.Transport = models.EventsubTransport{
+			Method:    models.TransportWebSocket,
+			SessionID: r.sessionID,
+		}
+		return sendNotification(r.client, trigger.EventSubMessageTypeRevocation, messageID, messageTimestamp, payload)
+	}
+
+	callback, secret := r.subscription.Callback, r.subscription.Secret
+	if r.subscription.ConduitID != "" {
+		if r.shard == nil {
+			return fmt.Errorf("Conduit [%v] has no enabled shards", r.subscription.ConduitID)
+		}
+		if r.shard.Method != models.TransportWebhook {
+			_, client, ok := getClientBySession(r.shard.SessionID)
+			if !ok {
+				return fmt.Errorf("Session [%v] is not connected", r.shard.SessionID)
+			}
+			return sendNotification(client, trigger.EventSubMessageTypeRevocation, messageID, messageTimestamp, payload)
+		}
+		callback, secret = r.shard.Callback, r.shard.Secret
+	}
+
+	body, _ := json.Marshal(payload)
+	resp, err := trigger.ForwardEvent(trigger.ForwardParamters{
+		ID:                  messageID,
+		ForwardAddress:      callback,
+		JSON:                body,
+		Transport:           models.TransportWebhook,
+		Timestamp:           messageTimestamp,
+		Secret:              secret,
+		Event:               r.subscription.Type,
+		Type:                trigger.EventSubMessageTypeRevocation,
+		SubscriptionVersion: r.subscription.Version,
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Builds the user.authorization.revoke event sent when a user revokes a client. The user's login and name are only
+// included if the user exists in the mock API database.
+func (sm *ServerManager) authorizationRevokeEvent(clientID string, userID string) models.AuthorizationRevokeEventSubResponse {
+	event := &models.AuthorizationRevokeEvent{
+		ClientID: clientID,
+		UserID:   userID,
+	}
+	if sm.db != nil {
+		user, err := sm.db.NewQuery(nil, 100).GetUser(database.User{ID: userID})
+		if err == nil && user.ID != "" {
+			event.UserLogin = user.UserLogin
+			event.UserName = user.DisplayName
+		}
+	}
+
+	return models.AuthorizationRevokeEventSubResponse{
+		Subscription: models.EventsubSubscription{
+			Type:    "user.authorization.revoke",
+			Version: "1",
+			Status:  STATUS_ENABLED,
+			Condition: models.EventsubCondition{
+				ClientID: clientID,
+			},
+		},
+		Event: event,
+	}
+}
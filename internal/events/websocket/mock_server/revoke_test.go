@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestRevokeAuthorizations(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 3)
+	defer f.close()
+
+	webhookMessages := make(chan string, 10)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookMessages <- r.Header.Get("Twitch-Eventsub-Message-Type")
+	}))
+	defer callback.Close()
+
+	conduitMessages := make(chan string, 10)
+	shardCallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conduitMessages <- r.Header.Get("Twitch-Eventsub-Subscription-Type")
+	}))
+	defer shardCallback.Close()
+
+	f.ws.muSubscriptions.Lock()
+	f.ws.Subscriptions[f.clients[0].name] = []Subscription{{
+		SubscriptionID: "user_token", ClientID: "client", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED,
+		Token: "token1", UserID: "1", Conditions: models.EventsubCondition{BroadcasterUserID: "1", ModeratorUserID: "1"},
+	}}
+	f.ws.Subscriptions[f.clients[1].name] = []Subscription{{
+		SubscriptionID: "other_token", ClientID: "client", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED,
+		Token: "token2", UserID: "3", Conditions: models.EventsubCondition{BroadcasterUserID: "2", ModeratorUserID: "3"},
+	}}
+	f.ws.Subscriptions[f.clients[2].name] = []Subscription{{
+		SubscriptionID: "revoke", ClientID: "client", Type: "user.authorization.revoke", Version: "1", Status: STATUS_ENABLED,
+		Token: "token3", UserID: "3", Conditions: models.EventsubCondition{ClientID: "client"},
+	}}
+	f.ws.subscriptionsChanged()
+	f.ws.muSubscriptions.Unlock()
+
+	serverManager.webhookSubscriptions = []Subscription{{
+		SubscriptionID: "webhook", ClientID: "client", Type: "channel.update", Version: "2", Status: STATUS_ENABLED,
+		Callback: callback.URL, Secret: "0123456789", Conditions: models.EventsubCondition{BroadcasterUserID: "2"},
+	}}
+
+	conduit := &Conduit{ID: "conduit", ClientID: "client"}
+	conduit.resize(1)
+	conduit.Shards[0] = Shard{ID: "0", Status: STATUS_ENABLED, Method: models.TransportWebhook, Callback: shardCallback.URL, Secret: "0123456789"}
+	serverManager.conduits = []*Conduit{conduit}
+	serverManager.conduitSubscriptions = []Subscription{{
+		SubscriptionID: "conduit_revoke", ClientID: "client", Type: "user.authorization.revoke", Version: "1", Status: STATUS_ENABLED,
+		ConduitID: "conduit", Conditions: models.EventsubCondition{ClientID: "client"},
+	}}
+
+	status := func(clientName string) string {
+		f.ws.muSubscriptions.Lock()
+		defer f.ws.muSubscriptions.Unlock()
+		return f.ws.Subscriptions[clientName][0].Status
+	}
+
+	// Revoking a token revokes the WebSocket subscriptions created with it
+	f.delivered.Add(1)
+	a.Equal(1, serverManager.revokeAuthorizations(admin.RevokeRequest{ClientID: "client", Tokens: []string{"token1"}}))
+	f.delivered.Wait()
+	a.Len(f.clients[0].revocations, 1)
+	a.Equal(STATUS_AUTHORIZATION_REVOKED, status(f.clients[0].name))
+	a.Equal(STATUS_ENABLED, status(f.clients[1].name))
+
+	// When a user revokes the client, subscriptions with the user in their condition are revoked as well, and
+	// user.authorization.revoke is sent
+	f.delivered.Add(2)
+	a.Equal(2, serverManager.revokeAuthorizations(admin.RevokeRequest{ClientID: "client", UserID: "2", Tokens: []string{}}))
+	f.delivered.Wait()
+	a.Len(f.clients[1].revocations, 1)
+	a.Equal(STATUS_AUTHORIZATION_REVOKED, status(f.clients[1].name))
+	a.Equal(STATUS_AUTHORIZATION_REVOKED, serverManager.webhookSubscriptions[0].Status)
+	a.Equal("revocation", <-webhookMessages)
+	a.Len(f.clients[2].notifications, 1)
+	a.Equal(STATUS_ENABLED, status(f.clients[2].name))
+	select {
+	case subscriptionType := <-conduitMessages:
+		a.Equal("user.authorization.revoke", subscriptionType)
+	case <-time.After(time.Second):
+		t.Fatal("user.authorization.revoke wasn't sent to the conduit")
+	}
+	a.Equal(STATUS_ENABLED, serverManager.conduitSubscriptions[0].Status)
+
+	// Already revoked subscriptions aren't revoked again
+	a.Equal(0, serverManager.revokeAuthorizations(admin.RevokeRequest{ClientID: "client", Tokens: []string{"token1"}}))
+}
@@ -3,6 +3,7 @@ package mock_server
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +13,8 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/events/websocket/transcript"
 	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/internal/util"
 )
@@ -19,6 +22,12 @@ import (
 // Minimum time between messages before the server disconnects a client.
 const KEEPALIVE_TIMEOUT_SECONDS = 10
 
+// Events delivered to more clients than this are logged with a single line, rather than one for each client
+const MAX_LOGGED_DELIVERIES = 10
+
+// Maximum number of clients listed when the server's connections change
+const MAX_LOGGED_CONNECTIONS = 100
+
 type WebSocketServer struct {
 	ServerId     string // Int representing the ID of the server
 	DebugEnabled bool   // Display debug messages; --debug
@@ -32,8 +41,9 @@ type WebSocketServer struct {
 	Status   int        // 0 = shut down; 1 = shutting down; 2 = online
 	muStatus sync.Mutex // Mutex for WebSocketServer.Status
 
-	Subscriptions   map[string][]Subscription // Active subscriptions on this server -- Accessed via Subscriptions[clientName]
-	muSubscriptions sync.Mutex                // Mutex for WebSocketServer.Subscriptions
+	Subscriptions     map[string][]Subscription // Active subscriptions on this server -- Accessed via Subscriptions[clientName]
+	subscriptionIndex *subscriptionIndex        // Index of the enabled Subscriptions; nil when it needs rebuilding
+	muSubscriptions   sync.Mutex                // Mutex for WebSocketServer.Subscriptions and WebSocketServer.subscriptionIndex
 
 	ReconnectClients   *util.List[[]Subscription] // Clients that were part of the last server
 	muReconnectClients sync.Mutex                 // Mutex for WebSocketServer.ReconnectClients
@@ -76,6 +86,22 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 		pingChanOpen:         false,
 	}
 
+	// Messages are written by the client's writer goroutine, so events can be queued for many clients without waiting on
+	// each of them. Clients that fall too far behind are disconnected.
+	client.onSendQueueFull = func() { ws.disconnectSlowClient(client) }
+	client.startWriter()
+	defer client.stopWriter()
+
+	// Record the session's frames with --record-dir
+	if serverManager.recordDir != "" {
+		client.transcript, err = transcript.NewWriter(serverManager.recordDir, fmt.Sprintf("%v_%v", ws.ServerId, client.clientName))
+		if err != nil {
+			log.Printf("Could not record transcript for client [%v]: %v", client.clientName, err)
+		}
+		defer client.transcript.Close()
+	}
+
+	reconnectedFrom := "" // Session the client reconnected from, if any
 	if r.URL.Query().Get("reconnect_id") != "" {
 		reconnectIdBytes, err := base64.StdEncoding.DecodeString(r.URL.Query().Get("reconnect_id") + "=")
 		if err != nil {
@@ -89,11 +115,18 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 
 			subscriptions, ok := ws.ReconnectClients.Get(reconnectId)
 			if ok { // User had subscriptions carry over
+				ws.muSubscriptions.Lock()
 				ws.Subscriptions[client.clientName] = *subscriptions
+				ws.subscriptionsChanged()
+				ws.muSubscriptions.Unlock()
 			}
+			reconnectedFrom = reconnectId
 
 			ws.ReconnectClients.Delete(reconnectId)
 
+			// Conduit shards follow the session to the new server
+			serverManager.moveConduitShards(reconnectId, fmt.Sprintf("%v_%v", ws.ServerId, client.clientName), connectedAtTimestamp)
+
 			if ws.DebugEnabled {
 				log.Printf("Reconnected client [%v] was assigned %v subscriptions", client.clientName, len(ws.Subscriptions[client.clientName]))
 			}
@@ -114,7 +147,14 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// TODO: Check if user is connected to the old server, and if they are then disconnect them from old server with close frame 4004
+	// Set up the timers and loop channels before the client is listed, as reconnects and restarts stop them
+	client.mustSubscribeTimer = time.NewTimer(10 * time.Second)
+	client.keepAliveTimer = time.NewTicker(keepalive_duration)
+	client.pingTimer = time.NewTicker(5 * time.Second)
+	client.keepAliveLoopChan = make(chan struct{})
+	client.pingLoopChan = make(chan struct{})
+	client.keepAliveChanOpen = true
+	client.pingChanOpen = true
 
 	ws.muClients.Lock()
 	// Add to the client connections list
@@ -129,13 +169,14 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 	ws.printConnections()
 
 	// Send welcome message
+	welcomeMetadata := MessageMetadata{
+		MessageID:        util.RandomGUID(),
+		MessageType:      "session_welcome",
+		MessageTimestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}
 	welcomeMsg, _ := json.Marshal(
 		WelcomeMessage{
-			Metadata: MessageMetadata{
-				MessageID:        util.RandomGUID(),
-				MessageType:      "session_welcome",
-				MessageTimestamp: time.Now().UTC().Format(time.RFC3339Nano),
-			},
+			Metadata: welcomeMetadata,
 			Payload: WelcomeMessagePayload{
 				Session: WelcomeMessagePayloadSession{
 					ID:                      fmt.Sprintf("%v_%v", ws.ServerId, client.clientName),
@@ -147,15 +188,20 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 			},
 		},
 	)
-	client.SendMessage(websocket.TextMessage, welcomeMsg)
+	client.SendMessage(websocket.TextMessage, welcomeMsg, welcomeMetadata)
+
+	// Stop sending events to the connection the client reconnected from, and close it with --close-old-connections
+	if reconnectedFrom != "" {
+		closeReconnectedClient(reconnectedFrom)
+	}
 
 	// Check if any subscriptions are sent after 10 seconds.
 	// Strict mode only
-	client.mustSubscribeTimer = time.NewTimer(10 * time.Second)
 	if ws.StrictMode {
 		go func() {
 			<-client.mustSubscribeTimer.C
-			if len(ws.Subscriptions[client.clientName]) == 0 {
+			// Sessions assigned to a conduit shard don't need their own subscriptions
+			if len(ws.Subscriptions[client.clientName]) == 0 && !serverManager.isConduitShardSession(fmt.Sprintf("%v_%v", ws.ServerId, client.clientName)) {
 				client.CloseWithReason(closeConnectionUnused)
 				ws.handleClientConnectionClose(client, closeConnectionUnused)
 
@@ -164,16 +210,9 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 		}()
 	}
 
-	// Set up ping/pong and keepalive handling
-	client.keepAliveTimer = time.NewTicker(keepalive_duration)
-	client.pingTimer = time.NewTicker(5 * time.Second)
-	client.keepAliveLoopChan = make(chan struct{})
-	client.pingLoopChan = make(chan struct{})
-	client.keepAliveChanOpen = true
-	client.pingChanOpen = true
-
 	// Set pong handler. Resets the read deadline when pong is received.
-	conn.SetPongHandler(func(string) error {
+	conn.SetPongHandler(func(appData string) error {
+		client.recordReceived(websocket.PongMessage, []byte(appData))
 		conn.SetReadDeadline(time.Now().Add(time.Second * KEEPALIVE_TIMEOUT_SECONDS))
 		return nil
 	})
@@ -190,23 +229,36 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 			case <-client.keepAliveTimer.C: // Send KeepAlive message
 				if !client.KeepAliveEnabled {
 					// Sending keep alives was disabled manually, so we skip this one.
+					keepaliveMisses.Inc("disabled")
 					if ws.DebugEnabled {
 						log.Printf("Skipped sending session_keepalive to client [%s]", client.clientName)
 					}
 					continue
 				}
 
+				keepAliveMetadata := MessageMetadata{
+					MessageID:        util.RandomGUID(),
+					MessageType:      "session_keepalive",
+					MessageTimestamp: time.Now().UTC().Format(time.RFC3339Nano),
+				}
 				keepAliveMsg, _ := json.Marshal(
 					KeepaliveMessage{
-						Metadata: MessageMetadata{
-							MessageID:        util.RandomGUID(),
-							MessageType:      "session_keepalive",
-							MessageTimestamp: time.Now().UTC().Format(time.RFC3339Nano),
-						},
-						Payload: KeepaliveMessagePayload{},
+						Metadata: keepAliveMetadata,
+						Payload:  KeepaliveMessagePayload{},
 					},
 				)
-				err := client.SendMessage(websocket.TextMessage, keepAliveMsg)
+
+				// Network failures injected with --chaos-* flags
+				send, stop := ws.applyKeepaliveChaos(client, keepAliveMsg, keepAliveMetadata)
+				if stop {
+					client.keepAliveTimer.Stop()
+					return
+				}
+				if !send {
+					continue
+				}
+
+				err := client.SendMessage(websocket.TextMessage, keepAliveMsg, keepAliveMetadata)
 				if err != nil {
 					client.CloseWithReason(closeNetworkError)
 				}
@@ -228,7 +280,7 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 				return
 
 			case <-client.pingTimer.C: // Send ping
-				err := client.SendMessage(websocket.PingMessage, []byte{})
+				err := client.SendMessage(websocket.PingMessage, []byte{}, MessageMetadata{})
 				if err != nil {
 					ws.muClients.Lock()
 					client.CloseWithReason(closeClientFailedPingPong)
@@ -250,12 +302,27 @@ func (ws *WebSocketServer) WsPageHandler(w http.ResponseWriter, r *http.Request)
 		client.conn.SetReadDeadline(time.Now().Add(time.Second * KEEPALIVE_TIMEOUT_SECONDS))
 
 		mt, message, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			client.transcript.Record(transcript.Entry{
+				Direction:   transcript.DirectionReceived,
+				Frame:       transcript.FrameClose,
+				CloseCode:   closeErr.Code,
+				CloseReason: closeErr.Text,
+			})
+		} else if err == nil {
+			client.recordReceived(mt, message)
+		}
+
 		if err != nil && ws.Status != 0 { // If server is shut down, clients should already be disconnectd.
 			log.Printf("read err [%v]: %v", client.clientName, err)
 
 			ws.muClients.Lock()
-			client.CloseWithReason(closeClientDisconnected)
-			ws.handleClientConnectionClose(client, closeClientDisconnected)
+			// The server may have already closed the connection, such as with the close command
+			if c, ok := ws.Clients.Get(client.clientName); ok && c == client {
+				client.CloseWithReason(closeClientDisconnected)
+				ws.handleClientConnectionClose(client, closeClientDisconnected)
+			}
 			ws.muClients.Unlock()
 			break
 		}
@@ -301,7 +368,9 @@ func (ws *WebSocketServer) GetCurrentSubscriptionsForReconnect() *util.List[[]Su
 	return reconnectClients
 }
 
-func (ws *WebSocketServer) InitiateRestart() {
+// Restarts the server, sending session_reconnect messages to every client, and disconnects the clients still connected
+// once the grace period expires
+func (ws *WebSocketServer) InitiateRestart(o ReconnectOptions) {
 	// Set status to shutting down; Stop accepting new clients
 	ws.muStatus.Lock()
 	ws.Status = 1
@@ -319,51 +388,23 @@ func (ws *WebSocketServer) InitiateRestart() {
 		close(client.keepAliveLoopChan)
 		client.keepAliveChanOpen = false
 		client.mustSubscribeTimer.Stop()
+		client.reconnecting = true
+		client.closeOnReconnect = o.closeOnReconnect()
 
 		// Send reconnect notice
-		sessionId := fmt.Sprintf("%v_%v", ws.ServerId, client.clientName)
-		reconnectId := base64.StdEncoding.EncodeToString([]byte(sessionId))
-		reconnectId = reconnectId[:len(reconnectId)-1]
-		clientConnectionUrl := strings.Replace(client.connectionUrl, "http://", "ws://", -1)
-		clientConnectionUrl = strings.Replace(clientConnectionUrl, "https://", "wss://", -1)
-		var reconnecturl string
-		if client.keepAliveSeconds != KEEPALIVE_TIMEOUT_SECONDS {
-			reconnecturl = fmt.Sprintf("%v?reconnect_id=%v&keepalive_timeout_seconds=%d", clientConnectionUrl, reconnectId, client.keepAliveSeconds)
-		} else {
-			reconnecturl = fmt.Sprintf("%v?reconnect_id=%v", clientConnectionUrl, reconnectId)
-		}
-		reconnectMsg, _ := json.Marshal(
-			ReconnectMessage{
-				Metadata: MessageMetadata{
-					MessageID:        util.RandomGUID(),
-					MessageType:      "session_reconnect",
-					MessageTimestamp: time.Now().UTC().Format(time.RFC3339Nano),
-				},
-				Payload: ReconnectMessagePayload{
-					Session: ReconnectMessagePayloadSession{
-						ID:                      sessionId,
-						Status:                  "reconnecting",
-						KeepaliveTimeoutSeconds: nil,
-						ReconnectUrl:            reconnecturl,
-						ConnectedAt:             client.ConnectedAtTimestamp,
-					},
-				},
-			},
-		)
-
-		err := client.SendMessage(websocket.TextMessage, reconnectMsg)
+		reconnectMsg, reconnectMetadata := ws.buildReconnectMessage(client)
+		err := client.SendMessage(websocket.TextMessage, reconnectMsg, reconnectMetadata)
 		if err != nil {
 			log.Printf("Error building session_reconnect JSON for client [%v]: %v", client.clientName, err.Error())
 		}
 	}
 
 	log.Printf("Reconnect notices sent for server [%v]", ws.ServerId)
-	log.Printf("Will disconnect all existing clients in 30 seconds...")
+	log.Printf("Will disconnect all existing clients in %v...", o.gracePeriod())
 
 	ws.muClients.Unlock()
 
-	// Wait 30 seconds
-	time.Sleep(30 * time.Second)
+	time.Sleep(o.gracePeriod())
 
 	// Change server status to 0
 	// This is done before disconnects because the read loop will err out due to the close message, which gets printed unless this is zero.
@@ -380,18 +421,93 @@ func (ws *WebSocketServer) InitiateRestart() {
 	log.Printf("All users disconnected from server [%v]", ws.ServerId)
 }
 
-func (ws *WebSocketServer) HandleRPCEventSubForwarding(eventsubBody string, clientName string) (bool, string) {
+// Builds a session_reconnect message with a reconnect URL the client's subscriptions carry over to. Returns the message
+// and its metadata.
+func (ws *WebSocketServer) buildReconnectMessage(client *Client) ([]byte, MessageMetadata) {
+	sessionId := fmt.Sprintf("%v_%v", ws.ServerId, client.clientName)
+	reconnectId := base64.StdEncoding.EncodeToString([]byte(sessionId))
+	reconnectId = reconnectId[:len(reconnectId)-1]
+	clientConnectionUrl := strings.Replace(client.connectionUrl, "http://", "ws://", -1)
+	clientConnectionUrl = strings.Replace(clientConnectionUrl, "https://", "wss://", -1)
+	var reconnecturl string
+	if client.keepAliveSeconds != KEEPALIVE_TIMEOUT_SECONDS {
+		reconnecturl = fmt.Sprintf("%v?reconnect_id=%v&keepalive_timeout_seconds=%d", clientConnectionUrl, reconnectId, client.keepAliveSeconds)
+	} else {
+		reconnecturl = fmt.Sprintf("%v?reconnect_id=%v", clientConnectionUrl, reconnectId)
+	}
+	metadata := MessageMetadata{
+		MessageID:        util.RandomGUID(),
+		MessageType:      "session_reconnect",
+		MessageTimestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	reconnectMsg, _ := json.Marshal(
+		ReconnectMessage{
+			Metadata: metadata,
+			Payload: ReconnectMessagePayload{
+				Session: ReconnectMessagePayloadSession{
+					ID:                      sessionId,
+					Status:                  "reconnecting",
+					KeepaliveTimeoutSeconds: nil,
+					ReconnectUrl:            reconnecturl,
+					ConnectedAt:             client.ConnectedAtTimestamp,
+				},
+			},
+		},
+	)
+	return reconnectMsg, metadata
+}
+
+// Returns the client's enabled subscription matching the topic, version, and condition of the event, if any, and whether
+// the client has any enabled subscriptions to the topic and version
+func (ws *WebSocketServer) findMatchingSubscription(clientName string, event models.EventsubSubscription) (*Subscription, bool) {
+	idx := ws.getSubscriptionIndex()
+	if match, ok := idx.match(event)[clientName]; ok {
+		sub := match.subscription
+		return &sub, true
+	}
+
+	return nil, idx.hasTopicSubscription(clientName, event)
+}
+
+// Sets the status of a client's enabled subscriptions matching the topic, version, and condition of the event
+func (ws *WebSocketServer) disableSubscriptions(clientName string, event models.EventsubSubscription, status string) {
+	ws.muSubscriptions.Lock()
+	defer ws.muSubscriptions.Unlock()
+
+	for i, sub := range ws.Subscriptions[clientName] {
+		if sub.Type == event.Type && sub.Version == event.Version && sub.Status == STATUS_ENABLED && types.ConditionMatches(sub.Conditions, event.Condition) {
+			ws.Subscriptions[clientName][i].Status = status
+			tNow := util.GetTimestamp()
+			ws.Subscriptions[clientName][i].DisabledAt = &tNow
+		}
+	}
+	ws.subscriptionsChanged()
+}
+
+// Sends an EventSub notification to connected clients. messageID and messageTimestamp are generated when empty.
+func (ws *WebSocketServer) HandleEventSubForwarding(eventsubBody string, clientName string, messageID string, messageTimestamp string) (bool, string) {
+	ws.muClients.Lock()
+	clients := ws.Clients.All()
+	ws.muClients.Unlock()
+
 	// If --session is used, make sure the client exists
 	if clientName != "" {
-		_, ok := ws.Clients.Get(strings.ToLower(clientName))
-		if !ok {
+		found := false
+		for _, client := range clients {
+			if strings.EqualFold(clientName, client.clientName) {
+				clients = []*Client{client}
+				found = true
+				break
+			}
+		}
+		if !found {
 			msg := fmt.Sprintf("Error executing remote triggered EventSub: Client [%v] does not exist on server [%v]", clientName, ws.ServerId)
 			log.Println(msg)
 			return false, msg
 		}
 	}
 
-	if ws.Clients.Length() == 0 {
+	if len(clients) == 0 {
 		msg := fmt.Sprintf("Warning for remote triggered EventSub: No clients in server [%v]", ws.ServerId)
 		log.Println(msg)
 		return false, msg
@@ -406,114 +522,145 @@ func (ws *WebSocketServer) HandleRPCEventSubForwarding(eventsubBody string, clie
 		return false, msg
 	}
 
-	didSend := false
-
-	for _, client := range ws.Clients.All() {
-		if clientName != "" && !strings.EqualFold(strings.ToLower(clientName), client.clientName) {
-			// When --session is used, only send to that client
-			continue
+	// If this is a Revocation message (user.authorization.revoke), set it as revoked
+	if eventObj.Subscription.Type == "user.authorization.revoke" {
+		if serverManager.debugEnabled {
+			log.Printf("Attempting to revoke subscription [%v]", eventObj.Subscription.ID)
 		}
 
-		// If this is a Revocation message (user.authorization.revoke), set it as revoked
-		if eventObj.Subscription.Type == "user.authorization.revoke" {
-			if serverManager.debugEnabled {
-				log.Printf("Attempting to revoke subscription [%v]", eventObj.Subscription.ID)
+		ws.muSubscriptions.Lock()
+		foundClientId := ""
+		for client, clientSubscriptions := range ws.Subscriptions {
+			if foundClientId != "" {
+				break
 			}
 
-			ws.muSubscriptions.Lock()
-			foundClientId := ""
-			for client, clientSubscriptions := range ws.Subscriptions {
-				if foundClientId != "" {
+			for i, sub := range clientSubscriptions {
+				if sub.SubscriptionID == eventObj.Subscription.ID {
+					foundClientId = sub.ClientID
+
+					ws.Subscriptions[client][i].Status = STATUS_AUTHORIZATION_REVOKED
+					tNow := util.GetTimestamp()
+					ws.Subscriptions[client][i].DisabledAt = &tNow
+					ws.subscriptionsChanged()
 					break
 				}
+			}
+		}
+		ws.muSubscriptions.Unlock()
 
-				for i, sub := range clientSubscriptions {
-					if sub.SubscriptionID == eventObj.Subscription.ID {
-						foundClientId = sub.ClientID
+		if foundClientId != "" {
+			log.Printf("Subscription ID [%v], belonging to Client ID [%v], has been revoked.", eventObj.Subscription.ID, foundClientId)
+		} else {
+			msg := fmt.Sprintf("Failed to revoke Subscription ID [%v]: Subscription by that ID does not exist.", eventObj.Subscription.ID)
+			log.Println(msg)
+			return false, msg
+		}
+	}
 
-						ws.Subscriptions[client][i].Status = STATUS_AUTHORIZATION_REVOKED
-						tNow := util.GetTimestamp()
-						ws.Subscriptions[client][i].DisabledAt = &tNow
-						break
-					}
-				}
-			}
-			ws.muSubscriptions.Unlock()
+	// Find the clients' subscriptions to the event with a single lookup. Events are only delivered to subscriptions whose
+	// condition matches, so a client subscribed to one broadcaster doesn't receive events for another
+	idx := ws.getSubscriptionIndex()
+	matches := idx.match(eventObj.Subscription)
 
-			if foundClientId != "" {
-				log.Printf("Subscription ID [%v], belonging to Client ID [%v], has been revoked.", eventObj.Subscription.ID, foundClientId)
-			} else {
-				msg := fmt.Sprintf("Failed to revoke Subscription ID [%v]: Subscription by that ID does not exist.", eventObj.Subscription.ID)
-				log.Println(msg)
-				return false, msg
+	if ws.StrictMode {
+		// With --require-subscription, only clients with a matching subscription receive the event
+		matched := make([]*Client, 0, len(matches))
+		for _, client := range clients {
+			if _, ok := matches[client.clientName]; ok {
+				matched = append(matched, client)
 			}
 		}
+		clients = matched
+	}
 
-		// Check for subscriptions when running with --require-subscription
-		subscriptionCreatedAtTimestamp := "" // Used below if in strict mode
-		if ws.StrictMode {
-			found := false
-			for subscriptionClientName, clientSubscriptions := range ws.Subscriptions {
-				if found {
-					break
-				}
+	notificationID := messageID
+	if notificationID == "" {
+		notificationID = util.RandomGUID()
+	}
+	notificationTimestamp := messageTimestamp
+	if notificationTimestamp == "" {
+		notificationTimestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
 
-				for _, sub := range clientSubscriptions {
-					if subscriptionClientName == client.clientName && sub.Type == eventObj.Subscription.Type && sub.Version == eventObj.Subscription.Version {
-						found = true
-						subscriptionCreatedAtTimestamp = sub.CreatedAt
-					}
-				}
-			}
+	// Non-enabled statuses are revocations, which disable the client's subscription to the topic
+	messageType := "notification"
+	if eventObj.Subscription.Status != "" && eventObj.Subscription.Status != STATUS_ENABLED {
+		messageType = "revocation"
+	}
 
-			if !found {
-				continue
-			}
+	sent := 0
+	for _, client := range clients {
+		if client.reconnected {
+			// Events go to the client's new connection
+			continue
+		}
+
+		clientEventObj := eventObj
+		var subscription *Subscription
+		if match, ok := matches[client.clientName]; ok {
+			subscription = &match.subscription
+		} else if idx.hasTopicSubscription(client.clientName, eventObj.Subscription) {
+			// Without --require-subscription, clients with no subscriptions to the topic receive every event
+			continue
+		} else if serverManager.isConduitShardSession(fmt.Sprintf("%v_%v", ws.ServerId, client.clientName)) {
+			// Sessions assigned to a conduit shard only receive events for their conduit's subscriptions
+			continue
 		}
 
 		// Change payload's subscription.transport.session_id to contain the correct Session ID
-		eventObj.Subscription.Transport.SessionID = fmt.Sprintf("%v_%v", ws.ServerId, client.clientName)
+		clientEventObj.Subscription.Transport.SessionID = fmt.Sprintf("%v_%v", ws.ServerId, client.clientName)
 
 		// Change payload's subscription.created_at to contain the correct timestamp -- https://github.com/twitchdev/twitch-cli/issues/264
-		if ws.StrictMode {
-			// When running WITH --require-subscription, created_at will be set to the time the subscription was created using the mock EventSub REST endpoint
-			eventObj.Subscription.CreatedAt = subscriptionCreatedAtTimestamp
+		if subscription != nil {
+			// When subscribed using the mock EventSub REST endpoint, the payload describes that subscription
+			clientEventObj.Subscription.ID = subscription.SubscriptionID
+			clientEventObj.Subscription.Condition = subscription.Conditions
+			clientEventObj.Subscription.CreatedAt = subscription.CreatedAt
 		} else {
 			// When running WITHOUT --require-subscription, created_at will be set to the time the client connected
 			// This is because without --require-subscription the server "grants" access to all event subscriptions at the moment the client is connected
-			eventObj.Subscription.CreatedAt = client.ConnectedAtTimestamp
+			clientEventObj.Subscription.CreatedAt = client.ConnectedAtTimestamp
+		}
+
+		if messageType == "revocation" {
+			ws.disableSubscriptions(client.clientName, eventObj.Subscription, eventObj.Subscription.Status)
 		}
 
 		// Build notification message
-		notificationMsg, err := json.Marshal(
-			NotificationMessage{
-				Metadata: MessageMetadata{
-					MessageID:           util.RandomGUID(),
-					MessageType:         "notification",
-					MessageTimestamp:    time.Now().UTC().Format(time.RFC3339Nano),
-					SubscriptionType:    eventObj.Subscription.Type,
-					SubscriptionVersion: eventObj.Subscription.Version,
-				},
-				Payload: eventObj,
-			},
-		)
+		notificationMetadata := MessageMetadata{
+			MessageID:           notificationID,
+			MessageType:         messageType,
+			MessageTimestamp:    notificationTimestamp,
+			SubscriptionType:    clientEventObj.Subscription.Type,
+			SubscriptionVersion: clientEventObj.Subscription.Version,
+		}
+		notificationMsg, err := json.Marshal(NotificationMessage{Metadata: notificationMetadata, Payload: clientEventObj})
 		if err != nil {
 			msg := fmt.Sprintf("Error building JSON for client [%v]: %v", client.clientName, err.Error())
 			log.Println(msg)
 			return false, msg
 		}
 
-		client.SendMessage(websocket.TextMessage, notificationMsg)
-		log.Printf("Sent [%v / %v] to client [%v]", eventObj.Subscription.Type, eventObj.Subscription.Version, client.clientName)
+		// Messages are queued rather than written here, so a slow client doesn't hold up delivery to the others
+		if err := sendWithChaos(client, notificationMsg, notificationMetadata); err != nil && ws.DebugEnabled {
+			log.Printf("Could not send [%v / %v] to client [%v]: %v", clientEventObj.Subscription.Type, clientEventObj.Subscription.Version, client.clientName, err)
+		}
+		if len(clients) <= MAX_LOGGED_DELIVERIES {
+			log.Printf("Sent [%v / %v] to client [%v]", clientEventObj.Subscription.Type, clientEventObj.Subscription.Version, client.clientName)
+		}
 
-		didSend = true
+		sent++
 	}
 
-	if !didSend {
+	if sent == 0 {
 		msg := fmt.Sprintf("Error executing remote triggered EventSub: No clients are subscribed to [%v / %v]", eventObj.Subscription.Type, eventObj.Subscription.Version)
 		log.Println(msg)
 		return false, msg
 	}
+	if len(clients) > MAX_LOGGED_DELIVERIES {
+		log.Printf("Sent [%v / %v] to %v clients", eventObj.Subscription.Type, eventObj.Subscription.Version, sent)
+	}
 
 	return true, ""
 }
@@ -548,25 +695,52 @@ func (ws *WebSocketServer) handleClientConnectionClose(client *Client, closeReas
 			}
 		}
 		ws.Subscriptions[client.clientName] = subscriptions
+		ws.subscriptionsChanged()
 		ws.muSubscriptions.Unlock()
 	}
 
+	// Disable any conduit shards using the session. During reconnect testing, shards of sessions that reconnected were
+	// already moved to their new session, so only sessions that failed to reconnect are left.
+	shardStatus := getStatusFromCloseMessage(closeReason)
+	if ws.Status != 2 || shardStatus == STATUS_ENABLED {
+		shardStatus = STATUS_WEBSOCKET_FAILED_TO_RECONNECT
+	}
+	go serverManager.disableConduitShards(fmt.Sprintf("%v_%v", ws.ServerId, client.clientName), shardStatus)
+
+	sessionsClosed.Inc(strconv.Itoa(closeReason.code))
 	log.Printf("Disconnected client [%v] with code [%v]", client.clientName, closeReason.code)
 
 	// Print new clients connections list
 	ws.printConnections()
 }
 
-func (ws *WebSocketServer) printConnections() {
-	currentConnections := ""
+// Disconnects a client whose send queue filled up because it isn't reading messages as fast as they're sent
+func (ws *WebSocketServer) disconnectSlowClient(client *Client) {
+	ws.muClients.Lock()
+	defer ws.muClients.Unlock()
 
-	for _, client := range ws.Clients.All() {
-		currentConnections += client.clientName + ", "
+	// The client may have already disconnected
+	if c, ok := ws.Clients.Get(client.clientName); !ok || c != client {
+		return
 	}
 
-	if currentConnections != "" {
-		currentConnections = string(currentConnections[:len(currentConnections)-2])
+	log.Printf("Disconnecting client [%v] as it isn't reading messages fast enough; %v messages are waiting to be sent", client.clientName, CLIENT_SEND_QUEUE_SIZE)
+	client.stopWriter()
+	client.CloseWithReason(closeNetworkError)
+	ws.handleClientConnectionClose(client, closeNetworkError)
+}
+
+func (ws *WebSocketServer) printConnections() {
+	clients := ws.Clients.All()
+
+	names := make([]string, 0, MAX_LOGGED_CONNECTIONS+1)
+	for _, client := range clients {
+		if len(names) == MAX_LOGGED_CONNECTIONS {
+			names = append(names, fmt.Sprintf("and %v more", len(clients)-MAX_LOGGED_CONNECTIONS))
+			break
+		}
+		names = append(names, client.clientName)
 	}
 
-	log.Printf("[%s] Connections: (%d) [ %s ]", ws.ServerId, ws.Clients.Length(), currentConnections)
+	log.Printf("[%s] Connections: (%d) [ %s ]", ws.ServerId, len(clients), strings.Join(names, ", "))
 }
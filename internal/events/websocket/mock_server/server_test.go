@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestConditionFiltering(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 2)
+	defer f.close()
+
+	broadcasterA, broadcasterB := f.clients[0], f.clients[1]
+
+	f.ws.muSubscriptions.Lock()
+	f.ws.Subscriptions[broadcasterA.name] = []Subscription{{
+		SubscriptionID: "a", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED,
+		Conditions: models.EventsubCondition{BroadcasterUserID: "1001", ModeratorUserID: "1001"},
+	}}
+	f.ws.Subscriptions[broadcasterB.name] = []Subscription{{
+		SubscriptionID: "b", Type: "channel.follow", Version: "2", Status: STATUS_ENABLED,
+		Conditions: models.EventsubCondition{BroadcasterUserID: "1002", ModeratorUserID: "1002"},
+	}}
+	f.ws.subscriptionsChanged()
+	f.ws.muSubscriptions.Unlock()
+
+	event := models.EventsubSubscription{Type: "channel.follow", Version: "2", Condition: models.EventsubCondition{BroadcasterUserID: "1001", ModeratorUserID: "1001"}}
+	sub, subscribed := f.ws.findMatchingSubscription(broadcasterA.name, event)
+	a.NotNil(sub)
+	a.Equal("a", sub.SubscriptionID)
+	a.True(subscribed)
+
+	// Subscribed to the topic, but for another broadcaster
+	sub, subscribed = f.ws.findMatchingSubscription(broadcasterB.name, event)
+	a.Nil(sub)
+	a.True(subscribed)
+
+	// Only the session subscribed to broadcaster A receives its event. Messages to a session arrive in order, so had
+	// broadcaster B's session received it, it would have arrived before broadcaster B's own event.
+	a.Equal(1, f.send(t, "1001"))
+	a.Equal(1, f.send(t, "1002"))
+	a.Len(broadcasterA.notifications, 1)
+	a.Len(broadcasterB.notifications, 1)
+}
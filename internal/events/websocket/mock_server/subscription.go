@@ -18,6 +18,15 @@ type Subscription struct {
 	ClientConnectedAt    string // Time client connected
 	ClientDisconnectedAt string // Time client disconnected
 
+	Callback string // Webhook only; URL events are sent to
+	Secret   string // Webhook only; Secret used to sign events
+
+	ConduitID string // Conduit only; Conduit whose shards events are sent to
+
+	Token  string // Bearer token the subscription was created with, if any
+	UserID string // User the token was issued to, if known to the mock API
+	Cost   int    // 0 if a user in the condition authorized the client; Otherwise 1
+
 	Conditions models.EventsubCondition // Values of the subscription's condition object
 }
 
@@ -34,6 +43,9 @@ type SubscriptionPostRequest struct {
 type SubscriptionPostRequestTransport struct {
 	Method    string `json:"method"`
 	SessionID string `json:"session_id"`
+	Callback  string `json:"callback"`
+	Secret    string `json:"secret"`
+	ConduitID string `json:"conduit_id"`
 }
 
 // Response (Success) - POST /eventsub/subscriptions
@@ -79,7 +91,9 @@ type SubscriptionGetSuccessResponse struct {
 // Cross-usage
 type SubscriptionTransport struct {
 	Method         string `json:"method"`
-	SessionID      string `json:"session_id"`
+	SessionID      string `json:"session_id,omitempty"`
+	Callback       string `json:"callback,omitempty"`
+	ConduitID      string `json:"conduit_id,omitempty"`
 	ConnectedAt    string `json:"connected_at,omitempty"`
 	DisconnectedAt string `json:"disconnected_at,omitempty"`
 }
@@ -93,7 +107,7 @@ type EmptyStruct struct {
 // https://dev.twitch.tv/docs/api/reference/#get-eventsub-subscriptions
 const (
 	STATUS_ENABLED                            = "enabled"
-	STATUS_AUTHORIZATION_REVOKED              = "revoked"
+	STATUS_AUTHORIZATION_REVOKED              = "authorization_revoked"
 	STATUS_MODERATOR_REMOVED                  = "moderator_removed"
 	STATUS_USER_REMOVED                       = "user_removed"
 	STATUS_VERSION_REMOVED                    = "version_removed"
@@ -0,0 +1,152 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+)
+
+// Condition fields used to match events to subscriptions, in the order they're joined into index keys. The moderator
+// isn't part of the events' conditions, so it's ignored as it is by types.ConditionMatches.
+var indexedConditionFields = func() []string {
+	fields := []string{}
+	for field := range types.ConditionFields(models.EventsubCondition{}) {
+		if field != "moderator_user_id" {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}()
+
+type topicKey struct {
+	Type    string
+	Version string
+}
+
+type subscriptionIndexKey struct {
+	topicKey
+	Condition string // The subscription's non-empty condition fields, joined by conditionKey
+}
+
+type indexedSubscription struct {
+	clientName   string
+	position     int // Position in the client's subscriptions; Earlier subscriptions are matched first
+	subscription Subscription
+}
+
+// The enabled WebSocket subscriptions of a server, indexed by topic, version, and condition, so events are matched with
+// lookups instead of scanning every client's subscriptions. It's a snapshot, rebuilt after the subscriptions change.
+type subscriptionIndex struct {
+	byCondition map[subscriptionIndexKey][]indexedSubscription
+	byTopic     map[topicKey]map[string]bool // Clients with an enabled subscription to the topic and version, whatever its condition
+}
+
+func newSubscriptionIndex(subscriptions map[string][]Subscription) *subscriptionIndex {
+	idx := &subscriptionIndex{
+		byCondition: make(map[subscriptionIndexKey][]indexedSubscription),
+		byTopic:     make(map[topicKey]map[string]bool),
+	}
+
+	for clientName, clientSubscriptions := range subscriptions {
+		for i, s := range clientSubscriptions {
+			if s.Status != STATUS_ENABLED {
+				continue
+			}
+
+			topic := topicKey{Type: s.Type, Version: s.Version}
+			if idx.byTopic[topic] == nil {
+				idx.byTopic[topic] = make(map[string]bool)
+			}
+			idx.byTopic[topic][clientName] = true
+
+			fields := types.ConditionFields(s.Conditions)
+			names := []string{}
+			for _, field := range indexedConditionFields {
+				if fields[field] != "" {
+					names = append(names, field)
+				}
+			}
+			key := subscriptionIndexKey{topicKey: topic, Condition: conditionKey(names, fields)}
+			idx.byCondition[key] = append(idx.byCondition[key], indexedSubscription{clientName: clientName, position: i, subscription: s})
+		}
+	}
+
+	return idx
+}
+
+// Returns each client's first enabled subscription whose topic, version, and condition match the event. A subscription
+// matches when each of its condition fields equals the event's, so its key is the key of a subset of the event's fields.
+func (idx *subscriptionIndex) match(event models.EventsubSubscription) map[string]*indexedSubscription {
+	matches := make(map[string]*indexedSubscription)
+	topic := topicKey{Type: event.Type, Version: event.Version}
+	if len(idx.byTopic[topic]) == 0 {
+		return matches
+	}
+
+	fields := types.ConditionFields(event.Condition)
+	names := []string{}
+	for _, field := range indexedConditionFields {
+		if fields[field] != "" {
+			names = append(names, field)
+		}
+	}
+
+	subset := make([]string, 0, len(names))
+	for mask := 0; mask < 1<<len(names); mask++ {
+		subset = subset[:0]
+		for i, name := range names {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, name)
+			}
+		}
+
+		entries := idx.byCondition[subscriptionIndexKey{topicKey: topic, Condition: conditionKey(subset, fields)}]
+		for i := range entries {
+			e := &entries[i]
+			if existing, ok := matches[e.clientName]; !ok || e.position < existing.position {
+				matches[e.clientName] = e
+			}
+		}
+	}
+
+	return matches
+}
+
+// Returns whether the client has an enabled subscription to the event's topic and version, whatever its condition
+func (idx *subscriptionIndex) hasTopicSubscription(clientName string, event models.EventsubSubscription) bool {
+	return idx.byTopic[topicKey{Type: event.Type, Version: event.Version}][clientName]
+}
+
+// Joins the given condition fields and their values into an index key
+func conditionKey(names []string, fields map[string]string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(fields[name])
+		b.WriteByte('&')
+	}
+	return b.String()
+}
+
+// Marks the subscription index as stale after the server's subscriptions changed. Must be called with muSubscriptions
+// held.
+func (ws *WebSocketServer) subscriptionsChanged() {
+	ws.subscriptionIndex = nil
+}
+
+// Returns the index of the server's enabled subscriptions, rebuilding it if they changed since it was last built
+func (ws *WebSocketServer) getSubscriptionIndex() *subscriptionIndex {
+	ws.muSubscriptions.Lock()
+	defer ws.muSubscriptions.Unlock()
+
+	if ws.subscriptionIndex == nil {
+		ws.subscriptionIndex = newSubscriptionIndex(ws.Subscriptions)
+	}
+	return ws.subscriptionIndex
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/ui"
+)
+
+// Response - GET /_ui/api/trigger
+type UITriggerForm struct {
+	Events []UITriggerEvent `json:"events"`
+	Flags  []ui.Flag        `json:"flags"`
+}
+
+type UITriggerEvent struct {
+	Topic    string   `json:"topic"`
+	Versions []string `json:"versions"`
+}
+
+// Registers the dashboard at /_ui
+func registerUIHandlers(m *http.ServeMux) {
+	info := ui.Info{
+		Title:  "Twitch CLI - EventSub WebSocket Server",
+		Panels: []string{ui.PanelSessions, ui.PanelSubscriptions, ui.PanelTrigger},
+	}
+
+	api := ui.NewAPI()
+	api.Handle(http.MethodGet, "sessions", adminSessionsHandler)
+	api.Handle(http.MethodGet, "subscriptions", adminSubscriptionsHandler)
+	api.Handle(http.MethodGet, "trigger", uiTriggerFormHandler)
+	api.Handle(http.MethodPost, "trigger", uiTriggerHandler)
+
+	// The events cache and tables are only available when the database could be opened
+	if serverManager.db != nil {
+		info.Panels = append(info.Panels, ui.PanelEvents, ui.PanelTables)
+		api.HandleDatabase(*serverManager.db)
+	}
+
+	m.Handle(ui.Path, ui.Handler(info, api))
+}
+
+// GET /_ui/api/trigger
+// Lists the events that can be triggered, and the flags of the shell's trigger command
+func uiTriggerFormHandler(w http.ResponseWriter, r *http.Request) {
+	versions := make(map[string][]string)
+	for _, e := range types.AllEvents() {
+		for _, transport := range []string{models.TransportWebSocket, models.TransportWebhook} {
+			for _, topic := range e.GetAllTopicsByTransport(transport) {
+				if !containsString(versions[topic], e.SubscriptionVersion()) {
+					versions[topic] = append(versions[topic], e.SubscriptionVersion())
+				}
+			}
+		}
+	}
+
+	form := UITriggerForm{
+		Events: []UITriggerEvent{},
+		Flags:  ui.Flags(triggerFlags(&trigger.TriggerParameters{})),
+	}
+	for topic, v := range versions {
+		// Newest version first, so it's selected by default
+		sort.Sort(sort.Reverse(sort.StringSlice(v)))
+		form.Events = append(form.Events, UITriggerEvent{Topic: topic, Versions: v})
+	}
+	sort.Slice(form.Events, func(i, j int) bool { return form.Events[i].Topic < form.Events[j].Topic })
+
+	admin.WriteJSON(w, "", form)
+}
+
+// POST /_ui/api/trigger
+// Triggers an event, as the shell's trigger command does
+func uiTriggerHandler(w http.ResponseWriter, r *http.Request) {
+	var body ui.FormRequest
+	if !admin.DecodeBody(w, r, &body) {
+		return
+	}
+	if len(body.Args) != 1 {
+		admin.WriteError(w, http.StatusBadRequest, "Expected the event to trigger")
+		return
+	}
+
+	p := trigger.TriggerParameters{}
+	if err := ui.SetFlags(triggerFlags(&p), body.Flags); err != nil {
+		admin.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	p.Event = body.Args[0]
+	if p.Count < 1 || p.Count > trigger.MaxCount {
+		admin.WriteError(w, http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %v", trigger.MaxCount))
+		return
+	}
+
+	for i := 0; i < p.Count; i++ {
+		if _, err := trigger.Fire(p); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	admin.WriteJSON(w, fmt.Sprintf("Triggered [%v] %v time(s) with the %v transport", p.Event, p.Count, strings.ToLower(p.Transport)), nil)
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
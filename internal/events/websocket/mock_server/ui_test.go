@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/ui"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestUITriggerCount(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	triggerCount := func(count int) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(ui.FormRequest{Args: []string{"channel.ban"}, Flags: map[string]string{"count": fmt.Sprint(count)}})
+		w := httptest.NewRecorder()
+		uiTriggerHandler(w, httptest.NewRequest(http.MethodPost, ui.APIPath+"trigger", bytes.NewReader(b)))
+		return w
+	}
+
+	for _, count := range []int{0, trigger.MaxCount + 1} {
+		w := triggerCount(count)
+		a.Equal(http.StatusBadRequest, w.Code)
+		a.Contains(w.Body.String(), "count must be between 1 and 100")
+	}
+}
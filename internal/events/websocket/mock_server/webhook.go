@@ -0,0 +1,231 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/events/types"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+// Subscription statuses only used by webhook subscriptions
+const (
+	STATUS_WEBHOOK_CALLBACK_VERIFICATION_PENDING = "webhook_callback_verification_pending"
+	STATUS_WEBHOOK_CALLBACK_VERIFICATION_FAILED  = "webhook_callback_verification_failed"
+	STATUS_NOTIFICATION_FAILURES_EXCEEDED        = "notification_failures_exceeded"
+)
+
+var errSubscriptionExists = errors.New("the subscription already exists")
+var errMaxTotalCost = errors.New("the subscription exceeds the maximum total cost")
+
+// Validates the transport of a webhook subscription request, returning the error message sent to the client
+func validateWebhookTransport(transport SubscriptionPostRequestTransport) string {
+	u, err := url.ParseRequestURI(transport.Callback)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "The value specified in the 'callback' field is not valid"
+	}
+	if len(transport.Secret) < 10 || len(transport.Secret) > 100 {
+		return "The value specified in the 'secret' field is not valid; Secrets must be between 10-100 characters"
+	}
+	return ""
+}
+
+// Adds a webhook subscription in the pending state, and starts the verification handshake in the background. Returns
+// the number and total cost of the client's webhook and conduit subscriptions before it was added, or
+// errSubscriptionExists or errMaxTotalCost if it wasn't.
+func (sm *ServerManager) addWebhookSubscription(subscription Subscription) (int, int, error) {
+	sm.muWebhookSubscriptions.Lock()
+	sm.muConduits.Lock()
+	defer sm.muWebhookSubscriptions.Unlock()
+	defer sm.muConduits.Unlock()
+
+	for _, s := range sm.webhookSubscriptions {
+		if s.ClientID == subscription.ClientID && s.Type == subscription.Type && s.Version == subscription.Version &&
+			s.Conditions == subscription.Conditions && s.Callback == subscription.Callback {
+			return 0, 0, errSubscriptionExists
+		}
+	}
+
+	total, totalCost := sm.webhookCostTotals(subscription.ClientID)
+	if totalCost+subscription.Cost > WEBHOOK_MAX_TOTAL_COST {
+		return 0, 0, errMaxTotalCost
+	}
+
+	sm.webhookSubscriptions = append(sm.webhookSubscriptions, subscription)
+
+	// Twitch responds to the request before sending the challenge
+	go sm.verifyWebhookSubscription(subscription)
+
+	return total, totalCost, nil
+}
+
+// Sends the webhook_callback_verification challenge to the subscription's callback, enabling the subscription only if
+// the callback responds with the challenge
+func (sm *ServerManager) verifyWebhookSubscription(subscription Subscription) {
+	status := STATUS_ENABLED
+	err := sendWebhookChallenge(subscription.Callback, subscription.Secret, subscription.toEventsubSubscription())
+	if err != nil {
+		status = STATUS_WEBHOOK_CALLBACK_VERIFICATION_FAILED
+		log.Printf("Webhook subscription [%v] failed verification: %v", subscription.SubscriptionID, err)
+	} else {
+		log.Printf("Webhook subscription [%v] to [%v / %v] verified; Events will be sent to %v", subscription.SubscriptionID, subscription.Type, subscription.Version, subscription.Callback)
+	}
+
+	sm.muWebhookSubscriptions.Lock()
+	defer sm.muWebhookSubscriptions.Unlock()
+	for i, s := range sm.webhookSubscriptions {
+		if s.SubscriptionID == subscription.SubscriptionID {
+			sm.webhookSubscriptions[i].Status = status
+			if status != STATUS_ENABLED {
+				tNow := util.GetTimestamp()
+				sm.webhookSubscriptions[i].DisabledAt = &tNow
+			}
+		}
+	}
+}
+
+// Sends a webhook_callback_verification challenge to the callback, returning an error unless the callback responds
+// with the challenge
+func sendWebhookChallenge(callback string, secret string, subscription models.EventsubSubscription) error {
+	challenge := util.RandomGUID()
+
+	body, _ := json.Marshal(models.EventsubSubscriptionVerification{
+		Challenge:    challenge,
+		Subscription: subscription,
+	})
+
+	resp, err := trigger.ForwardEvent(trigger.ForwardParamters{
+		ID:                  util.RandomGUID(),
+		ForwardAddress:      callback,
+		JSON:                body,
+		Transport:           models.TransportWebhook,
+		Timestamp:           util.GetTimestamp().Format(time.RFC3339Nano),
+		Secret:              secret,
+		Event:               subscription.Type,
+		Type:                trigger.EventSubMessageTypeVerification,
+		SubscriptionVersion: subscription.Version,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || string(respBody) != challenge {
+		return fmt.Errorf("Callback responded [%v] with body [%v], expected the challenge [%v]", resp.StatusCode, string(respBody), challenge)
+	}
+
+	return nil
+}
+
+// Sends an event to the callback of every enabled webhook subscription matching its topic, version, and condition.
+// Returns the number of subscriptions the event was sent to.
+func (sm *ServerManager) HandleWebhookForwarding(eventsubBody string, messageID string, messageTimestamp string) (int, error) {
+	eventObj := models.EventsubResponse{}
+	err := json.Unmarshal([]byte(eventsubBody), &eventObj)
+	if err != nil {
+		return 0, fmt.Errorf("Error reading JSON forwarded from EventSub: %v", err.Error())
+	}
+
+	messageType := trigger.EventSubMessageTypeNotification
+	if eventObj.Subscription.Status != "" && eventObj.Subscription.Status != STATUS_ENABLED {
+		messageType = trigger.EventSubMessageTypeRevocation
+	}
+
+	if messageTimestamp == "" {
+		messageTimestamp = util.GetTimestamp().Format(time.RFC3339Nano)
+	}
+
+	// Copied so callbacks aren't called while holding the lock
+	sm.muWebhookSubscriptions.Lock()
+	matching := []Subscription{}
+	for i, sub := range sm.webhookSubscriptions {
+		if sub.Type == eventObj.Subscription.Type && sub.Version == eventObj.Subscription.Version && sub.Status == STATUS_ENABLED &&
+			types.ConditionMatches(sub.Conditions, eventObj.Subscription.Condition) {
+			matching = append(matching, sub)
+
+			if messageType == trigger.EventSubMessageTypeRevocation {
+				sm.webhookSubscriptions[i].Status = eventObj.Subscription.Status
+				tNow := util.GetTimestamp()
+				sm.webhookSubscriptions[i].DisabledAt = &tNow
+			}
+		}
+	}
+	sm.muWebhookSubscriptions.Unlock()
+
+	for _, sub := range matching {
+		// The payload describes the subscription it's sent to
+		subEventObj := eventObj
+		subEventObj.Subscription = sub.toEventsubSubscription()
+		if messageType == trigger.EventSubMessageTypeRevocation {
+			subEventObj.Subscription.Status = eventObj.Subscription.Status
+		}
+		body, _ := json.Marshal(subEventObj)
+
+		id := messageID
+		if id == "" {
+			id = util.RandomGUID()
+		}
+
+		resp, err := trigger.ForwardEvent(trigger.ForwardParamters{
+			ID:                  id,
+			ForwardAddress:      sub.Callback,
+			JSON:                body,
+			Transport:           models.TransportWebhook,
+			Timestamp:           messageTimestamp,
+			Secret:              sub.Secret,
+			Event:               sub.Type,
+			Type:                messageType,
+			SubscriptionVersion: sub.Version,
+		})
+		if err != nil {
+			log.Printf("Failed to send [%v / %v] to webhook subscription [%v]: %v", sub.Type, sub.Version, sub.SubscriptionID, err)
+			continue
+		}
+		resp.Body.Close()
+
+		log.Printf("Sent [%v / %v] to webhook subscription [%v]; Callback responded [%v]", sub.Type, sub.Version, sub.SubscriptionID, resp.StatusCode)
+	}
+
+	return len(matching), nil
+}
+
+// Builds the subscription object included in payloads sent to webhook and conduit subscriptions
+func (s Subscription) toEventsubSubscription() models.EventsubSubscription {
+	if s.ConduitID != "" {
+		return models.EventsubSubscription{
+			ID:        s.SubscriptionID,
+			Status:    s.Status,
+			Type:      s.Type,
+			Version:   s.Version,
+			Condition: s.Conditions,
+			Transport: models.EventsubTransport{
+				Method:    TRANSPORT_CONDUIT,
+				ConduitID: s.ConduitID,
+			},
+			CreatedAt: s.CreatedAt,
+		}
+	}
+
+	return models.EventsubSubscription{
+		ID:        s.SubscriptionID,
+		Status:    s.Status,
+		Type:      s.Type,
+		Version:   s.Version,
+		Condition: s.Conditions,
+		Transport: models.EventsubTransport{
+			Method:   models.TransportWebhook,
+			Callback: s.Callback,
+		},
+		CreatedAt: s.CreatedAt,
+	}
+}
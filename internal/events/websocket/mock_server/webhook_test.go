@@ -0,0 +1,187 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/events/trigger"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+const testWebhookSecret = "webhooksecret"
+
+// A webhook callback, which records the messages it receives
+type testCallback struct {
+	server   *httptest.Server
+	messages chan testCallbackMessage
+}
+
+type testCallbackMessage struct {
+	header http.Header
+	body   []byte
+}
+
+// Starts a callback that answers verification challenges with the given function, and every other message with 204
+func newTestCallback(respond func(challenge string) string) *testCallback {
+	c := &testCallback{messages: make(chan testCallbackMessage, 10)}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.messages <- testCallbackMessage{header: r.Header, body: body}
+
+		if r.Header.Get("Twitch-Eventsub-Message-Type") == trigger.EventSubMessageTypeVerification {
+			var verification models.EventsubSubscriptionVerification
+			json.Unmarshal(body, &verification)
+			w.Write([]byte(respond(verification.Challenge)))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return c
+}
+
+// Returns whether the message's signature was made with the secret, as receivers check
+func (m testCallbackMessage) signedWith(secret string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(m.header.Get("Twitch-Eventsub-Message-Id") + m.header.Get("Twitch-Eventsub-Message-Timestamp")))
+	mac.Write(m.body)
+	return m.header.Get("Twitch-Eventsub-Message-Signature") == "sha256="+hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookSubscriptionStatus(id string) string {
+	serverManager.muWebhookSubscriptions.Lock()
+	defer serverManager.muWebhookSubscriptions.Unlock()
+	for _, sub := range serverManager.webhookSubscriptions {
+		if sub.SubscriptionID == id {
+			return sub.Status
+		}
+	}
+	return ""
+}
+
+func TestWebhookSubscriptionVerification(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 0)
+	defer f.close()
+	clientID, token := newTestAuthorization(t, "", "")
+
+	create := func(callback string) SubscriptionPostSuccessResponseBody {
+		w := handlerRequest(subscriptionPageHandler, http.MethodPost, "/eventsub/subscriptions", clientID, token, SubscriptionPostRequest{
+			Type:      "channel.update",
+			Version:   "2",
+			Condition: models.EventsubCondition{BroadcasterUserID: "1"},
+			Transport: SubscriptionPostRequestTransport{Method: models.TransportWebhook, Callback: callback, Secret: testWebhookSecret},
+		})
+		a.Equal(http.StatusAccepted, w.Code, w.Body.String())
+
+		var resp SubscriptionPostSuccessResponse
+		a.Nil(json.Unmarshal(w.Body.Bytes(), &resp))
+		a.Len(resp.Data, 1)
+		return resp.Data[0]
+	}
+
+	// The callback echoes the challenge, so the subscription is enabled
+	verified := newTestCallback(func(challenge string) string { return challenge })
+	defer verified.server.Close()
+
+	sub := create(verified.server.URL)
+	a.Equal(STATUS_WEBHOOK_CALLBACK_VERIFICATION_PENDING, sub.Status)
+
+	message := <-verified.messages
+	a.Equal(trigger.EventSubMessageTypeVerification, message.header.Get("Twitch-Eventsub-Message-Type"))
+	a.Equal("channel.update", message.header.Get("Twitch-Eventsub-Subscription-Type"))
+	a.True(message.signedWith(testWebhookSecret))
+	a.Eventually(func() bool { return webhookSubscriptionStatus(sub.ID) == STATUS_ENABLED }, time.Second, 10*time.Millisecond)
+
+	// Creating it again is a conflict
+	w := handlerRequest(subscriptionPageHandler, http.MethodPost, "/eventsub/subscriptions", clientID, token, SubscriptionPostRequest{
+		Type:      "channel.update",
+		Version:   "2",
+		Condition: models.EventsubCondition{BroadcasterUserID: "1"},
+		Transport: SubscriptionPostRequestTransport{Method: models.TransportWebhook, Callback: verified.server.URL, Secret: testWebhookSecret},
+	})
+	a.Equal(http.StatusConflict, w.Code)
+
+	// The callback responds with something other than the challenge, so verification fails
+	wrong := newTestCallback(func(challenge string) string { return "not the challenge" })
+	defer wrong.server.Close()
+
+	sub = create(wrong.server.URL)
+	<-wrong.messages
+	a.Eventually(func() bool {
+		return webhookSubscriptionStatus(sub.ID) == STATUS_WEBHOOK_CALLBACK_VERIFICATION_FAILED
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWebhookForwarding(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	f := newFanoutServer(t, 0)
+	defer f.close()
+
+	broadcaster1 := newTestCallback(func(challenge string) string { return challenge })
+	defer broadcaster1.server.Close()
+	broadcaster2 := newTestCallback(func(challenge string) string { return challenge })
+	defer broadcaster2.server.Close()
+
+	serverManager.webhookSubscriptions = []Subscription{
+		{
+			SubscriptionID: "broadcaster1", ClientID: "client", Type: "channel.update", Version: "2", Status: STATUS_ENABLED,
+			Callback: broadcaster1.server.URL, Secret: testWebhookSecret, Conditions: models.EventsubCondition{BroadcasterUserID: "1"},
+		},
+		{
+			SubscriptionID: "broadcaster2", ClientID: "client", Type: "channel.update", Version: "2", Status: STATUS_ENABLED,
+			Callback: broadcaster2.server.URL, Secret: testWebhookSecret, Conditions: models.EventsubCondition{BroadcasterUserID: "2"},
+		},
+	}
+
+	body, _ := json.Marshal(models.EventsubResponse{
+		Subscription: models.EventsubSubscription{
+			ID:        util.RandomGUID(),
+			Status:    STATUS_ENABLED,
+			Type:      "channel.update",
+			Version:   "2",
+			Condition: models.EventsubCondition{BroadcasterUserID: "1"},
+		},
+		Event: map[string]string{"broadcaster_user_id": "1"},
+	})
+
+	// Only sent to the subscription whose condition matches
+	messageID := util.RandomGUID()
+	sent, err := serverManager.HandleWebhookForwarding(string(body), messageID, "")
+	a.Nil(err)
+	a.Equal(1, sent)
+	a.Empty(broadcaster2.messages)
+
+	message := <-broadcaster1.messages
+	a.Equal(trigger.EventSubMessageTypeNotification, message.header.Get("Twitch-Eventsub-Message-Type"))
+	a.Equal(messageID, message.header.Get("Twitch-Eventsub-Message-Id"))
+	a.Equal("channel.update", message.header.Get("Twitch-Eventsub-Subscription-Type"))
+	a.Equal("2", message.header.Get("Twitch-Eventsub-Subscription-Version"))
+	a.NotEmpty(message.header.Get("Twitch-Eventsub-Message-Timestamp"))
+	a.True(message.signedWith(testWebhookSecret))
+	a.False(message.signedWith("wrongsecret"))
+
+	// The payload describes the subscription it was sent to
+	var payload models.EventsubResponse
+	a.Nil(json.Unmarshal(message.body, &payload))
+	a.Equal("broadcaster1", payload.Subscription.ID)
+	a.Equal(broadcaster1.server.URL, payload.Subscription.Transport.Callback)
+
+	// Disabled subscriptions aren't sent events
+	serverManager.webhookSubscriptions[0].Status = STATUS_WEBHOOK_CALLBACK_VERIFICATION_FAILED
+	sent, err = serverManager.HandleWebhookForwarding(string(body), "", "")
+	a.Nil(err)
+	a.Equal(0, sent)
+}
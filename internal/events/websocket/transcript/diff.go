@@ -0,0 +1,205 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	OpEqual   = " "
+	OpRemoved = "-" // Only in the first transcript
+	OpAdded   = "+" // Only in the second transcript
+	OpChanged = "~" // In both transcripts, with different message bodies
+)
+
+// DiffLine is a frame of either transcript. Changes lists the differences between the message bodies of changed frames.
+type DiffLine struct {
+	Op      string   `json:"op"`
+	Entry   Entry    `json:"entry"`
+	Changes []string `json:"changes,omitempty"`
+}
+
+// Fields that differ between every run, such as IDs and timestamps, which are ignored when comparing message bodies
+var volatileFields = map[string]bool{
+	"id":                true,
+	"message_id":        true,
+	"message_timestamp": true,
+	"session_id":        true,
+	"reconnect_url":     true,
+}
+
+// Diff compares the frames of two transcripts, ignoring pings and pongs, whose timing varies between runs. Frames are
+// matched by their direction, frame type, message type, subscription type, and close code, and the message bodies of
+// matched frames are compared, ignoring IDs and timestamps.
+func Diff(a []Entry, b []Entry) []DiffLine {
+	a = withoutPings(a)
+	b = withoutPings(b)
+
+	// Longest common subsequence of the frames' keys
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if entryKey(a[i]) == entryKey(b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := []DiffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if entryKey(a[i]) == entryKey(b[j]) {
+			changes := compareMessages(a[i].Message, b[j].Message)
+			if len(changes) > 0 {
+				lines = append(lines, DiffLine{Op: OpChanged, Entry: b[j], Changes: changes})
+			} else {
+				lines = append(lines, DiffLine{Op: OpEqual, Entry: b[j]})
+			}
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			lines = append(lines, DiffLine{Op: OpRemoved, Entry: a[i]})
+			i++
+		} else {
+			lines = append(lines, DiffLine{Op: OpAdded, Entry: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: OpRemoved, Entry: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: OpAdded, Entry: b[j]})
+	}
+
+	return lines
+}
+
+// PrintDiff writes the diff in a format similar to a unified diff, and returns whether the transcripts differ.
+func PrintDiff(w io.Writer, lines []DiffLine) bool {
+	different := false
+	for _, l := range lines {
+		if l.Op != OpEqual {
+			different = true
+		}
+		fmt.Fprintf(w, "%v %v\n", l.Op, Describe(l.Entry))
+		for _, c := range l.Changes {
+			fmt.Fprintf(w, "      %v\n", c)
+		}
+	}
+	return different
+}
+
+// Describe summarizes an entry in a single line, such as "sent notification (channel.follow)" or "received close 1000".
+func Describe(e Entry) string {
+	desc := e.Direction + " " + e.Frame
+	if e.MessageType != "" {
+		desc = e.Direction + " " + e.MessageType
+	}
+	if e.SubscriptionType != "" {
+		desc += fmt.Sprintf(" (%v)", e.SubscriptionType)
+	}
+	if e.Frame == FrameClose {
+		desc += fmt.Sprintf(" %v", e.CloseCode)
+		if e.CloseReason != "" {
+			desc += fmt.Sprintf(" %q", e.CloseReason)
+		}
+	}
+	return desc
+}
+
+func withoutPings(entries []Entry) []Entry {
+	filtered := []Entry{}
+	for _, e := range entries {
+		if e.Frame != FramePing && e.Frame != FramePong {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func entryKey(e Entry) string {
+	return fmt.Sprintf("%v|%v|%v|%v|%v", e.Direction, e.Frame, e.MessageType, e.SubscriptionType, e.CloseCode)
+}
+
+// Returns the differences between two message bodies, as "path: old -> new", ignoring volatile fields and timestamps
+func compareMessages(a json.RawMessage, b json.RawMessage) []string {
+	fieldsA := map[string]string{}
+	fieldsB := map[string]string{}
+	flatten("", decode(a), fieldsA)
+	flatten("", decode(b), fieldsB)
+
+	paths := map[string]bool{}
+	for p := range fieldsA {
+		paths[p] = true
+	}
+	for p := range fieldsB {
+		paths[p] = true
+	}
+
+	changes := []string{}
+	for p := range paths {
+		valueA, okA := fieldsA[p]
+		valueB, okB := fieldsB[p]
+		switch {
+		case !okA:
+			changes = append(changes, fmt.Sprintf("%v: added %v", p, valueB))
+		case !okB:
+			changes = append(changes, fmt.Sprintf("%v: removed %v", p, valueA))
+		case valueA != valueB:
+			changes = append(changes, fmt.Sprintf("%v: %v -> %v", p, valueA, valueB))
+		}
+	}
+	sort.Strings(changes)
+
+	return changes
+}
+
+func decode(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	var v interface{}
+	json.Unmarshal(raw, &v)
+	return v
+}
+
+// Flattens a decoded JSON value into dot-separated paths and their JSON-encoded values
+func flatten(path string, v interface{}, fields map[string]string) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, child := range value {
+			if volatileFields[k] || strings.HasSuffix(k, "_at") {
+				continue
+			}
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			flatten(childPath, child, fields)
+		}
+	case []interface{}:
+		for i, child := range value {
+			flatten(fmt.Sprintf("%v[%v]", path, i), child, fields)
+		}
+	case nil:
+		if path != "" {
+			fields[path] = "null"
+		}
+	default:
+		b, _ := json.Marshal(value)
+		fields[path] = string(b)
+	}
+}
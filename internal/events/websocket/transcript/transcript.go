@@ -0,0 +1,164 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package transcript records the frames the mock EventSub WebSocket server sends and receives on each session as JSONL,
+// and compares recorded transcripts.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Messages can include large event payloads, so lines are allowed to be well above bufio's default of 64KB
+const maxLineSize = 10 * 1024 * 1024
+
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+const (
+	FrameText       = "text"
+	FrameBinary     = "binary"
+	FramePing       = "ping"
+	FramePong       = "pong"
+	FrameClose      = "close"
+	FrameDisconnect = "disconnect" // The TCP connection was closed without a close frame
+)
+
+// Entry is a single line of a transcript.
+type Entry struct {
+	Timestamp        string          `json:"timestamp"`
+	Session          string          `json:"session"`
+	Direction        string          `json:"direction"`
+	Frame            string          `json:"frame"`
+	MessageType      string          `json:"message_type,omitempty"`      // metadata.message_type of text frames sent by the server
+	SubscriptionType string          `json:"subscription_type,omitempty"` // metadata.subscription_type of notifications and revocations
+	Message          json.RawMessage `json:"message,omitempty"`           // Body of text frames; A JSON string if the body isn't JSON
+	CloseCode        int             `json:"close_code,omitempty"`
+	CloseReason      string          `json:"close_reason,omitempty"`
+}
+
+// Writer appends a session's entries to <dir>/<session>.jsonl. It's safe for concurrent use, and ignores entries
+// recorded after it's closed.
+type Writer struct {
+	session string
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func NewWriter(dir string, session string) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, session+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{session: session, file: f, encoder: json.NewEncoder(f)}, nil
+}
+
+// Record writes an entry, filling in its timestamp and session.
+func (w *Writer) Record(e Entry) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return
+	}
+
+	e.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	e.Session = w.session
+	w.encoder.Encode(e)
+}
+
+// RecordMessage writes a text or binary frame, taking the message and subscription types from its metadata.
+func (w *Writer) RecordMessage(direction string, frame string, data []byte) {
+	if w == nil {
+		return
+	}
+
+	e := Entry{Direction: direction, Frame: frame}
+
+	var msg struct {
+		Metadata struct {
+			MessageType      string `json:"message_type"`
+			SubscriptionType string `json:"subscription_type"`
+		} `json:"metadata"`
+	}
+	if json.Valid(data) {
+		e.Message = json.RawMessage(data)
+		if json.Unmarshal(data, &msg) == nil {
+			e.MessageType = msg.Metadata.MessageType
+			e.SubscriptionType = msg.Metadata.SubscriptionType
+		}
+	} else {
+		e.Message, _ = json.Marshal(string(data))
+	}
+
+	w.Record(e)
+}
+
+func (w *Writer) Close() error {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// Read parses a transcript written by Writer.
+func Read(r io.Reader) ([]Entry, error) {
+	entries := []Entry{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("Invalid transcript entry on line %v: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, scanner.Err()
+}
+
+// ReadFile parses the transcript at path.
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package transcript
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestWriter(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	dir := t.TempDir()
+	w, err := NewWriter(dir, "abcd1234_efgh5678")
+	a.Nil(err)
+
+	w.RecordMessage(DirectionSent, FrameText, []byte(`{"metadata":{"message_id":"1","message_type":"notification","subscription_type":"channel.follow"},"payload":{}}`))
+	w.RecordMessage(DirectionReceived, FrameText, []byte("not json"))
+	w.Record(Entry{Direction: DirectionSent, Frame: FramePing})
+	w.Record(Entry{Direction: DirectionSent, Frame: FrameClose, CloseCode: 4006, CloseReason: "network error"})
+	a.Nil(w.Close())
+
+	// Entries recorded after closing are ignored
+	w.Record(Entry{Direction: DirectionSent, Frame: FramePing})
+
+	entries, err := ReadFile(filepath.Join(dir, "abcd1234_efgh5678.jsonl"))
+	a.Nil(err)
+	a.Len(entries, 4)
+	a.Equal("abcd1234_efgh5678", entries[0].Session)
+	a.NotEmpty(entries[0].Timestamp)
+	a.Equal("notification", entries[0].MessageType)
+	a.Equal("channel.follow", entries[0].SubscriptionType)
+	a.Equal(`"not json"`, string(entries[1].Message))
+	a.Equal("sent close 4006 \"network error\"", Describe(entries[3]))
+
+	_, err = Read(strings.NewReader("{\"frame\":\"text\"}\nnot json\n"))
+	a.NotNil(err)
+}
+
+func TestDiff(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	welcome := Entry{Direction: DirectionSent, Frame: FrameText, MessageType: "session_welcome", Message: []byte(`{"metadata":{"message_id":"1"},"payload":{"session":{"id":"a_b","status":"connected"}}}`)}
+	welcome2 := Entry{Direction: DirectionSent, Frame: FrameText, MessageType: "session_welcome", Message: []byte(`{"metadata":{"message_id":"2"},"payload":{"session":{"id":"c_d","status":"connected"}}}`)}
+	follow := Entry{Direction: DirectionSent, Frame: FrameText, MessageType: "notification", SubscriptionType: "channel.follow", Message: []byte(`{"payload":{"event":{"user_id":"1","followed_at":"x"}}}`)}
+	follow2 := Entry{Direction: DirectionSent, Frame: FrameText, MessageType: "notification", SubscriptionType: "channel.follow", Message: []byte(`{"payload":{"event":{"user_id":"2","followed_at":"y"}}}`)}
+	keepalive := Entry{Direction: DirectionSent, Frame: FrameText, MessageType: "session_keepalive"}
+	ping := Entry{Direction: DirectionSent, Frame: FramePing}
+	closed := Entry{Direction: DirectionSent, Frame: FrameClose, CloseCode: 4006}
+
+	// IDs, timestamps, and pings are ignored
+	lines := Diff([]Entry{welcome, ping, follow}, []Entry{welcome2, follow})
+	var out bytes.Buffer
+	a.False(PrintDiff(&out, lines))
+	a.Equal("  sent session_welcome\n  sent notification (channel.follow)\n", out.String())
+
+	lines = Diff([]Entry{welcome, follow, keepalive}, []Entry{welcome, follow2, closed})
+	a.Len(lines, 4)
+	a.Equal(OpEqual, lines[0].Op)
+	a.Equal(OpChanged, lines[1].Op)
+	a.Equal([]string{`payload.event.user_id: "1" -> "2"`}, lines[1].Changes)
+	a.Equal(OpRemoved, lines[2].Op)
+	a.Equal("session_keepalive", lines[2].Entry.MessageType)
+	a.Equal(OpAdded, lines[3].Op)
+	a.Equal(4006, lines[3].Entry.CloseCode)
+
+	out.Reset()
+	a.True(PrintDiff(&out, lines))
+}
@@ -1,13 +1,27 @@
 package websocket
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/rpc"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/spf13/viper"
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/certs"
+	"github.com/twitchdev/twitch-cli/internal/events/websocket/client"
 	"github.com/twitchdev/twitch-cli/internal/events/websocket/mock_server"
-	rpc_handler "github.com/twitchdev/twitch-cli/internal/rpc"
+	"github.com/twitchdev/twitch-cli/internal/events/websocket/transcript"
 )
 
 type WebsocketCommandParameters struct {
@@ -16,60 +30,225 @@ type WebsocketCommandParameters struct {
 	SubscriptionStatus string
 	CloseReason        string
 	FeatureEnabled     bool
+
+	// Used with "reconnect"
+	Percent             float64
+	Waves               int
+	WaveInterval        time.Duration
+	GracePeriod         time.Duration
+	CloseOldConnections bool
 }
 
+// Sends a websocket sub-command to the mock EventSub WebSocket server's admin API
 func ForwardWebsocketCommand(cmd string, p WebsocketCommandParameters) error {
-	client, err := rpc.DialHTTP("tcp", ":44747")
-	if err != nil {
-		return fmt.Errorf("Failed to dial RPC handler for WebSocket server. Is it online?\nError: %v", err.Error())
+	var path string
+	var body interface{}
+
+	switch cmd {
+	case "reconnect":
+		// Waves and the grace period are always sent, so 0 reaches the server rather than its defaults
+		gracePeriodSeconds := int(math.Ceil(p.GracePeriod.Seconds()))
+		path = "/admin/reconnect"
+		body = admin.ReconnectRequest{
+			Sessions:            splitSessions(p.Client),
+			Percent:             p.Percent,
+			Waves:               &p.Waves,
+			WaveIntervalSeconds: int(math.Ceil(p.WaveInterval.Seconds())),
+			GracePeriodSeconds:  &gracePeriodSeconds,
+			CloseOldConnections: p.CloseOldConnections,
+		}
+
+	case "close":
+		// Invalid codes are sent as 0, which the server rejects along with usage info
+		closeCode, _ := strconv.Atoi(p.CloseReason)
+		path = "/admin/sessions/close"
+		body = admin.CloseRequest{
+			Session: p.Client,
+			Code:    closeCode,
+		}
+
+	case "subscription":
+		path = "/admin/subscriptions/status"
+		body = admin.SubscriptionStatusRequest{
+			SubscriptionID: p.Subscription,
+			Status:         p.SubscriptionStatus,
+		}
+
+	case "keepalive":
+		path = "/admin/sessions/keepalive"
+		body = admin.KeepaliveRequest{
+			Session: p.Client,
+			Enabled: &p.FeatureEnabled,
+		}
+
+	default:
+		return fmt.Errorf("Invalid websocket sub-command: %v", cmd)
 	}
 
-	var reply rpc_handler.RPCResponse
+	_, err := admin.Call(http.MethodPost, path, body, nil)
 
-	rpcName := mock_server.ResolveRPCName(cmd)
-	if rpcName == "" {
-		return fmt.Errorf("Invalid websocket command")
+	var apiErr *admin.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Status == http.StatusBadRequest {
+			return fmt.Errorf(
+				color.New().Add(color.FgRed).Sprintln(fmt.Sprintf("✗ Command rejected for invalid flags:\n%v", apiErr.Message)),
+			)
+		}
+		return fmt.Errorf(
+			color.New().Add(color.FgRed).Sprintln(fmt.Sprintf("✗ EventSub WebSocket server failed to process command:\n%v", apiErr.Message)),
+		)
+	} else if err != nil {
+		return err
 	}
 
-	// Command line flags to be passed with the command
-	// Add them all, as it wont hurt anything if they're not relevant
-	variables := make(map[string]string)
-	variables["ClientName"] = p.Client
-	variables["SubscriptionID"] = p.Subscription
-	variables["SubscriptionStatus"] = p.SubscriptionStatus
-	variables["CloseReason"] = p.CloseReason
-	variables["FeatureEnabled"] = strconv.FormatBool(p.FeatureEnabled)
+	color.New().Add(color.FgGreen).Println(fmt.Sprintf("✔ Forwarded for use in mock EventSub WebSocket server\n"))
+	return nil
+}
+
+// --session takes a comma-separated list of sessions with "reconnect"
+func splitSessions(sessions string) []string {
+	split := []string{}
+	for _, s := range strings.Split(sessions, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			split = append(split, s)
+		}
+	}
+	return split
+}
+
+// Prints the sessions connected to the mock EventSub WebSocket server, as a table or as JSON
+func ListSessions(asJSON bool) error {
+	sessions := []mock_server.AdminSession{}
+	_, err := admin.Call(http.MethodGet, "/admin/sessions", nil, &sessions)
+	if err != nil {
+		return err
+	}
 
-	args := &rpc_handler.RPCArgs{
-		RPCName:   rpcName,
-		Variables: variables,
+	if asJSON {
+		return printJSON(sessions)
 	}
+	return mock_server.PrintSessions(os.Stdout, sessions)
+}
 
-	err = client.Call("RPCHandler.ExecuteGenericRPC", args, &reply)
+// Prints the subscriptions on the mock EventSub WebSocket server, or only those of a session, as a table or as JSON
+func ListSubscriptions(session string, asJSON bool) error {
+	path := "/admin/subscriptions"
+	if session != "" {
+		path += "?session=" + url.QueryEscape(session)
+	}
 
+	subscriptions := []mock_server.SubscriptionPostSuccessResponseBody{}
+	_, err := admin.Call(http.MethodGet, path, nil, &subscriptions)
 	if err != nil {
-		return fmt.Errorf("Failed to call RPC method RPCHandler.ExecuteGenericRPC: %v", err.Error())
+		return err
 	}
 
-	switch reply.ResponseCode {
-	case mock_server.COMMAND_RESPONSE_SUCCESS:
-		color.New().Add(color.FgGreen).Println(fmt.Sprintf("✔ Forwarded for use in mock EventSub WebSocket server\n"))
-		return nil
+	if asJSON {
+		return printJSON(subscriptions)
+	}
+	return mock_server.PrintSubscriptions(os.Stdout, subscriptions)
+}
 
-	case mock_server.COMMAND_RESPONSE_FAILED_ON_SERVER:
-		return fmt.Errorf(
-			color.New().Add(color.FgRed).Sprintln(fmt.Sprintf("✗ EventSub WebSocket server failed to process command:\n%v", reply.DetailedInfo)),
-		)
+// Prints the differences between two session transcripts recorded with --record-dir. Returns an error if they differ,
+// so the command exits with a non-zero status.
+func DiffTranscripts(pathA string, pathB string, asJSON bool) error {
+	a, err := transcript.ReadFile(pathA)
+	if err != nil {
+		return err
+	}
+	b, err := transcript.ReadFile(pathB)
+	if err != nil {
+		return err
+	}
 
-	case mock_server.COMMAND_RESPONSE_MISSING_FLAG:
-		return fmt.Errorf(
-			color.New().Add(color.FgRed).Sprintln(fmt.Sprintf("✗ Command rejected for invalid flags:\n%v", reply.DetailedInfo)),
-		)
+	lines := transcript.Diff(a, b)
+
+	different := false
+	if asJSON {
+		for _, l := range lines {
+			different = different || l.Op != transcript.OpEqual
+		}
+		if err := printJSON(lines); err != nil {
+			return err
+		}
+	} else {
+		different = transcript.PrintDiff(os.Stdout, lines)
+	}
 
-	case mock_server.COMMAND_RESPONSE_INVALID_CMD:
-		return fmt.Errorf("Invalid websocket sub-command: %v", cmd)
+	if different {
+		return errors.New("Transcripts differ")
+	}
+	return nil
+}
+
+type ConnectParameters struct {
+	Topics           []string // Topics to subscribe to, as type:version
+	Condition        []string // Condition of the subscriptions, as key=value
+	ClientID         string   // Defaults to the configured client ID
+	Token            string   // Defaults to the configured access token
+	SubscriptionsURL string
+	JSON             bool
+	Debug            bool
+}
+
+// Connects to an EventSub WebSocket server with the reference client, printing the messages it receives until
+// interrupted with Ctrl + C
+func Connect(wsURL string, p ConnectParameters) error {
+	o := client.Options{
+		URL:              wsURL,
+		SubscriptionsURL: p.SubscriptionsURL,
+		ClientID:         p.ClientID,
+		Token:            p.Token,
+		JSON:             p.JSON,
+		Debug:            p.Debug,
+		Out:              os.Stdout,
+		Log:              os.Stdout,
+	}
+	if p.JSON {
+		// Keeps stdout to one message per line
+		o.Log = os.Stderr
+	}
 
+	for _, t := range p.Topics {
+		topic, err := client.ParseTopic(t)
+		if err != nil {
+			return err
+		}
+		o.Topics = append(o.Topics, topic)
 	}
+	condition, err := client.ParseCondition(p.Condition)
+	if err != nil {
+		return err
+	}
+	o.Condition = condition
+
+	if len(o.Topics) > 0 {
+		if o.ClientID == "" {
+			o.ClientID = viper.GetString("clientId")
+		}
+		if o.Token == "" {
+			o.Token = viper.GetString("accessToken")
+		}
+	}
+
+	// Servers started with --ssl use a certificate signed by the CLI's local CA
+	roots, err := certs.RootCAs()
+	if err != nil {
+		return err
+	}
+	o.TLSConfig = &tls.Config{RootCAs: roots}
 
-	return fmt.Errorf("RPCHandler experienced unexpected response code: %v", reply.ResponseCode)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return client.Run(ctx, o)
+}
+
+func printJSON(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
 }
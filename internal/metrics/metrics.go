@@ -0,0 +1,355 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics serves the mock servers' /metrics endpoints in the Prometheus text exposition format. It implements the
+// subset of metric types the servers need: counters, gauges computed when scraped, and histograms.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Buckets for request latencies, in seconds. Mock handlers mostly run in well under a millisecond.
+var LatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+type metric interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry holds the metrics reported by a server's /metrics endpoint. It's safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.metrics {
+		if existing.name() == m.name() {
+			panic(fmt.Sprintf("metric %v registered twice", m.name()))
+		}
+	}
+	r.metrics = append(r.metrics, m)
+	sort.Slice(r.metrics, func(i, j int) bool { return r.metrics[i].name() < r.metrics[j].name() })
+}
+
+// Write writes every metric in the text exposition format, sorted by name.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]metric{}, r.metrics...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry's metrics, to be registered at /metrics.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		r.Write(w)
+	})
+}
+
+// Labels and values of a single series
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// Series of a metric, keyed by their label values
+type seriesSet struct {
+	labels []string
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newSeriesSet(labels []string) seriesSet {
+	return seriesSet{labels: labels, series: make(map[string]*series)}
+}
+
+// Returns the series with the given label values, creating it if needed. Must be called with mu held.
+func (s *seriesSet) get(labelValues []string) *series {
+	if len(labelValues) != len(s.labels) {
+		panic(fmt.Sprintf("expected %v label values, got %v", len(s.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	if existing, ok := s.series[key]; ok {
+		return existing
+	}
+	created := &series{labelValues: append([]string{}, labelValues...)}
+	s.series[key] = created
+	return created
+}
+
+// Returns the series sorted by their label values. Must be called with mu held.
+func (s *seriesSet) sorted() []*series {
+	sorted := make([]*series, 0, len(s.series))
+	for _, v := range s.series {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.Join(sorted[i].labelValues, "\xff") < strings.Join(sorted[j].labelValues, "\xff")
+	})
+	return sorted
+}
+
+// Counter is a value that only goes up, such as the number of messages sent, split by its labels.
+type Counter struct {
+	metricName string
+	help       string
+	seriesSet
+}
+
+// Counter registers a counter with the given label names. Names of counters should end in _total.
+func (r *Registry) Counter(name string, help string, labels ...string) *Counter {
+	c := &Counter{metricName: name, help: help, seriesSet: newSeriesSet(labels)}
+	r.register(c)
+	return c
+}
+
+// Inc adds 1 to the series with the given label values.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the series with the given label values.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("counters cannot decrease")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.get(labelValues).value += v
+}
+
+func (c *Counter) name() string {
+	return c.metricName
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeHeader(w, c.metricName, c.help, "counter")
+	for _, s := range c.sorted() {
+		writeSample(w, c.metricName, c.labels, s.labelValues, s.value)
+	}
+}
+
+// GaugeFunc is a value that can go up and down, such as the number of connected sessions, computed when scraped.
+type GaugeFunc struct {
+	metricName string
+	help       string
+	labels     []string
+	collect    func(set func(value float64, labelValues ...string))
+}
+
+// GaugeFunc registers a gauge whose series are computed by collect on every scrape. collect calls set once for each
+// series; Series it doesn't set aren't reported.
+func (r *Registry) GaugeFunc(name string, help string, labels []string, collect func(set func(value float64, labelValues ...string))) {
+	r.register(&GaugeFunc{metricName: name, help: help, labels: labels, collect: collect})
+}
+
+func (g *GaugeFunc) name() string {
+	return g.metricName
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	set := newSeriesSet(g.labels)
+	g.collect(func(value float64, labelValues ...string) {
+		set.get(labelValues).value = value
+	})
+
+	writeHeader(w, g.metricName, g.help, "gauge")
+	for _, s := range set.sorted() {
+		writeSample(w, g.metricName, g.labels, s.labelValues, s.value)
+	}
+}
+
+// Histogram counts observations, such as request latencies, in buckets.
+type Histogram struct {
+	metricName string
+	help       string
+	buckets    []float64
+	labels     []string
+	mu         sync.Mutex
+	series     map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // Observations in each bucket, not cumulative; The last is for +Inf
+	sum         float64
+}
+
+// Histogram registers a histogram with the given upper bounds for its buckets, which must be sorted. A +Inf bucket is
+// always added.
+func (r *Registry) Histogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		metricName: name,
+		help:       help,
+		buckets:    buckets,
+		labels:     labels,
+		series:     make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+// Observe records v in the series with the given label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	if len(labelValues) != len(h.labels) {
+		panic(fmt.Sprintf("expected %v label values, got %v", len(h.labels), len(labelValues)))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labelValues: append([]string{}, labelValues...), counts: make([]uint64, len(h.buckets)+1)}
+		h.series[key] = s
+	}
+	s.counts[sort.SearchFloat64s(h.buckets, v)]++
+	s.sum += v
+}
+
+func (h *Histogram) name() string {
+	return h.metricName
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bucketLabels := append(append([]string{}, h.labels...), "le")
+	writeHeader(w, h.metricName, h.help, "histogram")
+	for _, k := range keys {
+		s := h.series[k]
+		cumulative := uint64(0)
+		for i, count := range s.counts {
+			cumulative += count
+			le := math.Inf(1)
+			if i < len(h.buckets) {
+				le = h.buckets[i]
+			}
+			writeSample(w, h.metricName+"_bucket", bucketLabels, append(append([]string{}, s.labelValues...), formatFloat(le)), float64(cumulative))
+		}
+		writeSample(w, h.metricName+"_sum", h.labels, s.labelValues, s.sum)
+		writeSample(w, h.metricName+"_count", h.labels, s.labelValues, float64(cumulative))
+	}
+}
+
+// HTTPMetrics counts the requests served by a server's handlers, and how long the handlers took.
+type HTTPMetrics struct {
+	requests *Counter
+	duration *Histogram
+}
+
+// NewHTTPMetrics registers twitch_cli_http_requests_total and twitch_cli_http_request_duration_seconds.
+func NewHTTPMetrics(r *Registry) *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: r.Counter("twitch_cli_http_requests_total", "HTTP requests served, by endpoint, method, and status code.", "endpoint", "method", "code"),
+		duration: r.Histogram("twitch_cli_http_request_duration_seconds", "Time taken by HTTP handlers, by endpoint and method.", LatencyBuckets, "endpoint", "method"),
+	}
+}
+
+// Middleware records requests to next as requests to endpoint. endpoint should be the path the handler is registered
+// at, rather than the request's path, to keep the number of series bounded.
+func (m *HTTPMetrics) Middleware(endpoint string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		m.duration.Observe(time.Since(start).Seconds(), endpoint, r.Method)
+		m.requests.Inc(endpoint, r.Method, strconv.Itoa(sw.status))
+	})
+}
+
+// Records the status code written by a handler
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func writeHeader(w io.Writer, name string, help string, metricType string) {
+	fmt.Fprintf(w, "# HELP %v %v\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(w, "# TYPE %v %v\n", name, metricType)
+}
+
+func writeSample(w io.Writer, name string, labels []string, labelValues []string, value float64) {
+	io.WriteString(w, name)
+	if len(labels) > 0 {
+		pairs := make([]string, len(labels))
+		for i, l := range labels {
+			pairs[i] = fmt.Sprintf(`%v="%v"`, l, escapeLabelValue(labelValues[i]))
+		}
+		fmt.Fprintf(w, "{%v}", strings.Join(pairs, ","))
+	}
+	fmt.Fprintf(w, " %v\n", formatFloat(value))
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
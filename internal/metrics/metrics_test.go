@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestRegistry(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	r := NewRegistry()
+	sent := r.Counter("test_sent_total", "Messages sent.", "type")
+	sessions := 2
+	r.GaugeFunc("test_sessions", "Connected sessions.", nil, func(set func(float64, ...string)) {
+		set(float64(sessions))
+	})
+	latency := r.Histogram("test_latency_seconds", "Latency.", []float64{0.1, 1}, "endpoint")
+
+	sent.Inc("channel.follow")
+	sent.Add(2, "channel.ban")
+	sent.Inc(`quote"d`)
+	latency.Observe(0.1, "/a")
+	latency.Observe(0.5, "/a")
+	latency.Observe(5, "/a")
+
+	var b bytes.Buffer
+	a.Nil(r.Write(&b))
+	a.Equal(`# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{endpoint="/a",le="0.1"} 1
+test_latency_seconds_bucket{endpoint="/a",le="1"} 2
+test_latency_seconds_bucket{endpoint="/a",le="+Inf"} 3
+test_latency_seconds_sum{endpoint="/a"} 5.6
+test_latency_seconds_count{endpoint="/a"} 3
+# HELP test_sent_total Messages sent.
+# TYPE test_sent_total counter
+test_sent_total{type="channel.ban"} 2
+test_sent_total{type="channel.follow"} 1
+test_sent_total{type="quote\"d"} 1
+# HELP test_sessions Connected sessions.
+# TYPE test_sessions gauge
+test_sessions 2
+`, b.String())
+
+	// Gauges are computed on every scrape
+	sessions = 0
+	b.Reset()
+	a.Nil(r.Write(&b))
+	a.Contains(b.String(), "test_sessions 0\n")
+
+	a.Panics(func() { r.Counter("test_sent_total", "Registered twice.") })
+	a.Panics(func() { sent.Inc() })
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	r := NewRegistry()
+	m := NewHTTPMetrics(r)
+	h := m.Middleware("/mock/users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte("{}"))
+	}))
+
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPost} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/mock/users?id=1", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	a.Equal(http.StatusOK, rec.Code)
+	a.Equal(contentType, rec.Header().Get("Content-Type"))
+	a.Contains(rec.Body.String(), `twitch_cli_http_requests_total{endpoint="/mock/users",method="GET",code="200"} 2`)
+	a.Contains(rec.Body.String(), `twitch_cli_http_requests_total{endpoint="/mock/users",method="POST",code="400"} 1`)
+	a.Contains(rec.Body.String(), `twitch_cli_http_request_duration_seconds_count{endpoint="/mock/users",method="GET"} 2`)
+
+	rec = httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	a.Equal(http.StatusMethodNotAllowed, rec.Code)
+}
@@ -12,8 +12,10 @@ import (
 	"time"
 
 	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/internal/util"
 	"github.com/twitchdev/twitch-cli/test_setup"
+	"github.com/twitchdev/twitch-cli/test_setup/test_eventsub"
 	"github.com/twitchdev/twitch-cli/test_setup/test_server"
 )
 
@@ -157,6 +159,71 @@ func TestRedemption(t *testing.T) {
 
 }
 
+func TestRedemptionEvents(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+	ts := test_server.SetupTestServer(Redemption{})
+	a.Nil(test_server.InsertTestUsers())
+
+	db, err := database.NewConnection(true)
+	a.Nil(err)
+	defer db.DB.Close()
+	redemption := database.ChannelPointsRedemption{
+		ID:               util.RandomGUID(),
+		BroadcasterID:    "1",
+		UserID:           "2",
+		RedemptionStatus: "UNFULFILLED",
+		RewardID:         rewardID,
+		RedeemedAt:       util.GetTimestamp().Format(time.RFC3339),
+	}
+	a.Nil(db.NewQuery(nil, 100).InsertChannelPointsRedemption(redemption))
+	events := test_eventsub.Record(t)
+
+	b, _ := json.Marshal(PatchRedemptionBody{Status: "CANCELED"})
+	req, _ := http.NewRequest(http.MethodPatch, ts.URL+Redemption{}.Path(), bytes.NewBuffer(b))
+	q := req.URL.Query()
+	q.Set("broadcaster_id", "1")
+	q.Set("reward_id", rewardID)
+	q.Set("id", redemption.ID)
+	req.URL.RawQuery = q.Encode()
+	resp, err := http.DefaultClient.Do(req)
+	a.Nil(err)
+	a.Equal(200, resp.StatusCode)
+
+	var body struct {
+		Data []database.ChannelPointsRedemption `json:"data"`
+	}
+	a.Nil(json.NewDecoder(resp.Body).Decode(&body))
+	a.Len(body.Data, 1)
+	r := body.Data[0]
+
+	// The update matches the redemption returned
+	var event models.RedemptionEventSubEvent
+	subscription := events.Next(&event)
+	a.Equal("channel.channel_points_custom_reward_redemption.update", subscription.Type)
+	a.Equal("1", subscription.Version)
+	a.Equal("1", subscription.Condition.BroadcasterUserID)
+	a.Equal(redemption.ID, event.ID)
+	a.Equal(r.ID, event.ID)
+	a.Equal(r.BroadcasterID, event.BroadcasterUserID)
+	a.Equal(r.BroadcasterLogin, event.BroadcasterUserLogin)
+	a.Equal("2", event.UserID)
+	a.Equal(r.UserLogin, event.UserLogin)
+	a.Equal("canceled", event.Status)
+	a.Equal(r.ChannelPointsRedemptionRewardInfo.ID, event.Reward.ID)
+	a.Equal(rewardID, event.Reward.ID)
+	a.Equal(r.Title, event.Reward.Title)
+	a.Equal(int64(r.Cost), event.Reward.Cost)
+	a.Equal(r.RedeemedAt, event.RedeemedAt)
+
+	// Redemptions can't be updated twice, so nothing is sent
+	req, _ = http.NewRequest(http.MethodPatch, ts.URL+Redemption{}.Path(), bytes.NewBuffer(b))
+	req.URL.RawQuery = q.Encode()
+	resp, err = http.DefaultClient.Do(req)
+	a.Nil(err)
+	a.NotEqual(200, resp.StatusCode)
+	events.Empty()
+}
+
 func TestRewards(t *testing.T) {
 	a := test_setup.SetupTestEnv(t)
 
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/authentication"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_errors"
+	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_eventsub"
 	"github.com/twitchdev/twitch-cli/internal/models"
 )
 
@@ -200,4 +202,28 @@ func patchRedemptions(w http.ResponseWriter, r *http.Request) {
 
 	bytes, _ := json.Marshal(models.APIResponse{Data: responseData})
 	w.Write(bytes)
+
+	for _, redemption := range responseData {
+		mock_eventsub.Emit("channel.channel_points_custom_reward_redemption.update", "1",
+			models.EventsubCondition{BroadcasterUserID: redemption.BroadcasterID},
+			models.RedemptionEventSubEvent{
+				ID:                   redemption.ID,
+				BroadcasterUserID:    redemption.BroadcasterID,
+				BroadcasterUserLogin: redemption.BroadcasterLogin,
+				BroadcasterUserName:  redemption.BroadcasterName,
+				UserID:               redemption.UserID,
+				UserLogin:            redemption.UserLogin,
+				UserName:             redemption.UserName,
+				UserInput:            redemption.UserInput.String,
+				Status:               strings.ToLower(redemption.RedemptionStatus),
+				Reward: models.RedemptionReward{
+					ID:     redemption.RewardID,
+					Title:  redemption.Title,
+					Cost:   int64(redemption.Cost),
+					Prompt: redemption.RewardPrompt,
+				},
+				RedeemedAt: redemption.RedeemedAt,
+			},
+		)
+	}
 }
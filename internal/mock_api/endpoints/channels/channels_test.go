@@ -6,12 +6,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/internal/util"
 	"github.com/twitchdev/twitch-cli/test_setup"
+	"github.com/twitchdev/twitch-cli/test_setup/test_eventsub"
 	"github.com/twitchdev/twitch-cli/test_setup/test_server"
 )
 
@@ -190,6 +194,98 @@ func TestInformation(t *testing.T) {
 	a.Equal(400, resp.StatusCode)
 }
 
+func TestInformationEvents(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+	ts := test_server.SetupTestServer(InformationEndpoint{})
+	a.Nil(test_server.InsertTestUsers())
+	events := test_eventsub.Record(t)
+
+	// Enables only the given label, out of those broadcasters can set
+	patch := func(label string) {
+		body := PatchInformationEndpointRequest{BroadcasterLanguage: "en", Title: "Events " + label}
+		for _, ccl := range models.CCL_MAP {
+			if !ccl.RestrictedGaming {
+				body.ContentClassificationLabels = append(body.ContentClassificationLabels, PatchInformationEndpointRequestLabel{ID: ccl.ID, IsEnabled: ccl.ID == label})
+			}
+		}
+		b, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPatch, ts.URL+InformationEndpoint{}.Path()+"?broadcaster_id=1", bytes.NewBuffer(b))
+		resp, err := http.DefaultClient.Do(req)
+		a.Nil(err)
+		a.Equal(204, resp.StatusCode)
+	}
+	get := func() Channel {
+		resp, err := http.Get(ts.URL + InformationEndpoint{}.Path() + "?broadcaster_id=1")
+		a.Nil(err)
+		var channels struct {
+			Data []Channel `json:"data"`
+		}
+		a.Nil(json.NewDecoder(resp.Body).Decode(&channels))
+		a.Len(channels.Data, 1)
+		return channels.Data[0]
+	}
+
+	// Both versions of channel.update are sent, matching the updated channel
+	patch("Gambling")
+	channel := get()
+
+	var v1 models.ChannelUpdateEventSubEvent
+	subscription := events.Next(&v1)
+	a.Equal("channel.update", subscription.Type)
+	a.Equal("1", subscription.Version)
+	a.Equal("1", subscription.Condition.BroadcasterUserID)
+	a.Equal(channel.ID, v1.BroadcasterUserID)
+	a.Equal(channel.UserLogin, v1.BroadcasterUserLogin)
+	a.Equal("Events Gambling", v1.StreamTitle)
+	a.Equal(channel.Title, v1.StreamTitle)
+	a.Equal(channel.Language, v1.StreamLanguage)
+	a.NotNil(v1.IsMature)
+	a.True(*v1.IsMature) // Channels with content classification labels are mature
+	a.Nil(v1.ContentClassificationLabels)
+
+	var v2 models.ChannelUpdateEventSubEvent
+	subscription = events.Next(&v2)
+	a.Equal("channel.update", subscription.Type)
+	a.Equal("2", subscription.Version)
+	a.Equal(channel.Title, v2.StreamTitle)
+	a.Equal([]string{"Gambling"}, channel.ContentClassificationLabels)
+	a.Equal(channel.ContentClassificationLabels, v2.ContentClassificationLabels)
+	a.Nil(v2.IsMature)
+
+	// Labels are replaced by later updates
+	patch("ProfanityVulgarity")
+	events.Next(&models.ChannelUpdateEventSubEvent{})
+	v2 = models.ChannelUpdateEventSubEvent{}
+	subscription = events.Next(&v2)
+	a.Equal("2", subscription.Version)
+	a.Equal([]string{"ProfanityVulgarity"}, v2.ContentClassificationLabels)
+	a.Equal(get().ContentClassificationLabels, v2.ContentClassificationLabels)
+
+	// Channels without labels aren't mature
+	var err error
+	db, err = database.NewConnection(true)
+	a.Nil(err)
+	defer db.DB.Close()
+	q := db.NewQuery(nil, 100)
+	id := util.RandomUserID()
+	a.Nil(q.InsertUser(database.User{ID: id, UserLogin: "events_user", DisplayName: "events_user", Language: "en", CreatedAt: util.GetTimestamp().Format(time.RFC3339), ModifiedAt: util.GetTimestamp().Format(time.RFC3339)}, false))
+	emitChannelUpdate(httptest.NewRequest(http.MethodPatch, "/", nil), id)
+
+	v1 = models.ChannelUpdateEventSubEvent{}
+	subscription = events.Next(&v1)
+	a.Equal("1", subscription.Version)
+	a.Equal(id, subscription.Condition.BroadcasterUserID)
+	a.Equal(id, v1.BroadcasterUserID)
+	a.NotNil(v1.IsMature)
+	a.False(*v1.IsMature)
+
+	v2 = models.ChannelUpdateEventSubEvent{}
+	subscription = events.Next(&v2)
+	a.Equal("2", subscription.Version)
+	a.Empty(v2.ContentClassificationLabels)
+	events.Empty()
+}
+
 func TestFollowed(t *testing.T) {
 	a := test_setup.SetupTestEnv(t)
 	ts := test_server.SetupTestServer(FollowedEndpoint{})
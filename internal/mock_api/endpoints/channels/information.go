@@ -12,6 +12,7 @@ import (
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/authentication"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_errors"
+	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_eventsub"
 	"github.com/twitchdev/twitch-cli/internal/models"
 )
 
@@ -201,6 +202,39 @@ func patchInformation(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusNoContent)
+
+	emitChannelUpdate(r, broadcasterID)
+}
+
+// Sends both versions of channel.update, built from the channel's updated information, to the mock EventSub server
+func emitChannelUpdate(r *http.Request, broadcasterID string) {
+	dbr, err := db.NewQuery(r, 100).GetChannels(database.User{ID: broadcasterID})
+	if err != nil || len(dbr.Data.([]database.User)) == 0 {
+		return
+	}
+	c := convertUsers(dbr.Data.([]database.User))[0]
+
+	event := models.ChannelUpdateEventSubEvent{
+		BroadcasterUserID:    c.ID,
+		BroadcasterUserLogin: c.UserLogin,
+		BroadcasterUserName:  c.DisplayName,
+		StreamTitle:          c.Title,
+		StreamLanguage:       c.Language,
+		StreamCategoryID:     c.CategoryID,
+		StreamCategoryName:   c.CategoryName,
+	}
+	condition := models.EventsubCondition{BroadcasterUserID: broadcasterID}
+
+	// Version 1 predates content classification labels, which replaced the mature flag, so channels with any label are
+	// reported as mature
+	v1 := event
+	isMature := len(c.ContentClassificationLabels) > 0
+	v1.IsMature = &isMature
+	mock_eventsub.Emit("channel.update", "1", condition, v1)
+
+	v2 := event
+	v2.ContentClassificationLabels = c.ContentClassificationLabels
+	mock_eventsub.Emit("channel.update", "2", condition, v2)
 }
 
 func convertUsers(users []database.User) []Channel {
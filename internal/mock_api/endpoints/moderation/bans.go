@@ -11,6 +11,7 @@ import (
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/authentication"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_errors"
+	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_eventsub"
 	"github.com/twitchdev/twitch-cli/internal/models"
 )
 
@@ -218,6 +219,23 @@ func postBans(w http.ResponseWriter, r *http.Request) {
 
 	bytes, _ := json.Marshal(models.APIResponse{Data: []PostBansResponseBodyData{response}})
 	w.Write(bytes)
+
+	moderator, _ := db.NewQuery(r, 100).GetUser(database.User{ID: moderatorID})
+	mock_eventsub.Emit("channel.ban", "1", models.EventsubCondition{BroadcasterUserID: broadcasterID}, models.BanEventSubEvent{
+		UserID:               foundUser.ID,
+		UserLogin:            foundUser.UserLogin,
+		UserName:             foundUser.DisplayName,
+		BroadcasterUserID:    broadcaster.ID,
+		BroadcasterUserLogin: broadcaster.UserLogin,
+		BroadcasterUserName:  broadcaster.DisplayName,
+		ModeratorUserId:      moderatorID,
+		ModeratorUserLogin:   moderator.UserLogin,
+		ModeratorUserName:    moderator.DisplayName,
+		Reason:               body.Data.Reason,
+		BannedAt:             timeNow,
+		EndsAt:               timeLater,
+		IsPermanent:          timeLater == nil,
+	})
 }
 
 func deleteBans(w http.ResponseWriter, r *http.Request) {
@@ -307,4 +325,17 @@ func deleteBans(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusNoContent)
+
+	moderator, _ := db.NewQuery(r, 100).GetUser(database.User{ID: moderatorID})
+	mock_eventsub.Emit("channel.unban", "1", models.EventsubCondition{BroadcasterUserID: broadcasterID}, models.UnbanEventSubEvent{
+		UserID:               bannedUser.ID,
+		UserLogin:            bannedUser.UserLogin,
+		UserName:             bannedUser.DisplayName,
+		BroadcasterUserID:    broadcaster.ID,
+		BroadcasterUserLogin: broadcaster.UserLogin,
+		BroadcasterUserName:  broadcaster.DisplayName,
+		ModeratorUserId:      moderatorID,
+		ModeratorUserLogin:   moderator.UserLogin,
+		ModeratorUserName:    moderator.DisplayName,
+	})
 }
@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/test_setup"
+	"github.com/twitchdev/twitch-cli/test_setup/test_eventsub"
 	"github.com/twitchdev/twitch-cli/test_setup/test_server"
 )
 
@@ -191,3 +194,86 @@ func TestBans(t *testing.T) {
 	a.Nil(err)
 	a.Equal(204, resp.StatusCode)
 }
+
+func TestModerationEvents(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+	moderators := test_server.SetupTestServer(Moderators{})
+	bans := test_server.SetupTestServer(Bans{})
+
+	// User 2 starts out as a regular user of the channel
+	a.Nil(test_server.InsertTestUsers())
+	db, err := database.NewConnection(true)
+	a.Nil(err)
+	dbq := db.NewQuery(nil, 100)
+	dbq.DeleteBan(database.UserRequestParams{BroadcasterID: "1", UserID: "2"})
+	dbq.RemoveModerator("1", "2")
+	dbq.DeleteVIP("1", "2")
+	db.DB.Close()
+
+	events := test_eventsub.Record(t)
+
+	do := func(method string, url string, body interface{}) *http.Response {
+		var b []byte
+		if body != nil {
+			b, _ = json.Marshal(body)
+		}
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(b))
+		resp, err := http.DefaultClient.Do(req)
+		a.Nil(err)
+		return resp
+	}
+
+	// Adding and removing a moderator sends channel.moderator.add and channel.moderator.remove
+	resp := do(http.MethodPost, moderators.URL+Moderators{}.Path()+"?broadcaster_id=1&user_id=2", nil)
+	a.Equal(204, resp.StatusCode)
+	var moderator models.ModeratorChangeEventSubEvent
+	subscription := events.Next(&moderator)
+	a.Equal("channel.moderator.add", subscription.Type)
+	a.Equal("1", subscription.Condition.BroadcasterUserID)
+	a.Equal("2", moderator.UserID)
+	a.Equal("second_user", moderator.UserLogin)
+	a.Equal("1", moderator.BroadcasterUserID)
+	a.Equal("testing_user1", moderator.BroadcasterUserLogin)
+
+	resp = do(http.MethodDelete, moderators.URL+Moderators{}.Path()+"?broadcaster_id=1&user_id=2", nil)
+	a.Equal(204, resp.StatusCode)
+	moderator = models.ModeratorChangeEventSubEvent{}
+	subscription = events.Next(&moderator)
+	a.Equal("channel.moderator.remove", subscription.Type)
+	a.Equal("2", moderator.UserID)
+
+	// Banning a user sends channel.ban, matching the ban in the response
+	resp = do(http.MethodPost, bans.URL+Bans{}.Path()+"?broadcaster_id=1&moderator_id=1", PostBansRequestBody{
+		Data: PostBansRequestBodyData{UserID: "2", Reason: "events", Duration: 600},
+	})
+	a.Equal(200, resp.StatusCode)
+	var banned struct {
+		Data []PostBansResponseBodyData `json:"data"`
+	}
+	a.Nil(json.NewDecoder(resp.Body).Decode(&banned))
+	a.Len(banned.Data, 1)
+
+	var ban models.BanEventSubEvent
+	subscription = events.Next(&ban)
+	a.Equal("channel.ban", subscription.Type)
+	a.Equal("1", subscription.Condition.BroadcasterUserID)
+	a.Equal(banned.Data[0].UserID, ban.UserID)
+	a.Equal("second_user", ban.UserLogin)
+	a.Equal(banned.Data[0].ModeratorID, ban.ModeratorUserId)
+	a.Equal("events", ban.Reason)
+	a.Equal(banned.Data[0].CreatedAt, ban.BannedAt)
+	a.Equal(banned.Data[0].EndTime, ban.EndsAt)
+	a.False(ban.IsPermanent)
+
+	resp = do(http.MethodDelete, bans.URL+Bans{}.Path()+"?broadcaster_id=1&moderator_id=1&user_id=2", nil)
+	a.Equal(204, resp.StatusCode)
+	var unban models.UnbanEventSubEvent
+	subscription = events.Next(&unban)
+	a.Equal("channel.unban", subscription.Type)
+	a.Equal("2", unban.UserID)
+
+	// Failed requests don't send events
+	resp = do(http.MethodDelete, bans.URL+Bans{}.Path()+"?broadcaster_id=1&moderator_id=1&user_id=2", nil)
+	a.Equal(400, resp.StatusCode)
+	events.Empty()
+}
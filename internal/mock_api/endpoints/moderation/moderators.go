@@ -10,6 +10,7 @@ import (
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/authentication"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_errors"
+	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_eventsub"
 	"github.com/twitchdev/twitch-cli/internal/models"
 )
 
@@ -180,6 +181,8 @@ func postModerators(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusNoContent)
+
+	emitModeratorChange(r, "channel.moderator.add", broadcasterID, user)
 }
 
 func deleteModerators(w http.ResponseWriter, r *http.Request) {
@@ -231,4 +234,20 @@ func deleteModerators(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusNoContent)
+
+	user, _ := db.NewQuery(r, 100).GetUser(database.User{ID: userID})
+	emitModeratorChange(r, "channel.moderator.remove", broadcasterID, user)
+}
+
+// Sends channel.moderator.add or channel.moderator.remove for the user to the mock EventSub server
+func emitModeratorChange(r *http.Request, topic string, broadcasterID string, user database.User) {
+	broadcaster, _ := db.NewQuery(r, 100).GetUser(database.User{ID: broadcasterID})
+	mock_eventsub.Emit(topic, "1", models.EventsubCondition{BroadcasterUserID: broadcasterID}, models.ModeratorChangeEventSubEvent{
+		UserID:               user.ID,
+		UserLogin:            user.UserLogin,
+		UserName:             user.DisplayName,
+		BroadcasterUserID:    broadcasterID,
+		BroadcasterUserLogin: broadcaster.UserLogin,
+		BroadcasterUserName:  broadcaster.DisplayName,
+	})
 }
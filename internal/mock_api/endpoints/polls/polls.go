@@ -5,11 +5,13 @@ package polls
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/authentication"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_errors"
+	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_eventsub"
 	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/internal/util"
 )
@@ -198,6 +200,8 @@ func postPolls(w http.ResponseWriter, r *http.Request) {
 	}
 
 	json.NewEncoder(w).Encode(models.APIResponse{Data: []database.Poll{poll}})
+
+	mock_eventsub.Emit("channel.poll.begin", "1", models.EventsubCondition{BroadcasterUserID: poll.BroadcasterID}, pollEvent(poll))
 }
 
 func patchPolls(w http.ResponseWriter, r *http.Request) {
@@ -243,4 +247,43 @@ func patchPolls(w http.ResponseWriter, r *http.Request) {
 
 	bytes, _ := json.Marshal(apiResponse)
 	w.Write(bytes)
+
+	for _, poll := range dbr.Data.([]database.Poll) {
+		mock_eventsub.Emit("channel.poll.end", "1", models.EventsubCondition{BroadcasterUserID: poll.BroadcasterID}, pollEvent(poll))
+	}
+}
+
+// Builds the channel.poll.begin or channel.poll.end event for the poll, depending on whether it ended
+func pollEvent(p database.Poll) models.PollEventSubEvent {
+	event := models.PollEventSubEvent{
+		ID:                   p.ID,
+		BroadcasterUserID:    p.BroadcasterID,
+		BroadcasterUserLogin: p.BroadcasterLogin,
+		BroadcasterUserName:  p.BroadcasterName,
+		Title:                p.Title,
+		Choices:              []models.PollEventSubEventChoice{},
+		BitsVoting:           models.PollEventSubEventGoodVoting{IsEnabled: p.BitsVotingEnabled, AmountPerVote: p.BitsPerVote},
+		ChannelPointsVoting:  models.PollEventSubEventGoodVoting{IsEnabled: p.ChannelPointsVotingEnabled, AmountPerVote: p.ChannelPointsPerVote},
+		StartedAt:            p.StartedAt,
+	}
+
+	ended := p.Status != "ACTIVE"
+	for _, c := range p.Choices {
+		choice := models.PollEventSubEventChoice{ID: c.ID, Title: c.Title}
+		if ended {
+			// Votes are only included once the poll ends
+			votes, bitsVotes, channelPointsVotes := c.Votes, c.BitsVotes, c.ChannelPointsVotes
+			choice.Votes, choice.BitsVotes, choice.ChannelPointsVotes = &votes, &bitsVotes, &channelPointsVotes
+		}
+		event.Choices = append(event.Choices, choice)
+	}
+
+	if ended {
+		event.Status = strings.ToLower(p.Status)
+		event.EndedAt = p.EndedAt
+	} else if startedAt, err := time.Parse(time.RFC3339, p.StartedAt); err == nil {
+		event.EndsAt = startedAt.Add(time.Duration(p.Duration) * time.Second).Format(time.RFC3339)
+	}
+
+	return event
 }
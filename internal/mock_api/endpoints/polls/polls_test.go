@@ -7,8 +7,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/models"
 	"github.com/twitchdev/twitch-cli/test_setup"
+	"github.com/twitchdev/twitch-cli/test_setup/test_eventsub"
 	"github.com/twitchdev/twitch-cli/test_setup/test_server"
 )
 
@@ -133,3 +137,74 @@ func TestPolls(t *testing.T) {
 	a.Nil(err, err)
 	a.Equal(400, resp.StatusCode)
 }
+
+func TestPollEvents(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+	ts := test_server.SetupTestServer(Polls{})
+	events := test_eventsub.Record(t)
+
+	// Polls are listed with their broadcaster, who has to exist
+	a.Nil(test_server.InsertTestUsers())
+
+	var polls struct {
+		Data []database.Poll `json:"data"`
+	}
+
+	// Creating a poll sends channel.poll.begin
+	b, _ := json.Marshal(PostPollsBody{
+		BroadcasterID: "1",
+		Title:         "Events",
+		Choices:       []PostPollsBodyChoice{{Title: "Yes"}, {Title: "No"}},
+		Duration:      300,
+	})
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+Polls{}.Path(), bytes.NewBuffer(b))
+	resp, err := http.DefaultClient.Do(req)
+	a.Nil(err)
+	a.Equal(200, resp.StatusCode)
+	a.Nil(json.NewDecoder(resp.Body).Decode(&polls))
+	a.Len(polls.Data, 1)
+	poll := polls.Data[0]
+
+	var event models.PollEventSubEvent
+	subscription := events.Next(&event)
+	a.Equal("channel.poll.begin", subscription.Type)
+	a.Equal("1", subscription.Version)
+	a.Equal("1", subscription.Condition.BroadcasterUserID)
+	a.Equal(poll.ID, event.ID)
+	a.Equal("Events", event.Title)
+	a.Empty(event.Status)
+	a.Len(event.Choices, 2)
+	a.Equal(poll.Choices[0].ID, event.Choices[0].ID)
+	a.Nil(event.Choices[0].Votes) // Votes are only included once the poll ends
+	startedAt, err := time.Parse(time.RFC3339, poll.StartedAt)
+	a.Nil(err)
+	a.Equal(startedAt.Add(300*time.Second).Format(time.RFC3339), event.EndsAt)
+
+	// Ending it sends channel.poll.end
+	b, _ = json.Marshal(PatchPollsBody{BroadcasterID: "1", ID: poll.ID, Status: "TERMINATED"})
+	req, _ = http.NewRequest(http.MethodPatch, ts.URL+Polls{}.Path(), bytes.NewBuffer(b))
+	resp, err = http.DefaultClient.Do(req)
+	a.Nil(err)
+	a.Equal(200, resp.StatusCode)
+	a.Nil(json.NewDecoder(resp.Body).Decode(&polls))
+	a.Len(polls.Data, 1)
+
+	event = models.PollEventSubEvent{}
+	subscription = events.Next(&event)
+	a.Equal("channel.poll.end", subscription.Type)
+	a.Equal("1", subscription.Condition.BroadcasterUserID)
+	a.Equal(poll.ID, event.ID)
+	a.Equal("terminated", event.Status)
+	a.Equal(polls.Data[0].EndedAt, event.EndedAt)
+	a.Empty(event.EndsAt)
+	a.Len(event.Choices, 2)
+	a.NotNil(event.Choices[0].Votes)
+
+	// Invalid requests don't send events
+	b, _ = json.Marshal(PatchPollsBody{BroadcasterID: "1", ID: poll.ID, Status: "potato"})
+	req, _ = http.NewRequest(http.MethodPatch, ts.URL+Polls{}.Path(), bytes.NewBuffer(b))
+	resp, err = http.DefaultClient.Do(req)
+	a.Nil(err)
+	a.Equal(400, resp.StatusCode)
+	events.Empty()
+}
@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_eventsub
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/internal/util"
+)
+
+// Events waiting to be sent, so endpoints don't wait on the EventSub server to deliver them
+var (
+	queue     chan queuedEvent
+	startOnce sync.Once
+)
+
+type queuedEvent struct {
+	body []byte
+	send Sender // Sender when the event was emitted
+}
+
+// Sender sends an event to the mock EventSub server.
+type Sender func(eventsubBody []byte) error
+
+// Sender of the events emitted next; Replaced with SetSender
+var (
+	send   Sender = sendToServer
+	muSend sync.Mutex
+)
+
+func sendToServer(eventsubBody []byte) error {
+	_, err := admin.Call(http.MethodPost, "/admin/events", admin.EventRequest{Event: eventsubBody}, nil)
+	return err
+}
+
+// SetSender replaces how events are sent, such as to capture the events endpoints emit in tests, and returns the previous
+// Sender. Events emitted before it's replaced are still sent with the previous Sender.
+func SetSender(s Sender) Sender {
+	muSend.Lock()
+	defer muSend.Unlock()
+
+	previous := send
+	send = s
+	return previous
+}
+
+// Emit sends an EventSub notification for a change made through the mock API to the subscriptions of the mock EventSub
+// server matching the topic, version, and condition. Events are sent in the order they're emitted, after the endpoint
+// responds, and are dropped if the server isn't running.
+func Emit(topic string, version string, condition models.EventsubCondition, event interface{}) {
+	body, err := json.Marshal(models.EventsubResponse{
+		Subscription: models.EventsubSubscription{
+			ID:        util.RandomGUID(),
+			Status:    "enabled",
+			Type:      topic,
+			Version:   version,
+			Condition: condition,
+			Transport: models.EventsubTransport{Method: models.TransportWebSocket},
+			CreatedAt: util.GetTimestamp().Format(time.RFC3339Nano),
+		},
+		Event: event,
+	})
+	if err != nil {
+		log.Printf("Error building [%v / %v] EventSub event: %v", topic, version, err)
+		return
+	}
+
+	startOnce.Do(func() {
+		queue = make(chan queuedEvent, 1000)
+		go func() {
+			for e := range queue {
+				if err := e.send(e.body); err != nil && !errors.Is(err, admin.ErrServerNotRunning) {
+					log.Printf("Could not send EventSub event to the mock EventSub server: %v", err)
+				}
+			}
+		}()
+	})
+
+	muSend.Lock()
+	e := queuedEvent{body: body, send: send}
+	muSend.Unlock()
+
+	select {
+	case queue <- e:
+	default:
+		log.Printf("Dropped [%v / %v] EventSub event; Too many events are waiting to be sent", topic, version)
+	}
+}
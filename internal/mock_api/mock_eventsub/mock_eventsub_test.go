@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_eventsub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/models"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestEmit(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	sent := make(chan []byte, 10)
+	previous := SetSender(func(eventsubBody []byte) error {
+		sent <- eventsubBody
+		return admin.ErrServerNotRunning
+	})
+	defer SetSender(previous)
+
+	Emit("channel.ban", "1", models.EventsubCondition{BroadcasterUserID: "1234"}, models.UnbanEventSubEvent{UserID: "1"})
+	Emit("channel.unban", "1", models.EventsubCondition{BroadcasterUserID: "1234"}, models.UnbanEventSubEvent{UserID: "2"})
+
+	// Events are sent in the order they're emitted
+	for _, expected := range []string{"channel.ban", "channel.unban"} {
+		select {
+		case body := <-sent:
+			var event struct {
+				Subscription models.EventsubSubscription `json:"subscription"`
+				Event        models.UnbanEventSubEvent   `json:"event"`
+			}
+			a.Nil(json.Unmarshal(body, &event))
+			a.Equal(expected, event.Subscription.Type)
+			a.Equal("1", event.Subscription.Version)
+			a.Equal("enabled", event.Subscription.Status)
+			a.Equal("1234", event.Subscription.Condition.BroadcasterUserID)
+			a.NotEmpty(event.Event.UserID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the event to be sent")
+		}
+	}
+}
@@ -15,7 +15,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/twitchdev/twitch-cli/internal/certs"
 	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/metrics"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/authentication"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/endpoints"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/generate"
@@ -28,7 +30,11 @@ const MOCK_NAMESPACE = "/mock"
 const UNITS_NAMESPACE = "/units"
 const AUTH_NAMESPACE = "/auth"
 
-func StartServer(port int) error {
+// Metrics served at /metrics, for monitoring long running tests against the server
+var metricsRegistry = metrics.NewRegistry()
+var httpMetrics = metrics.NewHTTPMetrics(metricsRegistry)
+
+func StartServer(port int, ssl bool) error {
 	m := http.NewServeMux()
 
 	ctx := context.Background()
@@ -50,6 +56,7 @@ func StartServer(port int) error {
 	ctx = context.WithValue(ctx, "db", db)
 
 	RegisterHandlers(m)
+	registerUIHandlers(m, db)
 	s := http.Server{
 		Addr:    fmt.Sprintf(":%v", port),
 		Handler: m,
@@ -57,15 +64,39 @@ func StartServer(port int) error {
 			return ctx
 		},
 	}
-	stop := make(chan os.Signal)
+
+	// Uses localhost.crt and localhost.key from the application directory, generating them if they're missing
+	var files certs.Files
+	if ssl {
+		files, err = certs.Localhost("")
+		if err != nil {
+			return fmt.Errorf("Error loading SSL certificate: %v", err.Error())
+		}
+		if files.Generated {
+			log.Printf("Generated an SSL certificate for localhost at %v", files.CertFile)
+		}
+		if files.CACert != "" {
+			log.Print(certs.TrustHint(files.CACert))
+		}
+	}
+
+	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 
 	var serverErr error = nil
 
 	go func() {
 		log.Print("Mock server started")
-
-		if err := s.ListenAndServe(); err != nil {
+		log.Printf("Prometheus metrics are served at /metrics")
+		log.Printf("Open the dashboard at /_ui")
+
+		var err error
+		if ssl {
+			err = s.ListenAndServeTLS(files.CertFile, files.KeyFile)
+		} else {
+			err = s.ListenAndServe()
+		}
+		if err != nil {
 			if err != http.ErrServerClosed {
 				serverErr = err
 				stop <- syscall.SIGINT // Simulate Ctrl+C
@@ -96,23 +127,30 @@ func RegisterHandlers(m *http.ServeMux) {
 	for _, e := range endpoints.All() {
 		// no auth requirements on this endpoint, so just add it manually
 		if e.Path() == "/schedule/icalendar" {
-			m.Handle(MOCK_NAMESPACE+e.Path(), loggerMiddleware(e))
+			handleWithMetrics(m, MOCK_NAMESPACE+e.Path(), loggerMiddleware(e))
 			continue
 		}
-		m.Handle(MOCK_NAMESPACE+e.Path(), loggerMiddleware(authentication.AuthenticationMiddleware(e)))
+		handleWithMetrics(m, MOCK_NAMESPACE+e.Path(), loggerMiddleware(authentication.AuthenticationMiddleware(e)))
 	}
 	for _, e := range mock_units.All() {
-		m.Handle(UNITS_NAMESPACE+e.Path(), loggerMiddleware(e))
+		handleWithMetrics(m, UNITS_NAMESPACE+e.Path(), loggerMiddleware(e))
 	}
 
 	for _, e := range mock_auth.All() {
-		m.Handle(AUTH_NAMESPACE+e.Path(), loggerMiddleware(e))
+		handleWithMetrics(m, AUTH_NAMESPACE+e.Path(), loggerMiddleware(e))
 	}
 
 	// For removed endpoints we don't have to worry about an actual handler, since its just gonna return 410 Gone
 	for e := range endpoints.Gone() {
-		m.Handle(MOCK_NAMESPACE+e, loggerMiddleware(nil))
+		handleWithMetrics(m, MOCK_NAMESPACE+e, loggerMiddleware(nil))
 	}
+
+	m.Handle("/metrics", metricsRegistry.Handler())
+}
+
+// Registers the handler for path, counting its requests and latency by path
+func handleWithMetrics(m *http.ServeMux, path string, h http.Handler) {
+	m.Handle(path, httpMetrics.Middleware(path, h))
 }
 
 func loggerMiddleware(next http.Handler) http.Handler {
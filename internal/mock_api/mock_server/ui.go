@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_server
+
+import (
+	"net/http"
+
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/ui"
+)
+
+// Registers the dashboard at /_ui, which browses the mock API's database
+func registerUIHandlers(m *http.ServeMux, db database.CLIDatabase) {
+	api := ui.NewAPI()
+	api.HandleDatabase(db)
+
+	m.Handle(ui.Path, ui.Handler(ui.Info{
+		Title:  "Twitch CLI - Mock API",
+		Panels: []string{ui.PanelTables, ui.PanelEvents},
+	}, api))
+}
@@ -37,6 +37,8 @@ var validScopesByTokenType = map[string]map[string]bool{
 		"channel:manage:schedule":           true,
 		"channel:manage:videos":             true,
 		"channel:manage:vips":               true,
+		"channel:moderate":                  true,
+		"channel:read:ads":                  true,
 		"channel:read:charity":              true,
 		"channel:read:editors":              true,
 		"channel:read:goals":                true,
@@ -58,11 +60,14 @@ var validScopesByTokenType = map[string]map[string]bool{
 		"moderator:manage:chat_settings":    true,
 		"moderator:manage:shoutouts":        true,
 		"moderator:manage:shield_mode":      true,
+		"moderator:manage:unban_requests":   true,
 		"moderator:read:automod_settings":   true,
 		"moderator:read:blocked_terms":      true,
 		"moderator:read:followers":          true,
 		"moderator:read:chatters":           true,
 		"moderator:read:shield_mode":        true,
+		"moderator:read:shoutouts":          true,
+		"moderator:read:unban_requests":     true,
 		"user:edit":                         true,
 		"user:edit:broadcast":               true,
 		"user:manage:blocked_users":         true,
@@ -81,6 +86,7 @@ func All() []AuthEndpoint {
 		AppAccessTokenEndpoint{},
 		UserTokenEndpoint{},
 		ValidateTokenEndpoint{},
+		RevokeEndpoint{},
 	}
 }
 
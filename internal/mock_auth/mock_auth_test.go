@@ -8,11 +8,14 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/twitchdev/twitch-cli/internal/admin"
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/util"
 	"github.com/twitchdev/twitch-cli/test_setup"
@@ -147,6 +150,57 @@ func TestAppAccessToken(t *testing.T) {
 	a.Equal(200, resp.StatusCode)
 }
 
+func TestRevoke(t *testing.T) {
+	a = test_setup.SetupTestEnv(t)
+	ts := httptest.NewServer(baseMiddleware(RevokeEndpoint{}))
+
+	notified := []admin.RevokeRequest{}
+	notifyEventSub = func(req admin.RevokeRequest) error {
+		notified = append(notified, req)
+		return admin.ErrServerNotRunning
+	}
+
+	post := func(params map[string]string) int {
+		q := url.Values{}
+		for k, v := range params {
+			q.Set(k, v)
+		}
+		resp, err := http.Post(ts.URL+RevokeEndpoint{}.Path(), "application/x-www-form-urlencoded", strings.NewReader(q.Encode()))
+		a.Nil(err)
+		return resp.StatusCode
+	}
+
+	db, err := database.NewConnection(true)
+	a.Nil(err, err)
+	defer db.DB.Close()
+	q := db.NewQuery(nil, 100)
+
+	// Make sure the client exists when this test runs alone
+	post(map[string]string{"client_id": ac.ID, "token": "none"})
+
+	auth, err := q.CreateAuthorization(database.Authorization{ClientID: ac.ID, UserID: "revoke_user", Scopes: "bits:read"})
+	a.Nil(err)
+	other, err := q.CreateAuthorization(database.Authorization{ClientID: ac.ID, UserID: "revoke_user", Scopes: "bits:read"})
+	a.Nil(err)
+
+	a.Equal(400, post(map[string]string{"token": auth.Token}))
+	a.Equal(400, post(map[string]string{"client_id": ac.ID}))
+	a.Equal(404, post(map[string]string{"client_id": "unknown", "token": auth.Token}))
+	a.Equal(400, post(map[string]string{"client_id": ac.ID, "token": "invalid"}))
+
+	a.Equal(200, post(map[string]string{"client_id": ac.ID, "token": auth.Token}))
+	revoked, err := q.GetAuthorizationByToken(auth.Token)
+	a.Nil(err)
+	a.Empty(revoked.Token)
+	a.Equal(admin.RevokeRequest{ClientID: ac.ID, Tokens: []string{auth.Token}}, notified[len(notified)-1])
+	a.Equal(400, post(map[string]string{"client_id": ac.ID, "token": auth.Token}))
+
+	// Revoking the client's authorization revokes every token of the user
+	a.Equal(200, post(map[string]string{"client_id": ac.ID, "user_id": "revoke_user"}))
+	a.Equal(admin.RevokeRequest{ClientID: ac.ID, UserID: "revoke_user", Tokens: []string{other.Token}}, notified[len(notified)-1])
+	a.Equal(404, post(map[string]string{"client_id": ac.ID, "user_id": "revoke_user"}))
+}
+
 func baseMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := context.Background()
@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package mock_auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_errors"
+)
+
+// Revokes a token, as https://id.twitch.tv/oauth2/revoke does, or every authorization a user gave a client, as when the
+// user disconnects the client from their account. The mock EventSub server is told about the revocation, so it can
+// revoke the subscriptions that relied on the authorizations.
+type RevokeEndpoint struct{}
+
+// Tells the mock EventSub server about revoked authorizations; Replaced in tests
+var notifyEventSub = func(req admin.RevokeRequest) error {
+	_, err := admin.Call(http.MethodPost, "/admin/authorizations/revoke", req, nil)
+	return err
+}
+
+func (e RevokeEndpoint) Path() string { return "/revoke" }
+
+func (e RevokeEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	db = r.Context().Value("db").(database.CLIDatabase)
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Accepts query parameters, and form bodies like production
+	clientID := r.FormValue("client_id")
+	token := r.FormValue("token")
+	userID := r.FormValue("user_id")
+
+	if clientID == "" {
+		mock_errors.WriteBadRequest(w, "missing client id")
+		return
+	}
+	if (token == "") == (userID == "") {
+		mock_errors.WriteBadRequest(w, "Either token or user_id is required")
+		return
+	}
+
+	res, err := db.NewQuery(r, 10).GetAuthenticationClient(database.AuthenticationClient{ID: clientID})
+	if err != nil {
+		mock_errors.WriteServerError(w, err.Error())
+		return
+	}
+	if len(res.Data.([]database.AuthenticationClient)) == 0 {
+		mock_errors.WriteNotFound(w, "client does not exist")
+		return
+	}
+
+	revoked := admin.RevokeRequest{ClientID: clientID, Tokens: []string{}}
+	if token != "" {
+		auth, err := db.NewQuery(r, 100).GetAuthorizationByToken(token)
+		if err != nil {
+			mock_errors.WriteServerError(w, err.Error())
+			return
+		}
+		if auth.Token == "" || auth.ClientID != clientID {
+			mock_errors.WriteBadRequest(w, "Invalid token")
+			return
+		}
+
+		if _, err := db.NewQuery(r, 100).RevokeAuthorization(token); err != nil {
+			mock_errors.WriteServerError(w, err.Error())
+			return
+		}
+		revoked.Tokens = append(revoked.Tokens, token)
+	} else {
+		auths, err := db.NewQuery(r, 100).RevokeUserAuthorizations(clientID, userID)
+		if err != nil {
+			mock_errors.WriteServerError(w, err.Error())
+			return
+		}
+		if len(auths) == 0 {
+			mock_errors.WriteNotFound(w, "The user has not authorized the client")
+			return
+		}
+
+		revoked.UserID = userID
+		for _, a := range auths {
+			revoked.Tokens = append(revoked.Tokens, a.Token)
+		}
+	}
+
+	// The revocation succeeds whether or not the EventSub server is running
+	if err := notifyEventSub(revoked); err != nil && !errors.Is(err, admin.ErrServerNotRunning) {
+		log.Printf("Could not revoke EventSub subscriptions of the revoked authorizations: %v", err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package models
+
+type ConduitShardDisabledEventTransport struct {
+	Method         string `json:"method"`
+	Callback       string `json:"callback,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
+	ConnectedAt    string `json:"connected_at,omitempty"`
+	DisconnectedAt string `json:"disconnected_at,omitempty"`
+}
+
+type ConduitShardDisabledEvent struct {
+	ConduitID string                             `json:"conduit_id"`
+	ShardID   string                             `json:"shard_id"`
+	Status    string                             `json:"status"`
+	Transport ConduitShardDisabledEventTransport `json:"transport"`
+}
+
+type ConduitShardDisabledEventSubResponse struct {
+	Subscription EventsubSubscription       `json:"subscription"`
+	Event        *ConduitShardDisabledEvent `json:"event,omitempty"`
+}
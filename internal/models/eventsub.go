@@ -17,6 +17,7 @@ type EventsubTransport struct {
 	Method    string `json:"method"`
 	Callback  string `json:"callback,omitempty"`
 	SessionID string `json:"session_id,omitempty"`
+	ConduitID string `json:"conduit_id,omitempty"`
 }
 
 type EventsubCondition struct {
@@ -0,0 +1,138 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/database"
+)
+
+// Rows of the events cache and of tables returned at most per request
+const maxRows = 500
+
+// API routes the dashboard's requests to the handlers of the server's panels. Responses use the admin API's format.
+type API struct {
+	handlers map[string]map[string]http.HandlerFunc // By name, then method
+}
+
+func NewAPI() *API {
+	return &API{handlers: make(map[string]map[string]http.HandlerFunc)}
+}
+
+// Handle registers a handler for the given method and name, which is the path under APIPath, such as "sessions".
+func (a *API) Handle(method string, name string, handler http.HandlerFunc) {
+	if a.handlers[name] == nil {
+		a.handlers[name] = make(map[string]http.HandlerFunc)
+	}
+	a.handlers[name][method] = handler
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, APIPath)
+	methods, ok := a.handlers[name]
+	if !ok {
+		admin.WriteError(w, http.StatusNotFound, fmt.Sprintf("%v does not exist", r.URL.Path))
+		return
+	}
+	handler, ok := methods[r.Method]
+	if !ok {
+		allowed := []string{}
+		for m := range methods {
+			allowed = append(allowed, m)
+		}
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%v does not support %v", r.URL.Path, r.Method))
+		return
+	}
+	handler(w, r)
+}
+
+// HandleDatabase registers the handlers of the events and tables panels, which browse the CLI's database.
+func (a *API) HandleDatabase(db database.CLIDatabase) {
+	a.Handle(http.MethodGet, "events", eventsHandler(db))
+	a.Handle(http.MethodGet, "tables", tablesHandler(db))
+	a.Handle(http.MethodGet, "tables/rows", tableRowsHandler(db))
+}
+
+// GET /_ui/api/events
+// Takes the event, transport, search, and limit query parameters
+func eventsHandler(db database.CLIDatabase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		events, err := db.NewQuery(nil, maxRows).GetEvents(database.EventCacheFilter{
+			Event:     query.Get("event"),
+			Transport: query.Get("transport"),
+			Search:    query.Get("search"),
+			Limit:     limitParam(r),
+		})
+		if err != nil {
+			admin.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		admin.WriteJSON(w, "", events)
+	}
+}
+
+// GET /_ui/api/tables
+func tablesHandler(db database.CLIDatabase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tables, err := db.NewQuery(nil, maxRows).GetTables()
+		if err != nil {
+			admin.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		admin.WriteJSON(w, "", tables)
+	}
+}
+
+// GET /_ui/api/tables/rows
+// Takes the table, limit, and offset query parameters
+func tableRowsHandler(db database.CLIDatabase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		table := r.URL.Query().Get("table")
+		if table == "" {
+			admin.WriteError(w, http.StatusBadRequest, "Missing required parameter table")
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset < 0 {
+			offset = 0
+		}
+
+		q := db.NewQuery(nil, maxRows)
+		tables, err := q.GetTables()
+		if err != nil {
+			admin.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		found := false
+		for _, t := range tables {
+			found = found || t.Name == table
+		}
+		if !found {
+			admin.WriteError(w, http.StatusNotFound, fmt.Sprintf("Table [%v] does not exist", table))
+			return
+		}
+
+		rows, err := q.GetTableRows(table, limitParam(r), offset)
+		if err != nil {
+			admin.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		admin.WriteJSON(w, "", rows)
+	}
+}
+
+// Returns the limit query parameter, capped at maxRows
+func limitParam(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > maxRows {
+		return maxRows
+	}
+	return limit
+}
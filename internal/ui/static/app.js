@@ -0,0 +1,286 @@
+"use strict";
+
+const POLL_INTERVAL = 2000;
+const PAGE_SIZE = 50;
+
+const loaders = {
+  sessions: loadSessions,
+  subscriptions: loadSubscriptions,
+  trigger: loadTrigger,
+  events: loadEvents,
+  tables: loadTables,
+};
+const polled = ["sessions", "subscriptions"];
+
+let activePanel = "";
+let triggerLoaded = false;
+let tablePage = { table: "", offset: 0, total: 0 };
+
+// Calls the server's dashboard API. Requests with a body are sent as JSON.
+async function api(path, body) {
+  const options = body === undefined ? {} : {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(body),
+  };
+  const res = await fetch("api/" + path, options);
+  const json = await res.json().catch(() => ({}));
+  if (!res.ok) {
+    throw new Error(json.message || res.statusText);
+  }
+  return json;
+}
+
+// Creates an element. Children are elements or text, which is never parsed as HTML.
+function el(tag, attrs, ...children) {
+  const e = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (k.startsWith("on")) {
+      e.addEventListener(k.slice(2), v);
+    } else {
+      e.setAttribute(k, v);
+    }
+  }
+  for (const c of children) {
+    e.append(c === null || c === undefined ? "" : c);
+  }
+  return e;
+}
+
+function showError(err) {
+  const e = document.getElementById("error");
+  e.textContent = err ? err.message : "";
+  e.hidden = !err;
+}
+
+function fillTable(tbody, rows, columns, emptyText) {
+  tbody.replaceChildren();
+  if (rows.length === 0) {
+    tbody.append(el("tr", {}, el("td", { colspan: columns, class: "empty" }, emptyText)));
+    return;
+  }
+  for (const row of rows) {
+    tbody.append(row);
+  }
+}
+
+function showPanel(name) {
+  if (!loaders[name] || !document.querySelector(`#tabs [data-panel="${name}"]`)) {
+    return;
+  }
+  activePanel = name;
+  for (const p of document.querySelectorAll(".panel")) {
+    p.hidden = p.id !== name;
+  }
+  for (const b of document.querySelectorAll("#tabs button")) {
+    b.classList.toggle("active", b.dataset.panel === name);
+  }
+  refresh();
+}
+
+async function refresh() {
+  try {
+    await loaders[activePanel]();
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function loadSessions() {
+  const { data } = await api("sessions");
+  const rows = (data || []).map((s) => el("tr", {},
+    el("td", {}, s.id),
+    el("td", {}, s.connected_at),
+    el("td", {}, s.keepalive_enabled ? `every ${s.keepalive_timeout_seconds}s` : "disabled"),
+    el("td", {}, String(s.subscription_count)),
+    el("td", {}, Object.entries(s.messages_sent || {}).map(([t, n]) => `${t}: ${n}`).join(", ")),
+  ));
+  fillTable(document.querySelector("#sessions tbody"), rows, 5, "No sessions are connected.");
+}
+
+async function loadSubscriptions() {
+  const { data } = await api("subscriptions");
+  const rows = (data || []).map((s) => el("tr", {},
+    el("td", {}, s.id),
+    el("td", {}, s.type),
+    el("td", {}, s.version),
+    el("td", {}, s.status),
+    el("td", {}, [s.transport.method, s.transport.session_id || s.transport.callback || s.transport.conduit_id].filter(Boolean).join(" ")),
+    el("td", { class: "wrap" }, JSON.stringify(s.condition)),
+    el("td", {}, s.created_at),
+  ));
+  fillTable(document.querySelector("#subscriptions tbody"), rows, 7, "No subscriptions.");
+}
+
+async function loadTrigger() {
+  if (triggerLoaded) {
+    return;
+  }
+  const { data } = await api("trigger");
+  const form = document.getElementById("trigger-form");
+  const select = form.elements.event;
+  for (const e of data.events) {
+    select.append(el("option", { value: e.topic }, e.topic));
+  }
+
+  const fields = document.getElementById("trigger-flags");
+  for (const f of data.flags) {
+    const name = `flag-${f.name}`;
+    let input;
+    if (f.type === "bool") {
+      input = el("input", { type: "checkbox", name });
+      if (f.default === "true") {
+        input.checked = true;
+      }
+    } else if (f.name === "version") {
+      input = el("select", { name });
+    } else {
+      input = el("input", { name, placeholder: f.default || "" });
+    }
+    fields.append(el("label", {}, `--${f.name}`, " ", input, el("small", {}, f.usage)));
+  }
+
+  // The version field lists the selected event's versions
+  const updateVersions = () => {
+    const versionSelect = form.elements["flag-version"];
+    if (!versionSelect) {
+      return;
+    }
+    const event = data.events.find((e) => e.topic === select.value);
+    versionSelect.replaceChildren(...(event ? event.versions : []).map((v) => el("option", { value: v }, v)));
+  };
+  select.addEventListener("change", updateVersions);
+  updateVersions();
+
+  form.addEventListener("submit", async (ev) => {
+    ev.preventDefault();
+    const flags = {};
+    for (const f of data.flags) {
+      const input = form.elements[`flag-${f.name}`];
+      flags[f.name] = input.type === "checkbox" ? String(input.checked) : input.value;
+    }
+    const result = document.getElementById("trigger-result");
+    try {
+      const res = await api("trigger", { args: [select.value], flags });
+      result.textContent = res.message;
+      showError(null);
+    } catch (err) {
+      result.textContent = err.message;
+    }
+    result.hidden = false;
+  });
+
+  triggerLoaded = true;
+}
+
+async function loadEvents() {
+  const form = document.getElementById("events-form");
+  const params = new URLSearchParams({ limit: "100" });
+  for (const name of ["event", "transport", "search"]) {
+    if (form.elements[name].value) {
+      params.set(name, form.elements[name].value);
+    }
+  }
+  const { data } = await api("events?" + params);
+  const pre = document.getElementById("events-json");
+  pre.hidden = true;
+  const rows = (data || []).map((e) => {
+    const row = el("tr", { class: "clickable" },
+      el("td", {}, e.id),
+      el("td", {}, e.event),
+      el("td", {}, e.transport),
+      el("td", {}, e.from_user),
+      el("td", {}, e.to_user),
+      el("td", {}, e.timestamp),
+    );
+    row.addEventListener("click", () => {
+      for (const r of row.parentNode.children) {
+        r.classList.toggle("selected", r === row);
+      }
+      try {
+        pre.textContent = JSON.stringify(JSON.parse(e.json), null, 2);
+      } catch {
+        pre.textContent = e.json;
+      }
+      pre.hidden = false;
+    });
+    return row;
+  });
+  fillTable(document.querySelector("#events tbody"), rows, 6, "No events match.");
+}
+
+async function loadTables() {
+  const { data } = await api("tables");
+  const list = document.getElementById("table-list");
+  list.replaceChildren(...data.map((t) => el("li", {},
+    el("button", { type: "button", "data-table": t.name, onclick: () => loadTableRows(t.name, 0) }, `${t.name} (${t.rows})`))));
+  if (tablePage.table) {
+    await loadTableRows(tablePage.table, tablePage.offset);
+  }
+}
+
+async function loadTableRows(table, offset) {
+  const params = new URLSearchParams({ table, limit: String(PAGE_SIZE), offset: String(offset) });
+  const { data } = await api("tables/rows?" + params);
+  tablePage = { table, offset, total: data.total };
+
+  for (const b of document.querySelectorAll("#table-list button")) {
+    b.classList.toggle("active", b.dataset.table === table);
+  }
+
+  const container = document.getElementById("table-rows");
+  container.querySelector("h3").textContent = table;
+  container.querySelector("thead").replaceChildren(el("tr", {}, ...data.columns.map((c) => el("th", {}, c))));
+  const rows = data.rows.map((r) => el("tr", {}, ...r.map((v) => el("td", { class: "wrap" }, v === null ? "NULL" : String(v)))));
+  fillTable(container.querySelector("tbody"), rows, data.columns.length, "The table is empty.");
+
+  const last = Math.min(offset + PAGE_SIZE, data.total);
+  container.querySelector(".pager span").textContent = data.total === 0 ? "" : `${offset + 1}-${last} of ${data.total}`;
+  container.querySelector('[data-page="-1"]').disabled = offset === 0;
+  container.querySelector('[data-page="1"]').disabled = last >= data.total;
+  container.hidden = false;
+}
+
+async function init() {
+  document.getElementById("events-form").addEventListener("submit", (ev) => {
+    ev.preventDefault();
+    refresh();
+  });
+  for (const b of document.querySelectorAll("#table-rows .pager button")) {
+    b.addEventListener("click", async () => {
+      try {
+        await loadTableRows(tablePage.table, Math.max(0, tablePage.offset + Number(b.dataset.page) * PAGE_SIZE));
+      } catch (err) {
+        showError(err);
+      }
+    });
+  }
+
+  let info;
+  try {
+    info = (await api("info")).data;
+  } catch (err) {
+    showError(err);
+    return;
+  }
+  document.title = info.title;
+  document.getElementById("title").textContent = info.title;
+
+  const tabs = document.getElementById("tabs");
+  for (const panel of info.panels) {
+    const label = panel.charAt(0).toUpperCase() + panel.slice(1);
+    tabs.append(el("button", { type: "button", "data-panel": panel, onclick: () => { location.hash = panel; } }, label));
+  }
+
+  window.addEventListener("hashchange", () => showPanel(location.hash.slice(1)));
+  showPanel(info.panels.includes(location.hash.slice(1)) ? location.hash.slice(1) : info.panels[0]);
+
+  setInterval(() => {
+    if (polled.includes(activePanel) && !document.hidden) {
+      refresh();
+    }
+  }, POLL_INTERVAL);
+}
+
+init();
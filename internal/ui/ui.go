@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ui serves the mock servers' dashboard at /_ui, an embedded web page for using the mock servers without the
+// CLI. The page calls the server's JSON API under /_ui/api, which each server implements for the panels it shows.
+package ui
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/twitchdev/twitch-cli/internal/admin"
+)
+
+//go:embed static
+var static embed.FS
+
+const (
+	Path    = "/_ui/"
+	APIPath = "/_ui/api/"
+)
+
+// Panels of the dashboard. Each server shows the panels it implements the API for.
+const (
+	PanelSessions      = "sessions"      // GET /_ui/api/sessions
+	PanelSubscriptions = "subscriptions" // GET /_ui/api/subscriptions
+	PanelTrigger       = "trigger"       // GET /_ui/api/trigger, POST /_ui/api/trigger
+	PanelEvents        = "events"        // GET /_ui/api/events
+	PanelTables        = "tables"        // GET /_ui/api/tables, GET /_ui/api/tables/rows
+)
+
+// Info describes the server to the dashboard. It's served at GET /_ui/api/info.
+type Info struct {
+	Title  string   `json:"title"`
+	Panels []string `json:"panels"`
+}
+
+// Flag is a command line flag, shown as a field of a form.
+type Flag struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"` // pflag's type name, such as string, bool, or int
+	Default   string `json:"default"`
+	Usage     string `json:"usage"`
+}
+
+// FormRequest is the body of requests submitting a form built from flags, such as POST /_ui/api/trigger.
+type FormRequest struct {
+	Args  []string          `json:"args"`  // Positional arguments, such as the event to trigger
+	Flags map[string]string `json:"flags"` // Values of the flags, by name
+}
+
+// Handler serves the dashboard, and the API registered on api. It should be registered at Path. As with the admin API,
+// only requests from the local host are served, whatever address the server binds to. Requests other than GET must be
+// JSON, so other sites can't submit forms to the API.
+func Handler(info Info, api *API) http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	fileServer := http.StripPrefix(Path, http.FileServer(http.FS(files)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopback(r) {
+			admin.WriteError(w, http.StatusForbidden, "The dashboard is only available from the local host")
+			return
+		}
+
+		if !strings.HasPrefix(r.URL.Path, APIPath) {
+			fileServer.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if mediaType != "application/json" {
+				admin.WriteError(w, http.StatusUnsupportedMediaType, "Requests must have a JSON body")
+				return
+			}
+		}
+
+		if r.URL.Path == APIPath+"info" {
+			admin.WriteJSON(w, "", info)
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+}
+
+// Returns whether the request was sent from a loopback address
+func isLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Flags lists the flags of fs, sorted by name, except for help.
+func Flags(fs *pflag.FlagSet) []Flag {
+	flags := []Flag{}
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" {
+			return
+		}
+		flags = append(flags, Flag{
+			Name:      f.Name,
+			Shorthand: f.Shorthand,
+			Type:      f.Value.Type(),
+			Default:   f.DefValue,
+			Usage:     f.Usage,
+		})
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// SetFlags sets the flags of fs to the values submitted with a form. Empty values leave flags at their defaults.
+func SetFlags(fs *pflag.FlagSet, values map[string]string) error {
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("Unknown flag --%v", name)
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("Invalid value for --%v: %v", name, err)
+		}
+	}
+	return nil
+}
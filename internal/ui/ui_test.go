@@ -0,0 +1,155 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/twitchdev/twitch-cli/internal/admin"
+	"github.com/twitchdev/twitch-cli/internal/database"
+	"github.com/twitchdev/twitch-cli/internal/util"
+	"github.com/twitchdev/twitch-cli/test_setup"
+)
+
+func TestHandler(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	api := NewAPI()
+	api.Handle(http.MethodGet, "things", func(w http.ResponseWriter, r *http.Request) {
+		admin.WriteJSON(w, "", []string{"a"})
+	})
+	api.Handle(http.MethodPost, "things", func(w http.ResponseWriter, r *http.Request) {
+		admin.WriteJSON(w, "created", nil)
+	})
+	h := Handler(Info{Title: "Test", Panels: []string{PanelSessions}}, api)
+
+	serve := func(method string, path string, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+		req.RemoteAddr = "127.0.0.1:5000"
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The page and its assets are embedded
+	for _, path := range []string{Path, Path + "app.js", Path + "style.css"} {
+		rec := serve(http.MethodGet, path, "")
+		a.Equal(http.StatusOK, rec.Code, path)
+	}
+	a.Contains(serve(http.MethodGet, Path, "").Body.String(), "<script src=\"app.js\">")
+
+	var info struct {
+		Data Info `json:"data"`
+	}
+	rec := serve(http.MethodGet, APIPath+"info", "")
+	a.Equal(http.StatusOK, rec.Code)
+	a.Nil(json.Unmarshal(rec.Body.Bytes(), &info))
+	a.Equal("Test", info.Data.Title)
+	a.Equal([]string{PanelSessions}, info.Data.Panels)
+
+	a.Equal(http.StatusOK, serve(http.MethodGet, APIPath+"things", "").Code)
+	a.Equal(http.StatusOK, serve(http.MethodPost, APIPath+"things", "application/json; charset=utf-8").Code)
+	a.Equal(http.StatusNotFound, serve(http.MethodGet, APIPath+"other", "").Code)
+	a.Equal(http.StatusMethodNotAllowed, serve(http.MethodDelete, APIPath+"things", "application/json").Code)
+
+	// Forms posted by other sites aren't JSON
+	a.Equal(http.StatusUnsupportedMediaType, serve(http.MethodPost, APIPath+"things", "application/x-www-form-urlencoded").Code)
+	a.Equal(http.StatusUnsupportedMediaType, serve(http.MethodPost, APIPath+"things", "").Code)
+
+	// Only the local host is served, whatever address the server binds to
+	for remoteAddr, code := range map[string]int{"[::1]:5000": http.StatusOK, "192.0.2.1:5000": http.StatusForbidden, "10.0.0.1:5000": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodGet, Path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		a.Equal(code, rec.Code, remoteAddr)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	var to string
+	var count int
+	var anonymous bool
+	fs := pflag.NewFlagSet("trigger", pflag.ContinueOnError)
+	fs.StringVarP(&to, "to-user", "t", "", "User ID of the receiver of the event.")
+	fs.IntVar(&count, "count", 1, "Number of times to send the event.")
+	fs.BoolVar(&anonymous, "anonymous", false, "Denotes if the event is anonymous.")
+
+	flags := Flags(fs)
+	a.Len(flags, 3)
+	a.Equal(Flag{Name: "anonymous", Type: "bool", Default: "false", Usage: "Denotes if the event is anonymous."}, flags[0])
+	a.Equal("count", flags[1].Name)
+	a.Equal("1", flags[1].Default)
+	a.Equal("t", flags[2].Shorthand)
+
+	a.Nil(SetFlags(fs, map[string]string{"to-user": "1234", "count": "", "anonymous": "true"}))
+	a.Equal("1234", to)
+	a.Equal(1, count)
+	a.True(anonymous)
+
+	a.NotNil(SetFlags(fs, map[string]string{"count": "many"}))
+	a.NotNil(SetFlags(fs, map[string]string{"unknown": "1"}))
+}
+
+func TestDatabaseHandlers(t *testing.T) {
+	a := test_setup.SetupTestEnv(t)
+
+	db, err := database.NewConnection(true)
+	a.Nil(err)
+	defer db.DB.Close()
+
+	api := NewAPI()
+	api.HandleDatabase(db)
+	h := Handler(Info{}, api)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "127.0.0.1:5000"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var tables struct {
+		Data []database.Table `json:"data"`
+	}
+	rec := get(APIPath + "tables")
+	a.Equal(http.StatusOK, rec.Code)
+	a.Nil(json.Unmarshal(rec.Body.Bytes(), &tables))
+	a.NotEmpty(tables.Data)
+
+	var rows struct {
+		Data database.TableRows `json:"data"`
+	}
+	rec = get(APIPath + "tables/rows?table=events&limit=5")
+	a.Equal(http.StatusOK, rec.Code)
+	a.Nil(json.Unmarshal(rec.Body.Bytes(), &rows))
+	a.Equal("events", rows.Data.Table)
+	a.Contains(rows.Data.Columns, "json")
+	a.LessOrEqual(len(rows.Data.Rows), 5)
+
+	// Client secrets aren't served by the dashboard, which has no authentication
+	_, err = db.NewQuery(nil, 100).InsertOrUpdateAuthenticationClient(database.AuthenticationClient{ID: util.RandomClientID(), Name: "ui_test", Secret: "uitestsecret"}, false)
+	a.Nil(err)
+	clients, err := db.NewQuery(nil, 100).GetTableRows("clients", 1, 0)
+	a.Nil(err)
+	rec = get(APIPath + fmt.Sprintf("tables/rows?table=clients&offset=%v", clients.Total-1))
+	a.Equal(http.StatusOK, rec.Code)
+	a.NotContains(rec.Body.String(), "uitestsecret")
+	a.Contains(rec.Body.String(), "[redacted]")
+
+	a.Equal(http.StatusNotFound, get(APIPath+"tables/rows?table=missing").Code)
+	a.Equal(http.StatusBadRequest, get(APIPath+"tables/rows").Code)
+	a.Equal(http.StatusOK, get(APIPath+"events?transport=websocket&limit=10").Code)
+}
@@ -2,9 +2,58 @@
 // SPDX-License-Identifier: Apache-2.0
 package util
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var relativeSecondsRegex = regexp.MustCompile("^[0-9]+$")
+var relativeShorthandRegex = regexp.MustCompile("^(?:(?P<Days>[0-9]+)[dD])?(?:(?P<Hours>[0-9]+)[hH])?(?:(?P<Minutes>[0-9]+)[mM])?(?:(?P<Seconds>[0-9]+)[sS])?$")
 
 // GetTimestamp returns the timestamp in UTC for use with signature creation and event firing.
 func GetTimestamp() time.Time {
 	return time.Now().UTC()
 }
+
+// ParseRelativeDuration parses a relative amount of time, given either in seconds (600) or with shorthands (10d4h12m55s).
+func ParseRelativeDuration(value string) (time.Duration, error) {
+	if relativeSecondsRegex.MatchString(value) {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	if value == "" || !relativeShorthandRegex.MatchString(value) {
+		return 0, fmt.Errorf("invalid relative time '%v'; use seconds (600) or shorthands (10d4h12m55s)", value)
+	}
+
+	// Can include or exclude any of the shorthands, but they have to be in the same order as above
+	values := relativeShorthandRegex.FindStringSubmatch(value)
+	days, _ := strconv.Atoi(values[relativeShorthandRegex.SubexpIndex("Days")])
+	hours, _ := strconv.Atoi(values[relativeShorthandRegex.SubexpIndex("Hours")])
+	minutes, _ := strconv.Atoi(values[relativeShorthandRegex.SubexpIndex("Minutes")])
+	seconds, _ := strconv.Atoi(values[relativeShorthandRegex.SubexpIndex("Seconds")])
+
+	return time.Duration(days*24)*time.Hour +
+		time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second, nil
+}
+
+// ParseTimestampOrRelative parses either an RFC3339 timestamp, or a relative time (see ParseRelativeDuration) that is counted backwards from now.
+func ParseTimestampOrRelative(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	d, err := ParseRelativeDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%v'; use an RFC3339 timestamp or a relative time (600, 600s, 10d4h12m55s)", value)
+	}
+
+	return GetTimestamp().Add(-d), nil
+}
@@ -4,6 +4,7 @@ package util
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,3 +15,40 @@ func TestGetTimestamp(t *testing.T) {
 	ts := GetTimestamp()
 	a.NotNil(ts)
 }
+
+func TestParseRelativeDuration(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := ParseRelativeDuration("600")
+	a.Nil(err)
+	a.Equal(600*time.Second, d)
+
+	d, err = ParseRelativeDuration("1d2h3m4s")
+	a.Nil(err)
+	a.Equal(26*time.Hour+3*time.Minute+4*time.Second, d)
+
+	d, err = ParseRelativeDuration("30d")
+	a.Nil(err)
+	a.Equal(30*24*time.Hour, d)
+
+	_, err = ParseRelativeDuration("")
+	a.NotNil(err)
+
+	_, err = ParseRelativeDuration("4s3m")
+	a.NotNil(err)
+}
+
+func TestParseTimestampOrRelative(t *testing.T) {
+	a := assert.New(t)
+
+	ts, err := ParseTimestampOrRelative("2023-04-13T14:34:23Z")
+	a.Nil(err)
+	a.Equal(time.Date(2023, 4, 13, 14, 34, 23, 0, time.UTC), ts)
+
+	ts, err = ParseTimestampOrRelative("1h")
+	a.Nil(err)
+	a.WithinDuration(GetTimestamp().Add(-time.Hour), ts, time.Minute)
+
+	_, err = ParseTimestampOrRelative("yesterday")
+	a.NotNil(err)
+}
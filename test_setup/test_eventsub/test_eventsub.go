@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+package test_eventsub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/twitchdev/twitch-cli/internal/mock_api/mock_eventsub"
+	"github.com/twitchdev/twitch-cli/internal/models"
+)
+
+// Recorder captures the EventSub events emitted by mock API endpoints, instead of sending them to the mock EventSub server.
+type Recorder struct {
+	t      *testing.T
+	events chan []byte
+}
+
+// Record captures the events emitted by mock API endpoints until the test ends.
+func Record(t *testing.T) *Recorder {
+	r := &Recorder{t: t, events: make(chan []byte, 100)}
+	previous := mock_eventsub.SetSender(func(eventsubBody []byte) error {
+		r.events <- eventsubBody
+		return nil
+	})
+	t.Cleanup(func() { mock_eventsub.SetSender(previous) })
+	return r
+}
+
+// Next returns the subscription of the next event emitted, and decodes its event object into event. Fails the test if no
+// event is emitted within 5 seconds.
+func (r *Recorder) Next(event interface{}) models.EventsubSubscription {
+	r.t.Helper()
+
+	select {
+	case body := <-r.events:
+		var e struct {
+			Subscription models.EventsubSubscription `json:"subscription"`
+			Event        json.RawMessage             `json:"event"`
+		}
+		if err := json.Unmarshal(body, &e); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := json.Unmarshal(e.Event, event); err != nil {
+			r.t.Fatal(err)
+		}
+		return e.Subscription
+	case <-time.After(5 * time.Second):
+		r.t.Fatal("Timed out waiting for an EventSub event to be emitted")
+		return models.EventsubSubscription{}
+	}
+}
+
+// Empty fails the test if any event was emitted that wasn't returned by Next.
+func (r *Recorder) Empty() {
+	r.t.Helper()
+
+	select {
+	case body := <-r.events:
+		r.t.Fatalf("Unexpected EventSub event emitted: %s", body)
+	default:
+	}
+}
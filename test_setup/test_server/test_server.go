@@ -7,10 +7,12 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/twitchdev/twitch-cli/internal/database"
 	"github.com/twitchdev/twitch-cli/internal/mock_api"
 	"github.com/twitchdev/twitch-cli/internal/mock_api/authentication"
+	"github.com/twitchdev/twitch-cli/internal/util"
 )
 
 func SetupTestServer(next mock_api.MockEndpoint) *httptest.Server {
@@ -64,3 +66,35 @@ func SetupTestServer(next mock_api.MockEndpoint) *httptest.Server {
 	}))
 	return ts
 }
+
+// InsertTestUsers adds the token's user, 1, and user 2 to the database when they don't exist, for endpoints that look up
+// the users they change.
+func InsertTestUsers() error {
+	db, err := database.NewConnection(true)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	q := db.NewQuery(nil, 100)
+	for _, u := range []database.User{{ID: "1", UserLogin: "testing_user1"}, {ID: "2", UserLogin: "second_user"}} {
+		dbr, err := q.GetUsers(database.User{ID: u.ID})
+		if err != nil {
+			return err
+		}
+		if len(dbr.Data.([]database.User)) > 0 {
+			continue
+		}
+
+		u.DisplayName = u.UserLogin
+		u.BroadcasterType = "partner"
+		u.UserType = "testing"
+		u.CreatedAt = util.GetTimestamp().Format(time.RFC3339)
+		u.ModifiedAt = u.CreatedAt
+		u.Language = "en"
+		if err := q.InsertUser(u, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}